@@ -0,0 +1,92 @@
+/* Copyright © 2026 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package v1alpha1
+
+import (
+	"os"
+	"path/filepath"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	configv1alpha1 "k8s.io/component-base/config/v1alpha1"
+)
+
+const (
+	DefaultMetricsBindAddress     = "0"
+	DefaultHealthProbeBindAddress = ":8081"
+	DefaultWebhookPort            = 9443
+	DefaultLeaderElectionID       = "antrea-operator.antrea.vmware.com"
+
+	DefaultWatchNamespace    = "antrea-operator"
+	DefaultAntreaInstallName = "antrea-install"
+	DefaultManifestDir       = "antrea-manifest"
+
+	DefaultResyncPeriod      = 2 * time.Minute
+	DefaultProgressTimeout   = 10 * time.Minute
+	DefaultReconcileDeadline = 5 * time.Minute
+)
+
+// DefaultManifestCacheDir returns the default directory of the manifest cache, in the temporary
+// directory, which is not writable in a container with a read-only root filesystem.
+func DefaultManifestCacheDir() string {
+	return filepath.Join(os.TempDir(), "antrea-manifests")
+}
+
+// Default sets the fields which are not set to their default values.
+func (c *OperatorConfiguration) Default() {
+	if c.Metrics.BindAddress == "" {
+		c.Metrics.BindAddress = DefaultMetricsBindAddress
+	}
+	if c.Health.HealthProbeBindAddress == "" {
+		c.Health.HealthProbeBindAddress = DefaultHealthProbeBindAddress
+	}
+	if c.Webhook.Port == nil {
+		port := DefaultWebhookPort
+		c.Webhook.Port = &port
+	}
+	if c.LeaderElection == nil {
+		c.LeaderElection = &configv1alpha1.LeaderElectionConfiguration{}
+	}
+	if c.LeaderElection.LeaderElect == nil {
+		leaderElect := false
+		c.LeaderElection.LeaderElect = &leaderElect
+	}
+	if c.LeaderElection.ResourceName == "" {
+		c.LeaderElection.ResourceName = DefaultLeaderElectionID
+	}
+
+	// The logger used to be always created in development mode, keep it as the default.
+	if c.Logging.Level == "" {
+		c.Logging.Level = LogLevelDebug
+	}
+	if c.Logging.Format == "" {
+		c.Logging.Format = LogFormatConsole
+	}
+	if c.Logging.Development == nil {
+		development := true
+		c.Logging.Development = &development
+	}
+
+	if c.WatchNamespace == "" {
+		c.WatchNamespace = DefaultWatchNamespace
+	}
+	if c.AntreaInstallName == "" {
+		c.AntreaInstallName = DefaultAntreaInstallName
+	}
+	if c.ManifestDir == "" {
+		c.ManifestDir = DefaultManifestDir
+	}
+	if c.ManifestCacheDir == "" {
+		c.ManifestCacheDir = DefaultManifestCacheDir()
+	}
+	if c.ResyncPeriod == nil {
+		c.ResyncPeriod = &metav1.Duration{Duration: DefaultResyncPeriod}
+	}
+	if c.ProgressTimeout == nil {
+		c.ProgressTimeout = &metav1.Duration{Duration: DefaultProgressTimeout}
+	}
+	if c.ReconcileDeadline == nil {
+		c.ReconcileDeadline = &metav1.Duration{Duration: DefaultReconcileDeadline}
+	}
+}
@@ -0,0 +1,76 @@
+/* Copyright © 2026 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	cfg "sigs.k8s.io/controller-runtime/pkg/config/v1alpha1"
+)
+
+const (
+	LogFormatJSON    = "json"
+	LogFormatConsole = "console"
+
+	LogLevelDebug = "debug"
+	LogLevelInfo  = "info"
+	LogLevelError = "error"
+)
+
+// LoggingConfiguration configures the operator logger.
+type LoggingConfiguration struct {
+	// Level is the minimum level of the logged messages: debug, info, error, or an integer
+	// verbosity greater than 0 which enables the debug messages up to that verbosity.
+	Level string `json:"level,omitempty"`
+
+	// Format is the log encoding, json or console.
+	Format string `json:"format,omitempty"`
+
+	// Development enables the zap development mode, which logs stack traces on warnings and
+	// panics on DPanic.
+	Development *bool `json:"development,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// OperatorConfiguration is the Schema for the operator configuration file, which is loaded with
+// the --config flag.
+type OperatorConfiguration struct {
+	metav1.TypeMeta `json:",inline"`
+
+	// ControllerManagerConfigurationSpec holds the generic controller-runtime manager
+	// configurations, e.g. the metrics and health probe addresses and leader election.
+	cfg.ControllerManagerConfigurationSpec `json:",inline"`
+
+	// Logging configures the operator logger.
+	Logging LoggingConfiguration `json:"logging,omitempty"`
+
+	// WatchNamespace is the namespace watched for the AntreaInstall CR.
+	WatchNamespace string `json:"watchNamespace,omitempty"`
+
+	// AntreaInstallName is the name of the AntreaInstall CR reconciled by the operator.
+	AntreaInstallName string `json:"antreaInstallName,omitempty"`
+
+	// ManifestDir is the directory which holds the Antrea manifest templates, in one subdirectory
+	// per bundled Antrea version, such as v1.14.1.
+	ManifestDir string `json:"manifestDir,omitempty"`
+
+	// ManifestCacheDir is the writable directory in which the verified templates of
+	// spec.manifestSource are cached, such as an emptyDir volume. It is created if needed.
+	ManifestCacheDir string `json:"manifestCacheDir,omitempty"`
+
+	// ResyncPeriod is the interval at which the Antrea Pods are checked for the rollout status.
+	ResyncPeriod *metav1.Duration `json:"resyncPeriod,omitempty"`
+
+	// ProgressTimeout is how long a rollout may not make any progress before the operator is
+	// marked as Degraded.
+	ProgressTimeout *metav1.Duration `json:"progressTimeout,omitempty"`
+
+	// ReconcileDeadline is how long a single reconciliation may run before the operator is
+	// reported as unhealthy.
+	ReconcileDeadline *metav1.Duration `json:"reconcileDeadline,omitempty"`
+}
+
+func init() {
+	SchemeBuilder.Register(&OperatorConfiguration{})
+}
@@ -0,0 +1,68 @@
+/* Copyright © 2026 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package v1alpha1
+
+import (
+	"strconv"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+// Validate checks a defaulted configuration and returns all the invalid fields.
+func (c *OperatorConfiguration) Validate() error {
+	var allErrs field.ErrorList
+
+	loggingPath := field.NewPath("logging")
+	switch c.Logging.Level {
+	case LogLevelDebug, LogLevelInfo, LogLevelError:
+	default:
+		if v, err := strconv.Atoi(c.Logging.Level); err != nil || v <= 0 {
+			allErrs = append(allErrs, field.Invalid(loggingPath.Child("level"), c.Logging.Level, "must be debug, info, error or an integer verbosity greater than 0"))
+		}
+	}
+	switch c.Logging.Format {
+	case LogFormatJSON, LogFormatConsole:
+	default:
+		allErrs = append(allErrs, field.NotSupported(loggingPath.Child("format"), c.Logging.Format, []string{LogFormatJSON, LogFormatConsole}))
+	}
+
+	for _, msg := range validation.IsDNS1123Label(c.WatchNamespace) {
+		allErrs = append(allErrs, field.Invalid(field.NewPath("watchNamespace"), c.WatchNamespace, msg))
+	}
+	for _, msg := range validation.IsDNS1123Subdomain(c.AntreaInstallName) {
+		allErrs = append(allErrs, field.Invalid(field.NewPath("antreaInstallName"), c.AntreaInstallName, msg))
+	}
+	if c.ManifestDir == "" {
+		allErrs = append(allErrs, field.Required(field.NewPath("manifestDir"), ""))
+	}
+	if c.ManifestCacheDir == "" {
+		allErrs = append(allErrs, field.Required(field.NewPath("manifestCacheDir"), ""))
+	}
+
+	for _, d := range []struct {
+		name     string
+		duration *metav1.Duration
+	}{
+		{"resyncPeriod", c.ResyncPeriod},
+		{"progressTimeout", c.ProgressTimeout},
+		{"reconcileDeadline", c.ReconcileDeadline},
+	} {
+		if d.duration == nil || d.duration.Duration <= 0 {
+			allErrs = append(allErrs, field.Invalid(field.NewPath(d.name), d.duration, "must be greater than 0"))
+		}
+	}
+
+	if c.Webhook.Port != nil {
+		for _, msg := range validation.IsValidPortNum(*c.Webhook.Port) {
+			allErrs = append(allErrs, field.Invalid(field.NewPath("webhook", "port"), *c.Webhook.Port, msg))
+		}
+	}
+	if c.LeaderElection != nil && c.LeaderElection.ResourceName == "" {
+		allErrs = append(allErrs, field.Required(field.NewPath("leaderElection", "resourceName"), ""))
+	}
+
+	return allErrs.ToAggregate()
+}
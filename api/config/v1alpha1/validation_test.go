@@ -0,0 +1,99 @@
+/* Copyright © 2026 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package v1alpha1
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	configv1alpha1 "k8s.io/component-base/config/v1alpha1"
+)
+
+func TestDefault(t *testing.T) {
+	g := NewGomegaWithT(t)
+	c := &OperatorConfiguration{}
+	c.Default()
+	g.Expect(c.Metrics.BindAddress).Should(Equal(DefaultMetricsBindAddress))
+	g.Expect(c.Health.HealthProbeBindAddress).Should(Equal(DefaultHealthProbeBindAddress))
+	g.Expect(*c.Webhook.Port).Should(Equal(DefaultWebhookPort))
+	g.Expect(*c.LeaderElection.LeaderElect).Should(BeFalse())
+	g.Expect(c.LeaderElection.ResourceName).Should(Equal(DefaultLeaderElectionID))
+	g.Expect(c.Logging.Level).Should(Equal(LogLevelDebug))
+	g.Expect(c.Logging.Format).Should(Equal(LogFormatConsole))
+	g.Expect(*c.Logging.Development).Should(BeTrue())
+	g.Expect(c.WatchNamespace).Should(Equal(DefaultWatchNamespace))
+	g.Expect(c.AntreaInstallName).Should(Equal(DefaultAntreaInstallName))
+	g.Expect(c.ManifestDir).Should(Equal(DefaultManifestDir))
+	g.Expect(c.ManifestCacheDir).Should(Equal(DefaultManifestCacheDir()))
+	g.Expect(c.ResyncPeriod.Duration).Should(Equal(DefaultResyncPeriod))
+	g.Expect(c.ProgressTimeout.Duration).Should(Equal(DefaultProgressTimeout))
+	g.Expect(c.ReconcileDeadline.Duration).Should(Equal(DefaultReconcileDeadline))
+	g.Expect(c.Validate()).Should(Succeed())
+
+	// The fields which are set are kept.
+	leaderElect, development, port := true, false, 8443
+	c = &OperatorConfiguration{
+		Logging:           LoggingConfiguration{Level: LogLevelError, Format: LogFormatJSON, Development: &development},
+		WatchNamespace:    "kube-system",
+		AntreaInstallName: "antrea",
+		ManifestDir:       "/manifests",
+		ManifestCacheDir:  "/var/cache/antrea-operator/manifests",
+		ResyncPeriod:      &metav1.Duration{Duration: time.Minute},
+	}
+	c.Webhook.Port = &port
+	c.LeaderElection = &configv1alpha1.LeaderElectionConfiguration{LeaderElect: &leaderElect, ResourceName: "lock"}
+	c.Default()
+	g.Expect(*c.Webhook.Port).Should(Equal(8443))
+	g.Expect(*c.LeaderElection.LeaderElect).Should(BeTrue())
+	g.Expect(c.LeaderElection.ResourceName).Should(Equal("lock"))
+	g.Expect(c.Logging).Should(Equal(LoggingConfiguration{Level: LogLevelError, Format: LogFormatJSON, Development: &development}))
+	g.Expect(c.WatchNamespace).Should(Equal("kube-system"))
+	g.Expect(c.AntreaInstallName).Should(Equal("antrea"))
+	g.Expect(c.ManifestDir).Should(Equal("/manifests"))
+	g.Expect(c.ManifestCacheDir).Should(Equal("/var/cache/antrea-operator/manifests"))
+	g.Expect(c.ResyncPeriod.Duration).Should(Equal(time.Minute))
+}
+
+func TestValidate(t *testing.T) {
+	g := NewGomegaWithT(t)
+	for _, tc := range []struct {
+		name        string
+		modify      func(c *OperatorConfiguration)
+		expectedErr string
+	}{
+		{name: "defaults", modify: func(c *OperatorConfiguration) {}},
+		{name: "verbosity", modify: func(c *OperatorConfiguration) { c.Logging.Level = "4" }},
+		{name: "invalid level", modify: func(c *OperatorConfiguration) { c.Logging.Level = "trace" }, expectedErr: "logging.level"},
+		{name: "zero verbosity", modify: func(c *OperatorConfiguration) { c.Logging.Level = "0" }, expectedErr: "logging.level"},
+		{name: "invalid format", modify: func(c *OperatorConfiguration) { c.Logging.Format = "text" }, expectedErr: "logging.format"},
+		{name: "invalid namespace", modify: func(c *OperatorConfiguration) { c.WatchNamespace = "Antrea_Operator" }, expectedErr: "watchNamespace"},
+		{name: "invalid CR name", modify: func(c *OperatorConfiguration) { c.AntreaInstallName = "antrea install" }, expectedErr: "antreaInstallName"},
+		{name: "no manifest directory", modify: func(c *OperatorConfiguration) { c.ManifestDir = "" }, expectedErr: "manifestDir"},
+		{name: "no manifest cache directory", modify: func(c *OperatorConfiguration) { c.ManifestCacheDir = "" }, expectedErr: "manifestCacheDir"},
+		{name: "zero resync period", modify: func(c *OperatorConfiguration) { c.ResyncPeriod.Duration = 0 }, expectedErr: "resyncPeriod"},
+		{name: "negative progress timeout", modify: func(c *OperatorConfiguration) { c.ProgressTimeout.Duration = -time.Minute }, expectedErr: "progressTimeout"},
+		{name: "no reconcile deadline", modify: func(c *OperatorConfiguration) { c.ReconcileDeadline = nil }, expectedErr: "reconcileDeadline"},
+		{name: "invalid webhook port", modify: func(c *OperatorConfiguration) { *c.Webhook.Port = 70000 }, expectedErr: "webhook.port"},
+		{name: "no leader election ID", modify: func(c *OperatorConfiguration) { c.LeaderElection.ResourceName = "" }, expectedErr: "leaderElection.resourceName"},
+	} {
+		c := &OperatorConfiguration{}
+		c.Default()
+		tc.modify(c)
+		err := c.Validate()
+		if tc.expectedErr == "" {
+			g.Expect(err).ShouldNot(HaveOccurred(), tc.name)
+			continue
+		}
+		g.Expect(err).Should(MatchError(ContainSubstring(tc.expectedErr)), tc.name)
+	}
+
+	// All the invalid fields are reported.
+	c := &OperatorConfiguration{}
+	c.Default()
+	c.Logging.Format = "text"
+	c.ManifestDir = ""
+	g.Expect(c.Validate()).Should(MatchError(And(ContainSubstring("logging.format"), ContainSubstring("manifestDir"))))
+}
@@ -6,6 +6,7 @@ package v1
 import (
 	configv1 "github.com/openshift/api/config/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
 )
 
 // EDIT THIS FILE!  THIS IS SCAFFOLDING FOR YOU TO OWN!
@@ -31,15 +32,192 @@ type AntreaInstallSpec struct {
 	// +required
 	AntreaControllerConfig string `json:"antreaControllerConfig"`
 
-	// AntreaPlatform is the platform on which antrea will be deployed.
+	// AntreaPlatform is the platform on which antrea will be deployed: openshift, kubernetes,
+	// or auto to detect it from the cluster.
 	// +operator-sdk:csv:customresourcedefinitions:type=spec
-	// +required
-	AntreaPlatform string `json:"antreaPlatform"`
+	// +kubebuilder:default=auto
+	// +optional
+	AntreaPlatform string `json:"antreaPlatform,omitempty"`
+
+	// Profile selects the defaults of a Kubernetes distribution: the CNI directories, the traffic
+	// mode, the MTU and the required feature gates. With auto, the profile of the detected
+	// distribution is used.
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +kubebuilder:validation:Enum=auto;generic;k3s;rke2;microk8s;eks;aks;gke;openshift
+	// +kubebuilder:default=auto
+	// +optional
+	Profile string `json:"profile,omitempty"`
 
 	// AntreaImage is the Docker image name used by antrea-agent and antrea-controller.
 	// +operator-sdk:csv:customresourcedefinitions:type=spec
 	// +optional
 	AntreaImage string `json:"antreaImage,omitempty"`
+
+	// AntreaVersion is the Antrea release to install, such as v1.14.1. It selects the bundled
+	// manifests of that release and the tag of AntreaImage. When unset, the latest bundled
+	// manifests are used with AntreaImage as is.
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +kubebuilder:validation:Pattern=`^v[0-9]+\.[0-9]+\.[0-9]+$`
+	// +optional
+	AntreaVersion string `json:"antreaVersion,omitempty"`
+
+	// AllowDowngrade allows changing AntreaVersion of a running installation to the previous minor
+	// version, to roll back an upgrade.
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +optional
+	AllowDowngrade bool `json:"allowDowngrade,omitempty"`
+
+	// DefaultMTU is the MTU of the Pod network: a number, or auto to derive it from the uplink MTU
+	// of the Nodes minus the overhead of the traffic encapsulation and encryption. It overrides
+	// defaultMTU of AntreaAgentConfig.
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +kubebuilder:validation:XIntOrString
+	// +optional
+	DefaultMTU *intstr.IntOrString `json:"defaultMTU,omitempty"`
+
+	// Traffic configures how antrea-agent forwards the Pod traffic. It overrides the matching
+	// options of AntreaAgentConfig.
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +optional
+	Traffic *TrafficSpec `json:"traffic,omitempty"`
+
+	// CNIChaining chains Antrea after the primary CNI of the cluster, in networkPolicyOnly mode.
+	// When unset, it is enabled by the eks and aks profiles.
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +optional
+	CNIChaining *CNIChainingSpec `json:"cniChaining,omitempty"`
+
+	// DryRun makes the operator fill, validate and render the configurations without applying
+	// them or restarting the Antrea Pods. The changes applying them would make are published in
+	// status.preview.
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +optional
+	DryRun bool `json:"dryRun,omitempty"`
+
+	// ApprovalPolicy is Automatic to apply the configuration changes as soon as they are
+	// validated, or Manual to wait until the revision of a change, published in
+	// status.preview.revision, is approved with the operator.antrea.vmware.com/approved-revision
+	// annotation. The initial installation is not gated.
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +kubebuilder:validation:Enum=Automatic;Manual
+	// +kubebuilder:default=Automatic
+	// +optional
+	ApprovalPolicy string `json:"approvalPolicy,omitempty"`
+
+	// ApprovalTTL is how long the approval of a revision is valid with the Manual approval policy,
+	// from the time the operator observes it, 24h by default. A revision which is not applied
+	// before its approval expires, such as a change deferred to a maintenance window, must be
+	// approved again.
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +optional
+	ApprovalTTL *metav1.Duration `json:"approvalTTL,omitempty"`
+
+	// MaintenanceWindows defers the disruptive changes of a running installation, which restart
+	// antrea-agent or change the Antrea image, to the next maintenance window. The other objects
+	// are applied right away. The operator.antrea.vmware.com/apply-now annotation applies a
+	// deferred change at once.
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +optional
+	MaintenanceWindows []MaintenanceWindow `json:"maintenanceWindows,omitempty"`
+
+	// ManifestSource loads the Antrea manifest templates from a ConfigMap, a mounted directory or
+	// an OCI artifact instead of the ones bundled with the operator.
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +optional
+	ManifestSource *ManifestSource `json:"manifestSource,omitempty"`
+}
+
+// ManifestSource is a source of Antrea manifest templates, verified by their digest. Exactly one
+// of ConfigMap, Directory and OCI must be set.
+type ManifestSource struct {
+	// ConfigMap is the name of a ConfigMap in the namespace of the CR, whose keys are the names of
+	// the templates.
+	// +optional
+	ConfigMap string `json:"configMap,omitempty"`
+
+	// Directory is a directory mounted in the operator container, which holds the templates.
+	// +optional
+	Directory string `json:"directory,omitempty"`
+
+	// OCI is an OCI artifact holding the templates.
+	// +optional
+	OCI *OCIManifestSource `json:"oci,omitempty"`
+
+	// Digest is the SHA-256 digest of the templates, "sha256:" followed by 64 hexadecimal digits,
+	// as printed by the manifest-digest subcommand of the operator.
+	// +kubebuilder:validation:Pattern=`^sha256:[a-f0-9]{64}$`
+	Digest string `json:"digest"`
+}
+
+// OCIManifestSource is an OCI artifact in a registry which does not require authentication.
+type OCIManifestSource struct {
+	// Reference is the artifact reference, e.g. registry.local:5000/antrea/manifests:v1.11.0 or
+	// registry.local:5000/antrea/manifests@sha256:<digest>.
+	Reference string `json:"reference"`
+
+	// PlainHTTP pulls the artifact over HTTP instead of HTTPS.
+	// +optional
+	PlainHTTP bool `json:"plainHTTP,omitempty"`
+}
+
+// MaintenanceWindow is a recurring time range in which the disruptive changes are applied.
+type MaintenanceWindow struct {
+	// Schedule is the cron expression of the starts of the window: minute, hour, day of month,
+	// month and day of week, e.g. "0 2 * * SAT" for 2am on Saturdays.
+	Schedule string `json:"schedule"`
+
+	// Duration is how long the window stays open after each start, e.g. 2h.
+	Duration metav1.Duration `json:"duration"`
+
+	// TimeZone is the IANA time zone of the schedule, e.g. Europe/Paris. UTC is used when it is
+	// empty.
+	// +optional
+	TimeZone string `json:"timeZone,omitempty"`
+}
+
+// CNIChainingSpec configures the chaining of Antrea after the primary CNI of the cluster.
+type CNIChainingSpec struct {
+	// Enabled appends the antrea plugin to the conflist of the primary CNI on each Node, and sets
+	// the networkPolicyOnly traffic mode. When it is disabled, the antrea plugin is removed from
+	// the conflist.
+	Enabled bool `json:"enabled"`
+
+	// PrimaryConfList is the file name of the conflist of the primary CNI in the CNI configuration
+	// directory. When empty, the first conflist of the directory is used, as the container
+	// runtime does.
+	// +optional
+	PrimaryConfList string `json:"primaryConfList,omitempty"`
+}
+
+// TrafficSpec holds the traffic options of antrea-agent, which are validated together.
+type TrafficSpec struct {
+	// EncapMode is the traffic encapsulation mode: encap, noEncap, hybrid or networkPolicyOnly.
+	// noEncap requires the Pod CIDRs to be routable by the Node network, and networkPolicyOnly
+	// requires Antrea to be chained to the primary CNI of the cluster.
+	// +kubebuilder:validation:Enum=encap;noEncap;hybrid;networkPolicyOnly
+	// +optional
+	EncapMode string `json:"encapMode,omitempty"`
+
+	// TunnelType is the tunnel protocol used in the encap and hybrid modes.
+	// +kubebuilder:validation:Enum=geneve;vxlan;gre;stt
+	// +optional
+	TunnelType string `json:"tunnelType,omitempty"`
+
+	// NoSNAT disables the SNAT of the Pod traffic to the external network. It is only supported
+	// in the noEncap mode.
+	// +optional
+	NoSNAT *bool `json:"noSNAT,omitempty"`
+
+	// TransportInterface is the name of the Node interface used for the tunnels and the routing
+	// of the Pod traffic.
+	// +optional
+	TransportInterface string `json:"transportInterface,omitempty"`
+
+	// AllowMigration allows changing the encapsulation mode or the tunnel type of a running
+	// installation. Such a change disrupts the Pod traffic between the Nodes until all the
+	// antrea-agents have restarted, so the Nodes should be drained first.
+	// +optional
+	AllowMigration bool `json:"allowMigration,omitempty"`
 }
 
 // AntreaInstallStatus defines the observed state of AntreaInstall
@@ -51,6 +229,178 @@ type AntreaInstallStatus struct {
 	// +operator-sdk:csv:customresourcedefinitions:type=status
 	// +optional
 	Conditions []InstallCondition `json:"conditions,omitempty"`
+
+	// EffectiveConfig describes the configurations Antrea is actually running with, after the
+	// operator has filled in the defaults.
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	// +optional
+	EffectiveConfig *EffectiveConfig `json:"effectiveConfig,omitempty"`
+
+	// Platform describes the platform Antrea is deployed on.
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	// +optional
+	Platform *PlatformStatus `json:"platform,omitempty"`
+
+	// ClusterNetwork describes the cluster network discovered on the kubernetes platform.
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	// +optional
+	ClusterNetwork *ClusterNetworkStatus `json:"clusterNetwork,omitempty"`
+
+	// Preview describes the changes applying the configurations would make, when spec.dryRun
+	// is set or when a change is waiting for approval.
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	// +optional
+	Preview *PreviewStatus `json:"preview,omitempty"`
+
+	// ScheduledChange describes the disruptive change held until the next maintenance window.
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	// +optional
+	ScheduledChange *ScheduledChangeStatus `json:"scheduledChange,omitempty"`
+}
+
+// ScheduledChangeStatus describes a disruptive change held until a maintenance window.
+type ScheduledChangeStatus struct {
+	// Revision identifies the filled configurations of the change.
+	// +optional
+	Revision string `json:"revision,omitempty"`
+
+	// ScheduledTime is the start of the maintenance window the change will be applied in.
+	// +optional
+	ScheduledTime metav1.Time `json:"scheduledTime,omitempty"`
+
+	// Reason tells why the change is disruptive.
+	// +optional
+	Reason string `json:"reason,omitempty"`
+}
+
+// PreviewStatus describes the changes the operator would make to the cluster. The objects are
+// named as Kind namespace/name.
+type PreviewStatus struct {
+	// Revision identifies the filled configurations of the change. It is the value of the
+	// approval annotation which approves the change.
+	// +optional
+	Revision string `json:"revision,omitempty"`
+
+	// ObservedGeneration is the generation of the AntreaInstall the preview was computed for.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// Created is the list of the objects which would be created.
+	// +optional
+	Created []string `json:"created,omitempty"`
+
+	// Updated is the list of the objects which would be updated.
+	// +optional
+	Updated []string `json:"updated,omitempty"`
+
+	// Deleted is the list of the objects of a previous installation which would be deleted.
+	// +optional
+	Deleted []string `json:"deleted,omitempty"`
+
+	// AgentRestart tells whether the antrea-agent Pods would be restarted.
+	// +optional
+	AgentRestart bool `json:"agentRestart,omitempty"`
+
+	// ControllerRestart tells whether the antrea-controller Pods would be restarted.
+	// +optional
+	ControllerRestart bool `json:"controllerRestart,omitempty"`
+
+	// ImageChange tells whether the Antrea Pods would be rolled out with a new image.
+	// +optional
+	ImageChange bool `json:"imageChange,omitempty"`
+
+	// Refused is the reason the operator would refuse to apply the configurations, if any.
+	// +optional
+	Refused string `json:"refused,omitempty"`
+}
+
+// ClusterNetworkStatus describes the cluster network discovered by the operator, and where
+// each part of it was found: kubeadm-config, static-pod-args, servicecidr-api or node-podcidrs.
+type ClusterNetworkStatus struct {
+	// ServiceNetwork is the list of the service CIDRs.
+	// +optional
+	ServiceNetwork []string `json:"serviceNetwork,omitempty"`
+
+	// ServiceNetworkSource is where the service CIDRs were found.
+	// +optional
+	ServiceNetworkSource string `json:"serviceNetworkSource,omitempty"`
+
+	// ClusterNetwork is the list of the Pod CIDRs.
+	// +optional
+	ClusterNetwork []string `json:"clusterNetwork,omitempty"`
+
+	// ClusterNetworkSource is where the Pod CIDRs were found.
+	// +optional
+	ClusterNetworkSource string `json:"clusterNetworkSource,omitempty"`
+
+	// NodeIPAM is the component allocating the Pod CIDRs of the Nodes, kube-controller-manager
+	// or antrea.
+	// +optional
+	NodeIPAM string `json:"nodeIPAM,omitempty"`
+}
+
+// PlatformStatus describes the platform selected by the operator.
+type PlatformStatus struct {
+	// Name is the platform in use, e.g. openshift or kubernetes.
+	// +optional
+	Name string `json:"name,omitempty"`
+
+	// Distribution is the detected Kubernetes distribution, e.g. k3s, rke2, eks, aks or gke,
+	// or generic if none was recognized.
+	// +optional
+	Distribution string `json:"distribution,omitempty"`
+
+	// Detected tells whether the platform was detected automatically.
+	// +optional
+	Detected bool `json:"detected,omitempty"`
+
+	// Profile is the distribution profile in use.
+	// +optional
+	Profile string `json:"profile,omitempty"`
+}
+
+// EffectiveConfig describes the effective (defaulted) configurations applied by the operator.
+type EffectiveConfig struct {
+	// ConfigMapName is the name of the read-only ConfigMap in the operator namespace which holds
+	// the effective antrea-agent, antrea-controller and CNI configurations.
+	// +optional
+	ConfigMapName string `json:"configMapName,omitempty"`
+
+	// AntreaImage is the resolved image used by antrea-agent and antrea-controller.
+	// +optional
+	AntreaImage string `json:"antreaImage,omitempty"`
+
+	// AntreaAgentConfigHash is the SHA-256 hash of the effective antrea-agent configuration.
+	// +optional
+	AntreaAgentConfigHash string `json:"antreaAgentConfigHash,omitempty"`
+
+	// AntreaCNIConfigHash is the SHA-256 hash of the effective CNI configuration.
+	// +optional
+	AntreaCNIConfigHash string `json:"antreaCNIConfigHash,omitempty"`
+
+	// AntreaControllerConfigHash is the SHA-256 hash of the effective antrea-controller configuration.
+	// +optional
+	AntreaControllerConfigHash string `json:"antreaControllerConfigHash,omitempty"`
+
+	// DefaultMTU is the MTU of the Pod network Antrea is configured with.
+	// +optional
+	DefaultMTU int `json:"defaultMTU,omitempty"`
+
+	// ServiceCIDR and ServiceCIDRv6 are the service CIDRs antrea-agent is configured with, from
+	// AntreaAgentConfig or the cluster network.
+	// +optional
+	ServiceCIDR string `json:"serviceCIDR,omitempty"`
+	// +optional
+	ServiceCIDRv6 string `json:"serviceCIDRv6,omitempty"`
+
+	// TrafficEncapMode is the traffic encapsulation mode of antrea-agent, from spec.traffic,
+	// AntreaAgentConfig or the profile.
+	// +optional
+	TrafficEncapMode string `json:"trafficEncapMode,omitempty"`
+
+	// NodeIPAM tells whether antrea-controller allocates the Pod CIDRs of the Nodes.
+	// +optional
+	NodeIPAM bool `json:"nodeIPAM,omitempty"`
 }
 
 // +kubebuilder:object:generate=false
@@ -10,7 +10,9 @@ package v1
 
 import (
 	configv1 "github.com/openshift/api/config/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
 )
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
@@ -18,7 +20,7 @@ func (in *AntreaInstall) DeepCopyInto(out *AntreaInstall) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	out.Spec = in.Spec
+	in.Spec.DeepCopyInto(&out.Spec)
 	in.Status.DeepCopyInto(&out.Status)
 }
 
@@ -75,6 +77,36 @@ func (in *AntreaInstallList) DeepCopyObject() runtime.Object {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AntreaInstallSpec) DeepCopyInto(out *AntreaInstallSpec) {
 	*out = *in
+	if in.DefaultMTU != nil {
+		in, out := &in.DefaultMTU, &out.DefaultMTU
+		*out = new(intstr.IntOrString)
+		**out = **in
+	}
+	if in.Traffic != nil {
+		in, out := &in.Traffic, &out.Traffic
+		*out = new(TrafficSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.CNIChaining != nil {
+		in, out := &in.CNIChaining, &out.CNIChaining
+		*out = new(CNIChainingSpec)
+		**out = **in
+	}
+	if in.ApprovalTTL != nil {
+		in, out := &in.ApprovalTTL, &out.ApprovalTTL
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.MaintenanceWindows != nil {
+		in, out := &in.MaintenanceWindows, &out.MaintenanceWindows
+		*out = make([]MaintenanceWindow, len(*in))
+		copy(*out, *in)
+	}
+	if in.ManifestSource != nil {
+		in, out := &in.ManifestSource, &out.ManifestSource
+		*out = new(ManifestSource)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AntreaInstallSpec.
@@ -97,6 +129,31 @@ func (in *AntreaInstallStatus) DeepCopyInto(out *AntreaInstallStatus) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.EffectiveConfig != nil {
+		in, out := &in.EffectiveConfig, &out.EffectiveConfig
+		*out = new(EffectiveConfig)
+		**out = **in
+	}
+	if in.Platform != nil {
+		in, out := &in.Platform, &out.Platform
+		*out = new(PlatformStatus)
+		**out = **in
+	}
+	if in.ClusterNetwork != nil {
+		in, out := &in.ClusterNetwork, &out.ClusterNetwork
+		*out = new(ClusterNetworkStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Preview != nil {
+		in, out := &in.Preview, &out.Preview
+		*out = new(PreviewStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ScheduledChange != nil {
+		in, out := &in.ScheduledChange, &out.ScheduledChange
+		*out = new(ScheduledChangeStatus)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AntreaInstallStatus.
@@ -108,3 +165,190 @@ func (in *AntreaInstallStatus) DeepCopy() *AntreaInstallStatus {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CNIChainingSpec) DeepCopyInto(out *CNIChainingSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CNIChainingSpec.
+func (in *CNIChainingSpec) DeepCopy() *CNIChainingSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(CNIChainingSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterNetworkStatus) DeepCopyInto(out *ClusterNetworkStatus) {
+	*out = *in
+	if in.ServiceNetwork != nil {
+		in, out := &in.ServiceNetwork, &out.ServiceNetwork
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ClusterNetwork != nil {
+		in, out := &in.ClusterNetwork, &out.ClusterNetwork
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterNetworkStatus.
+func (in *ClusterNetworkStatus) DeepCopy() *ClusterNetworkStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterNetworkStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EffectiveConfig) DeepCopyInto(out *EffectiveConfig) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EffectiveConfig.
+func (in *EffectiveConfig) DeepCopy() *EffectiveConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(EffectiveConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MaintenanceWindow) DeepCopyInto(out *MaintenanceWindow) {
+	*out = *in
+	out.Duration = in.Duration
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MaintenanceWindow.
+func (in *MaintenanceWindow) DeepCopy() *MaintenanceWindow {
+	if in == nil {
+		return nil
+	}
+	out := new(MaintenanceWindow)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ManifestSource) DeepCopyInto(out *ManifestSource) {
+	*out = *in
+	if in.OCI != nil {
+		in, out := &in.OCI, &out.OCI
+		*out = new(OCIManifestSource)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ManifestSource.
+func (in *ManifestSource) DeepCopy() *ManifestSource {
+	if in == nil {
+		return nil
+	}
+	out := new(ManifestSource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OCIManifestSource) DeepCopyInto(out *OCIManifestSource) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OCIManifestSource.
+func (in *OCIManifestSource) DeepCopy() *OCIManifestSource {
+	if in == nil {
+		return nil
+	}
+	out := new(OCIManifestSource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PlatformStatus) DeepCopyInto(out *PlatformStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PlatformStatus.
+func (in *PlatformStatus) DeepCopy() *PlatformStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(PlatformStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PreviewStatus) DeepCopyInto(out *PreviewStatus) {
+	*out = *in
+	if in.Created != nil {
+		in, out := &in.Created, &out.Created
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Updated != nil {
+		in, out := &in.Updated, &out.Updated
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Deleted != nil {
+		in, out := &in.Deleted, &out.Deleted
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PreviewStatus.
+func (in *PreviewStatus) DeepCopy() *PreviewStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(PreviewStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ScheduledChangeStatus) DeepCopyInto(out *ScheduledChangeStatus) {
+	*out = *in
+	in.ScheduledTime.DeepCopyInto(&out.ScheduledTime)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ScheduledChangeStatus.
+func (in *ScheduledChangeStatus) DeepCopy() *ScheduledChangeStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ScheduledChangeStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TrafficSpec) DeepCopyInto(out *TrafficSpec) {
+	*out = *in
+	if in.NoSNAT != nil {
+		in, out := &in.NoSNAT, &out.NoSNAT
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TrafficSpec.
+func (in *TrafficSpec) DeepCopy() *TrafficSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(TrafficSpec)
+	in.DeepCopyInto(out)
+	return out
+}
@@ -5,10 +5,10 @@ package controllers
 
 import (
 	"context"
-	"errors"
 	"fmt"
 	"reflect"
 	"strings"
+	"time"
 
 	configv1 "github.com/openshift/api/config/v1"
 	ocoperv1 "github.com/openshift/api/operator/v1"
@@ -21,6 +21,8 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	uns "k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/discovery"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
@@ -33,7 +35,13 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	operatorv1 "github.com/vmware/antrea-operator-for-kubernetes/api/v1"
+	"github.com/vmware/antrea-operator-for-kubernetes/controllers/antreaversion"
+	"github.com/vmware/antrea-operator-for-kubernetes/controllers/clusternetwork"
 	configutil "github.com/vmware/antrea-operator-for-kubernetes/controllers/config"
+	"github.com/vmware/antrea-operator-for-kubernetes/controllers/health"
+	"github.com/vmware/antrea-operator-for-kubernetes/controllers/manifestsource"
+	"github.com/vmware/antrea-operator-for-kubernetes/controllers/metrics"
+	"github.com/vmware/antrea-operator-for-kubernetes/controllers/platform"
 	"github.com/vmware/antrea-operator-for-kubernetes/controllers/sharedinfo"
 	"github.com/vmware/antrea-operator-for-kubernetes/controllers/statusmanager"
 	operatortypes "github.com/vmware/antrea-operator-for-kubernetes/controllers/types"
@@ -42,9 +50,8 @@ import (
 var log = ctrl.Log.WithName("controllers")
 
 type Adaptor interface {
-	SetupWithManager(r *AntreaInstallReconciler, mgr ctrl.Manager) error
 	Reconcile(r *AntreaInstallReconciler, request ctrl.Request) (reconcile.Result, error)
-	UpdateStatusManagerAndSharedInfo(r *AntreaInstallReconciler, objs []*uns.Unstructured, clusterConfig *configv1.Network) error
+	UpdateStatusManagerAndSharedInfo(r *AntreaInstallReconciler, objs []*uns.Unstructured, clusterConfig *configv1.Network, deferred bool) error
 }
 
 type AdaptorK8s struct {
@@ -55,94 +62,196 @@ type AdaptorOc struct {
 	Config configutil.Config
 }
 
-func (k8s *AdaptorK8s) SetupWithManager(r *AntreaInstallReconciler, mgr ctrl.Manager) error {
-	return ctrl.NewControllerManagedBy(mgr).
-		For(&operatorv1.AntreaInstall{}).
-		Complete(r)
-}
-
-func (oc *AdaptorOc) SetupWithManager(r *AntreaInstallReconciler, mgr ctrl.Manager) error {
-	return ctrl.NewControllerManagedBy(mgr).
-		For(&operatorv1.AntreaInstall{}).
-		Watches(&source.Kind{Type: &configv1.Network{}}, &handler.EnqueueRequestForObject{}).
-		Complete(r)
-}
+func applyConfig(r *AntreaInstallReconciler, config configutil.Config, clusterConfig *configv1.Network, operConfig *operatorv1.AntreaInstall, operatorNetwork *ocoperv1.Network) (reconcile.Result, bool, error) {
+	// Validate configurations against the Antrea configuration schema, before filling drops the
+	// unknown fields.
+	schemaErrs, schemaWarnings := configutil.ValidateSchema(operConfig, operatortypes.ManifestDir)
+	if len(schemaWarnings) > 0 {
+		log.Info("configurations have unknown fields", "warnings", schemaWarnings)
+		r.Status.Warning(statusmanager.EventReasonUnknownConfigFields, strings.Join(schemaWarnings, "\n"))
+	}
+	if len(schemaErrs) > 0 {
+		err := configutil.ToValidationErrors(schemaErrs)
+		log.Error(err, "invalid configurations")
+		r.Status.Warning(statusmanager.EventReasonValidationFailed, fmt.Sprintf("The operator configuration is invalid: %v", err))
+		r.Status.SetDegraded(statusmanager.OperatorConfig, "InvalidOperatorConfig", fmt.Sprintf("The operator configuration is invalid: %v", err))
+		return reconcile.Result{Requeue: true}, false, err
+	}
 
-func applyConfig(r *AntreaInstallReconciler, config configutil.Config, clusterConfig *configv1.Network, operConfig *operatorv1.AntreaInstall, operatorNetwork *ocoperv1.Network) (reconcile.Result, error) {
-	// Fill default configurations.
-	if err := config.FillConfigs(clusterConfig, operConfig); err != nil {
+	// Fill default configurations, some of which are derived from the cluster state.
+	state, err := GetClusterState(context.TODO(), r.APIReader)
+	if err != nil {
+		log.Error(err, "failed to get cluster state")
+		r.Status.SetDegraded(statusmanager.OperatorConfig, "InternalError", fmt.Sprintf("Failed to get cluster state: %v", err))
+		return reconcile.Result{Requeue: true}, false, err
+	}
+	// The datapath defaults of a running installation are derived from the applied configurations.
+	appliedConfig, err := r.getAppliedOperConfig()
+	if err != nil {
+		log.Error(err, "failed to get applied config")
+		r.Status.SetDegraded(statusmanager.OperatorConfig, "InternalError", fmt.Sprintf("Failed to get current configurations: %v", err))
+		return reconcile.Result{}, false, err
+	}
+	state.AppliedConfig = appliedConfig
+	if err := config.FillConfigs(clusterConfig, operConfig, state); err != nil {
 		log.Error(err, "failed to fill configurations")
+		r.Status.Warning(statusmanager.EventReasonFillFailed, fmt.Sprintf("Failed to fill configurations: %v", err))
 		r.Status.SetDegraded(statusmanager.OperatorConfig, "FillConfigurationsError", fmt.Sprintf("Failed to fill configurations: %v", err))
-		return reconcile.Result{Requeue: true}, err
+		return reconcile.Result{Requeue: true}, false, err
+	}
+	r.Status.Normal(statusmanager.EventReasonConfigFilled, "Filled default configurations")
+	if configutil.UplinkMTUFallback(operConfig.Spec.DefaultMTU, state) {
+		msg := fmt.Sprintf("No Node is annotated with %s, the MTU is derived from the default uplink MTU %d", operatortypes.UplinkMTUAnnotation, configutil.DefaultUplinkMTU)
+		r.Status.Warning(statusmanager.EventReasonUplinkMTUUnknown, msg)
+		r.Status.SetUplinkMTUUnknown(msg)
+	} else {
+		r.Status.ClearUplinkMTUUnknown()
 	}
 
-	// Validate configurations.
-	if err := config.ValidateConfig(clusterConfig, operConfig); err != nil {
+	// Validate configurations against the addresses in use in the cluster.
+	if err := config.ValidateConfig(clusterConfig, operConfig, state); err != nil {
 		log.Error(err, "failed to validate configurations")
+		r.Status.Warning(statusmanager.EventReasonValidationFailed, fmt.Sprintf("The operator configuration is invalid: %v", err))
 		r.Status.SetDegraded(statusmanager.OperatorConfig, "InvalidOperatorConfig", fmt.Sprintf("The operator configuration is invalid: %v", err))
-		return reconcile.Result{Requeue: true}, err
+		return reconcile.Result{Requeue: true}, false, err
 	}
 
 	// Generate render data.
 	renderData, err := config.GenerateRenderData(operatorNetwork, operConfig)
 	if err != nil {
 		log.Error(err, "failed to generate render data")
+		r.Status.Warning(statusmanager.EventReasonRenderFailed, fmt.Sprintf("Failed to generate render data: %v", err))
 		r.Status.SetDegraded(statusmanager.OperatorConfig, "RenderConfigError", fmt.Sprintf("Failed to render operator configurations: %v", err))
-		return reconcile.Result{Requeue: true}, err
+		return reconcile.Result{Requeue: true}, false, err
 	}
 
 	// Compare configurations change.
-	appliedConfig, err := r.getAppliedOperConfig()
-	if err != nil {
-		log.Error(err, "failed to get applied config")
-		r.Status.SetDegraded(statusmanager.OperatorConfig, "InternalError", fmt.Sprintf("Failed to get current configurations: %v", err))
-		return reconcile.Result{}, err
+	if operConfig.Spec.DryRun {
+		result, err := dryRun(r, renderData, appliedConfig, operConfig)
+		return result, false, err
+	}
+	if err := configutil.ValidateTrafficModeChange(appliedConfig, operConfig); err != nil {
+		log.Error(err, "refused traffic mode change")
+		r.Status.Warning(statusmanager.EventReasonValidationFailed, fmt.Sprintf("The operator configuration is invalid: %v", err))
+		r.Status.SetDegraded(statusmanager.OperatorConfig, "TrafficModeChangeRefused", fmt.Sprintf("The traffic mode change is refused: %v", err))
+		return reconcile.Result{}, false, err
+	}
+	if err := configutil.ValidateVersionChange(appliedConfig, operConfig); err != nil {
+		log.Error(err, "refused Antrea version change")
+		r.Status.Warning(statusmanager.EventReasonValidationFailed, fmt.Sprintf("The operator configuration is invalid: %v", err))
+		r.Status.SetDegraded(statusmanager.OperatorConfig, "VersionChangeRefused", fmt.Sprintf("The Antrea version change is refused: %v", err))
+		return reconcile.Result{}, false, err
 	}
 	agentNeedChange, controllerNeedChange, imageChange := configutil.NeedApplyChange(appliedConfig, operConfig)
-	if !agentNeedChange && !controllerNeedChange {
+	manifestChange := configutil.NeedManifestChange(appliedConfig, operConfig)
+
+	// Wait for the approval of the changes of a running installation with the Manual policy.
+	approved := false
+	if operConfig.Spec.ApprovalPolicy != configutil.ApprovalPolicyManual {
+		r.Status.ClearApprovalConditions()
+	} else if appliedConfig != nil && (agentNeedChange || controllerNeedChange || manifestChange) {
+		if approved, err = gateChange(r, renderData, appliedConfig, operConfig); err != nil {
+			r.Status.SetDegraded(statusmanager.OperatorConfig, "InternalError", fmt.Sprintf("Failed to gate configuration change: %v", err))
+			return reconcile.Result{Requeue: true}, false, err
+		}
+		if !approved {
+			return reconcile.Result{}, false, nil
+		}
+	} else {
+		r.Status.SetApprovalConditions(configv1.ClusterOperatorStatusCondition{
+			Type:   statusmanager.ConditionChangePending,
+			Status: configv1.ConditionFalse,
+			Reason: "NoPendingChange",
+		})
+	}
+	// Defer the disruptive changes of a running installation to the next maintenance window.
+	deferred, nextWindow, deferReason := deferDisruptiveChange(appliedConfig, operConfig, agentNeedChange, imageChange)
+	if !agentNeedChange && !controllerNeedChange && !manifestChange {
 		log.Info("no configuration change")
 	} else {
 		// Render configurations.
-		objs, err := render.RenderDir(operatortypes.DefaultManifestDir, renderData)
+		objs, err := renderManifests(r, renderData, operConfig)
 		if err != nil {
-			log.Error(err, "failed to render configuration")
-			r.Status.SetDegraded(statusmanager.OperatorConfig, "RenderConfigError", fmt.Sprintf("Failed to render operator configurations: %v", err))
-			return reconcile.Result{Requeue: true}, err
+			return reconcile.Result{Requeue: true}, false, err
 		}
 
 		// Update status and sharedInfo.
 		r.SharedInfo.Lock()
 		defer r.SharedInfo.Unlock()
-		if err = r.UpdateStatusManagerAndSharedInfo(r, objs, clusterConfig); err != nil {
-			return reconcile.Result{Requeue: true}, err
+		if err = r.UpdateStatusManagerAndSharedInfo(r, objs, clusterConfig, deferred); err != nil {
+			return reconcile.Result{Requeue: true}, false, err
 		}
 
-		// Apply configurations.
+		// Apply configurations. The objects restarting the Antrea Pods of a deferred change are
+		// held until the maintenance window.
+		applied := 0
 		for _, obj := range objs {
+			if deferred && isDisruptiveObject(obj) {
+				continue
+			}
+			applied++
 			if err = apply.ApplyObject(context.TODO(), r.Client, obj, ""); err != nil {
 				log.Error(err, "failed to apply resource")
+				r.Status.Warning(statusmanager.EventReasonApplyFailed, fmt.Sprintf("Failed to apply %s %s/%s: %v", obj.GetKind(), obj.GetNamespace(), obj.GetName(), err))
 				r.Status.SetDegraded(statusmanager.OperatorConfig, "ApplyObjectsError", fmt.Sprintf("Failed to apply operator configurations: %v", err))
-				return reconcile.Result{Requeue: true}, err
+				return reconcile.Result{Requeue: true}, false, err
 			}
 		}
+		r.Status.Normal(statusmanager.EventReasonObjectsApplied, fmt.Sprintf("Applied %d objects", applied))
+		if deferred {
+			if err = scheduleChange(r, operConfig, nextWindow, deferReason); err != nil {
+				r.Status.SetDegraded(statusmanager.OperatorConfig, "InternalError", fmt.Sprintf("Failed to schedule configuration change: %v", err))
+				return reconcile.Result{Requeue: true}, false, err
+			}
+			result := reconcile.Result{}
+			if !nextWindow.IsZero() {
+				result.RequeueAfter = time.Until(nextWindow)
+			}
+			return result, false, nil
+		}
 
-		// Delete old antrea-agent and antrea-controller pods.
-		if r.AppliedOperConfig != nil && agentNeedChange && !imageChange {
+		// Delete old antrea-agent and antrea-controller pods. The applied configurations are read from
+		// the cluster after a restart of the operator, so that the Pods of a running installation
+		// pick up the changes made meanwhile.
+		if appliedConfig != nil && agentNeedChange && !imageChange {
 			if err = deleteExistingPods(r.Client.Default().CRClient(), operatortypes.AntreaAgentDaemonSetName); err != nil {
 				msg := fmt.Sprintf("DaemonSet %s is not using the latest configuration updates because: %v", operatortypes.AntreaAgentDaemonSetName, err)
 				r.Status.SetDegraded(statusmanager.OperatorConfig, "DeleteOldPodsError", msg)
-				return reconcile.Result{Requeue: true}, err
+				return reconcile.Result{Requeue: true}, false, err
 			}
+			r.Status.Normal(statusmanager.EventReasonPodsRestarted, fmt.Sprintf("Restarted %s Pods to pick up configuration changes", operatortypes.AntreaAgentDaemonSetName))
 		}
-		if r.AppliedOperConfig != nil && controllerNeedChange && !imageChange {
+		if appliedConfig != nil && controllerNeedChange && !imageChange {
 			if err = deleteExistingPods(r.Client.Default().CRClient(), operatortypes.AntreaControllerDeploymentName); err != nil {
 				msg := fmt.Sprintf("Deployment %s is not using the latest configuration updates because: %v", operatortypes.AntreaControllerDeploymentName, err)
 				r.Status.SetDegraded(statusmanager.OperatorConfig, "DeleteOldPodsError", msg)
-				return reconcile.Result{Requeue: true}, err
+				return reconcile.Result{Requeue: true}, false, err
+			}
+			r.Status.Normal(statusmanager.EventReasonPodsRestarted, fmt.Sprintf("Restarted %s Pods to pick up configuration changes", operatortypes.AntreaControllerDeploymentName))
+		}
+		if approved {
+			if err = consumeApproval(r, operConfig); err != nil {
+				r.Status.SetDegraded(statusmanager.OperatorConfig, "InternalError", fmt.Sprintf("Failed to remove the applied approval: %v", err))
+				return reconcile.Result{Requeue: true}, false, err
 			}
 		}
 	}
-	return reconcile.Result{}, nil
+
+	// Publish effective configurations.
+	if err = publishEffectiveConfig(r, operConfig); err != nil {
+		r.Status.SetDegraded(statusmanager.OperatorConfig, "PublishEffectiveConfigError", fmt.Sprintf("Failed to publish effective configurations: %v", err))
+		return reconcile.Result{Requeue: true}, false, err
+	}
+	// Clear the preview of a previous dry run.
+	if err := r.Status.SetPreview(nil); err != nil {
+		log.Error(err, "failed to clear preview")
+	}
+	if err = completeScheduledChange(r, operConfig); err != nil {
+		r.Status.SetDegraded(statusmanager.OperatorConfig, "InternalError", fmt.Sprintf("Failed to complete scheduled configuration change: %v", err))
+		return reconcile.Result{Requeue: true}, false, err
+	}
+	metrics.SetAppliedConfig(operConfig.Generation)
+	return reconcile.Result{}, true, nil
 }
 
 func fetchAntreaInstall(r *AntreaInstallReconciler, request ctrl.Request) (*operatorv1.AntreaInstall, error, bool, bool) {
@@ -151,9 +260,7 @@ func fetchAntreaInstall(r *AntreaInstallReconciler, request ctrl.Request) (*oper
 	err := r.Client.Default().CRClient().Get(context.TODO(), types.NamespacedName{Namespace: operatortypes.OperatorNameSpace, Name: operatortypes.OperatorConfigName}, operConfig)
 	if err != nil {
 		if apierrors.IsNotFound(err) {
-			msg := fmt.Sprintf("%s CR not found", operatortypes.OperatorConfigName)
-			log.Info(msg)
-			r.Status.SetDegraded(statusmanager.ClusterConfig, "NoAntreaInstallCR", msg)
+			waitForAntreaInstall(r)
 			return nil, err, false, false
 		}
 		log.Error(err, "failed to get antrea-install CR")
@@ -163,12 +270,47 @@ func fetchAntreaInstall(r *AntreaInstallReconciler, request ctrl.Request) (*oper
 	if request.Name == operConfig.Name && r.AppliedOperConfig != nil {
 		if reflect.DeepEqual(operConfig.Spec, r.AppliedOperConfig.Spec) {
 			log.Info("no configuration change")
+			// A change deferred to a maintenance window was reverted.
+			if operConfig.Status.ScheduledChange != nil {
+				if err := completeScheduledChange(r, operConfig); err != nil {
+					log.Error(err, "failed to clear scheduled change")
+				}
+			}
 			return operConfig, nil, true, false
 		}
 	}
 	return operConfig, nil, true, true
 }
 
+// selectProfile returns the distribution profile selected by operConfig and records it in
+// AntreaInstall.Status. An invalid profile is reported as a Degraded condition.
+func selectProfile(r *AntreaInstallReconciler, operConfig *operatorv1.AntreaInstall, openShift bool) (*configutil.Profile, error) {
+	profile, err := configutil.SelectProfile(operConfig.Spec.Profile, r.distribution, openShift)
+	if err != nil {
+		log.Error(err, "failed to select profile")
+		r.Status.Warning(statusmanager.EventReasonValidationFailed, fmt.Sprintf("The operator configuration is invalid: %v", err))
+		r.Status.SetDegraded(statusmanager.OperatorConfig, "InvalidProfile", fmt.Sprintf("The operator configuration is invalid: %v", err))
+		return nil, err
+	}
+	if err := r.Status.SetProfile(profile.Name); err != nil {
+		log.Error(err, "failed to record profile")
+	}
+	return profile, nil
+}
+
+func buildClusterNetworkStatus(discovered *clusternetwork.Result) *operatorv1.ClusterNetworkStatus {
+	status := &operatorv1.ClusterNetworkStatus{
+		ServiceNetwork:       discovered.ServiceNetwork,
+		ServiceNetworkSource: discovered.ServiceNetworkSource,
+		ClusterNetworkSource: discovered.ClusterNetworkSource,
+		NodeIPAM:             discovered.NodeIPAM,
+	}
+	for _, entry := range discovered.ClusterNetwork {
+		status.ClusterNetwork = append(status.ClusterNetwork, entry.CIDR)
+	}
+	return status
+}
+
 func isOperatorRequest(r *AntreaInstallReconciler, request ctrl.Request) bool {
 	reqLogger := r.Log.WithValues("Request.NamespacedName", request.NamespacedName)
 	if request.Namespace == "" && request.Name == operatortypes.ClusterConfigName {
@@ -199,10 +341,29 @@ func (k8s *AdaptorK8s) Reconcile(r *AntreaInstallReconciler, request ctrl.Reques
 		return reconcile.Result{}, nil
 	}
 
+	// Select the distribution profile.
+	profile, err := selectProfile(r, operConfig, false)
+	if err != nil {
+		return reconcile.Result{}, nil
+	}
+	// Discover the cluster network.
+	discovered := r.NetworkDiscoverer.Discover(context.TODO())
+	if err := r.Status.SetClusterNetwork(buildClusterNetworkStatus(discovered)); err != nil {
+		log.Error(err, "failed to record cluster network")
+	}
+	k8s.Config = &configutil.ConfigK8s{Profile: profile, NodeIPAM: discovered.AntreaNodeIPAM(), ManifestDir: operatortypes.ManifestDir}
+
 	// Apply configuration.
-	if result, err := applyConfig(r, k8s.Config, nil, operConfig, nil); err != nil {
+	result, applied, err := applyConfig(r, k8s.Config, discovered.Network(), operConfig, nil)
+	if err != nil {
 		return result, err
 	}
+	if !applied {
+		// The configurations are only previewed, waiting for approval or deferred to a
+		// maintenance window, the applied configurations are unchanged.
+		r.Status.SetNotDegraded(statusmanager.OperatorConfig)
+		return result, nil
+	}
 
 	r.Status.SetNotDegraded(statusmanager.ClusterConfig)
 	r.Status.SetNotDegraded(statusmanager.OperatorConfig)
@@ -263,10 +424,23 @@ func (oc *AdaptorOc) Reconcile(r *AntreaInstallReconciler, request ctrl.Request)
 		return reconcile.Result{}, nil
 	}
 
+	// Check the distribution profile.
+	if _, err := selectProfile(r, operConfig, true); err != nil {
+		return reconcile.Result{}, nil
+	}
+
 	// Apply configuration.
-	if result, err := applyConfig(r, oc.Config, clusterConfig, operConfig, operatorNetwork); err != nil {
+	result, applied, err := applyConfig(r, oc.Config, clusterConfig, operConfig, operatorNetwork)
+	if err != nil {
 		return result, err
 	}
+	if !applied {
+		// The configurations are only previewed, waiting for approval or deferred to a
+		// maintenance window, the applied configurations and the cluster network status are
+		// unchanged.
+		r.Status.SetNotDegraded(statusmanager.OperatorConfig)
+		return result, nil
+	}
 
 	// Update cluster network CR status.
 	clusterNetworkConfigChanged := configutil.HasClusterNetworkConfigChange(r.AppliedClusterConfig, clusterConfig)
@@ -298,40 +472,145 @@ type AntreaInstallReconciler struct {
 	Scheme *runtime.Scheme
 	Status *statusmanager.StatusManager
 	Mapper meta.RESTMapper
+	// APIReader reads objects which are not watched, bypassing the cache.
+	APIReader client.Reader
+	Health    *health.Checker
 
+	// Adaptor is nil until the antrea-install CR, which selects the platform, has been found.
 	Adaptor
 
 	SharedInfo           *sharedinfo.SharedInfo
 	AppliedClusterConfig *configv1.Network
 	AppliedOperConfig    *operatorv1.AntreaInstall
+
+	Detector          *platform.Detector
+	NetworkDiscoverer *clusternetwork.Discoverer
+
+	controller             controller.Controller
+	platform               string
+	distribution           string
+	detectedPlatform       *operatorv1.PlatformStatus
+	platformChangeRefused  bool
+	watchingClusterNetwork bool
 }
 
-func New(mgr ctrl.Manager, statusManager *statusmanager.StatusManager, info *sharedinfo.SharedInfo, cli cnoclient.Client) (*AntreaInstallReconciler, error) {
-	r := AntreaInstallReconciler{
+func New(mgr ctrl.Manager, statusManager *statusmanager.StatusManager, info *sharedinfo.SharedInfo, cli cnoclient.Client, checker *health.Checker) (*AntreaInstallReconciler, error) {
+	discoveryClient, err := discovery.NewDiscoveryClientForConfig(mgr.GetConfig())
+	if err != nil {
+		return nil, err
+	}
+	return &AntreaInstallReconciler{
 		Client:     cli,
 		Log:        ctrl.Log.WithName("controllers").WithName("AntreaInstall"),
 		Scheme:     mgr.GetScheme(),
 		Status:     statusManager,
 		Mapper:     mgr.GetRESTMapper(),
+		APIReader:  mgr.GetAPIReader(),
+		Health:     checker,
 		SharedInfo: info,
+		Detector:   platform.NewDetector(discoveryClient, mgr.GetAPIReader()),
+
+		NetworkDiscoverer: clusternetwork.NewDiscoverer(mgr.GetAPIReader()),
+	}, nil
+}
+
+func (r *AntreaInstallReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	c, err := ctrl.NewControllerManagedBy(mgr).
+		For(&operatorv1.AntreaInstall{}).
+		Build(r)
+	if err != nil {
+		return err
 	}
-	switch info.AntreaPlatform {
-	case "openshift":
-		r.Adaptor = &AdaptorOc{
-			Config: &configutil.ConfigOc{},
+	r.controller = c
+	return nil
+}
+
+// watchClusterNetwork adds the watch of the cluster Network CR, which only exists on OpenShift,
+// once the platform is known.
+func (r *AntreaInstallReconciler) watchClusterNetwork() error {
+	if r.watchingClusterNetwork {
+		return nil
+	}
+	if err := r.controller.Watch(&source.Kind{Type: &configv1.Network{}}, &handler.EnqueueRequestForObject{}); err != nil {
+		return err
+	}
+	r.watchingClusterNetwork = true
+	return nil
+}
+
+func waitForAntreaInstall(r *AntreaInstallReconciler) {
+	msg := fmt.Sprintf("Waiting for AntreaInstall %s/%s to be created", operatortypes.OperatorNameSpace, operatortypes.OperatorConfigName)
+	log.Info(msg)
+	r.Status.SetDegraded(statusmanager.ClusterConfig, "WaitingForAntreaInstall", msg)
+}
+
+// getAdaptor returns the adaptor of the platform selected by the antrea-install CR, and builds
+// it when the CR is found for the first time. It returns a nil adaptor when the CR cannot be
+// reconciled yet. Once configurations have been applied, a platform change is refused, as the
+// applied objects cannot be migrated to another platform.
+func (r *AntreaInstallReconciler) getAdaptor() (Adaptor, error) {
+	operConfig := &operatorv1.AntreaInstall{}
+	err := r.Client.Default().CRClient().Get(context.TODO(), types.NamespacedName{Namespace: operatortypes.OperatorNameSpace, Name: operatortypes.OperatorConfigName}, operConfig)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			waitForAntreaInstall(r)
+			return nil, nil
 		}
-	case "kubernetes":
-		r.Adaptor = &AdaptorK8s{
-			Config: &configutil.ConfigK8s{},
+		log.Error(err, "failed to get antrea-install CR")
+		r.Status.SetDegraded(statusmanager.OperatorConfig, "InvalidAntreaInstallCR", fmt.Sprintf("Failed to get operator CR: %v", err))
+		return nil, err
+	}
+
+	platformStatus, err := r.resolvePlatform(operConfig.Spec.AntreaPlatform)
+	if err != nil {
+		log.Error(err, "failed to detect platform")
+		r.Status.SetDegraded(statusmanager.ClusterConfig, "PlatformDetectionError", fmt.Sprintf("Failed to detect platform: %v", err))
+		return nil, err
+	}
+	platformName := platformStatus.Name
+	if r.Adaptor != nil && platformName == r.platform {
+		if r.platformChangeRefused {
+			r.platformChangeRefused = false
+			r.Status.SetNotDegraded(statusmanager.OperatorConfig)
 		}
-	default:
-		return nil, errors.New("invalid platform: platform should be openshift or kubernetes")
+		return r.Adaptor, nil
+	}
+	if r.Adaptor != nil && r.AppliedOperConfig != nil {
+		msg := fmt.Sprintf("Changing the platform from %s to %s is not supported after Antrea has been installed", r.platform, platformName)
+		log.Info(msg)
+		r.platformChangeRefused = true
+		r.Status.Warning(statusmanager.EventReasonPlatformChangeRefused, msg)
+		r.Status.SetDegraded(statusmanager.OperatorConfig, "PlatformChangeRefused", msg)
+		return nil, nil
 	}
-	return &r, nil
-}
 
-func (r *AntreaInstallReconciler) SetupWithManager(mgr ctrl.Manager) error {
-	return r.Adaptor.SetupWithManager(r, mgr)
+	adaptors, ok := platforms[platformName]
+	if !ok {
+		msg := fmt.Sprintf("Invalid platform %q: platform should be one of %s", platformName, strings.Join(platformNames(), ", "))
+		log.Info(msg)
+		r.Status.SetDegraded(statusmanager.OperatorConfig, "InvalidPlatform", msg)
+		return nil, nil
+	}
+	adaptor, err := adaptors.newAdaptor(r)
+	if err != nil {
+		log.Error(err, "failed to create platform adaptor", "platform", platformName)
+		r.Status.SetDegraded(statusmanager.ClusterConfig, "InternalError", fmt.Sprintf("Failed to set up platform %s: %v", platformName, err))
+		return nil, err
+	}
+	if err := r.Status.SetPlatform(platformStatus, adaptors.newStatusAdaptor()); err != nil {
+		return nil, err
+	}
+	r.SharedInfo.Lock()
+	r.SharedInfo.AntreaPlatform = platformName
+	r.SharedInfo.Unlock()
+	r.Adaptor = adaptor
+	r.platform = platformName
+	r.distribution = platformStatus.Distribution
+	r.platformChangeRefused = false
+	r.Status.SetNotDegraded(statusmanager.ClusterConfig)
+	r.Status.SetNotDegraded(statusmanager.OperatorConfig)
+	log.Info("Using platform adaptor", "platform", platformName, "distribution", platformStatus.Distribution, "detected", platformStatus.Detected)
+	return adaptor, nil
 }
 
 // +kubebuilder:rbac:groups=operator.antrea.vmware.com,resources=antreainstalls,verbs=get;list;watch;create;update;patch;delete
@@ -341,6 +620,7 @@ func (r *AntreaInstallReconciler) SetupWithManager(mgr ctrl.Manager) error {
 // +kubebuilder:rbac:groups=config.openshift.io,resources=networks;networks/finalizers,verbs=get;list;watch;patch;update
 // +kubebuilder:rbac:groups=operator.openshift.io,resources=networks,verbs=get;list;watch;patch;update
 // +kubebuilder:rbac:groups="",resources=nodes,verbs=get;watch;list
+// +kubebuilder:rbac:groups="",resources=events,verbs=create;patch
 // +kubebuilder:rbac:groups="",resources=namespaces;pods;configmaps;services;serviceaccounts,verbs=create;delete;get;list;patch;update;watch;deletecollection
 // +kubebuilder:rbac:groups=apps,resources=deployments;daemonsets,verbs=create;delete;get;list;patch;update;watch
 // +kubebuilder:rbac:groups=apiextensions.k8s.io,resources=customresourcedefinitions,verbs=create;delete;get;list;patch;update;watch
@@ -349,28 +629,48 @@ func (r *AntreaInstallReconciler) SetupWithManager(mgr ctrl.Manager) error {
 // +kubebuilder:rbac:groups=authorization.k8s.io,resources=subjectaccessreviews,verbs=create
 // +kubebuilder:rbac:groups=authentication.k8s.io,resources=tokenreviews,verbs=create
 // +kubebuilder:rbac:groups=networking.k8s.io,resources=networkpolicies,verbs=get;watch;list
+// +kubebuilder:rbac:groups=networking.k8s.io,resources=servicecidrs,verbs=get;list
 // +kubebuilder:rbac:groups=admissionregistration.k8s.io,resources=mutatingwebhookconfigurations;validatingwebhookconfigurations,verbs=create
 // +kubebuilder:rbac:groups=crd.antrea.io,resources=traceflows;traceflows/status,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=crd.antrea.io,resources=antreaagentinfos;antreacontrollerinfos,verbs=get;list;create;update;delete
 // +kubebuilder:rbac:groups=controlplane.antrea.io,resources=networkpolicies;appliedtogroups;addressgroups,verbs=get;watch;list;delete
 // +kubebuilder:rbac:groups=crd.antrea.io,resources=clusternetworkpolicies,verbs=get;watch;list;delete
+// +kubebuilder:rbac:groups=crd.antrea.io,resources=externalippools,verbs=get;list
 // +kubebuilder:rbac:groups=system.antrea.io,resources=agentinfos;supportbundles;supportbundles/download,verbs=get;watch;list;post;delete
 // +kubebuilder:rbac:urls=/agentinfo;/addressgroups;/appliedtogroups;/networkpolicies;/ovsflows;/ovstracing;/podinterfaces,verbs=get
 // +kubebuilder:rbac:groups=security.openshift.io,resources=securitycontextconstraints,resourceNames=hostnetwork,verbs=use
 
 func (r *AntreaInstallReconciler) Reconcile(cxt context.Context, request ctrl.Request) (reconcile.Result, error) {
-	return r.Adaptor.Reconcile(r, request)
+	defer r.Health.TrackReconcile("antreainstall")()
+	start := time.Now()
+	result, err := r.reconcile(request)
+	metrics.ObserveReconcile("antreainstall", start, err)
+	return result, err
+}
+
+func (r *AntreaInstallReconciler) reconcile(request ctrl.Request) (reconcile.Result, error) {
+	adaptor, err := r.getAdaptor()
+	if adaptor == nil {
+		return reconcile.Result{}, err
+	}
+	return adaptor.Reconcile(r, request)
 }
 
 func (r *AntreaInstallReconciler) getAppliedOperConfig() (*operatorv1.AntreaInstall, error) {
 	if r.AppliedOperConfig != nil {
 		return r.AppliedOperConfig, nil
 	}
+	return ReadAppliedOperConfig(r.Client.Default().CRClient())
+}
+
+// ReadAppliedOperConfig rebuilds the applied configurations from the antrea-config ConfigMap, the
+// image of the antrea-controller Deployment and the effective ConfigMap of the operator. It returns
+// nil when Antrea is not installed.
+func ReadAppliedOperConfig(crcClient client.Reader) (*operatorv1.AntreaInstall, error) {
 	operConfig := &operatorv1.AntreaInstall{}
 	var antreaConfig *corev1.ConfigMap
 	configList := &corev1.ConfigMapList{}
 	label := map[string]string{"app": "antrea"}
-	crcClient := r.Client.Default().CRClient()
 	if err := crcClient.List(context.TODO(), configList, client.InNamespace(operatortypes.AntreaNamespace), client.MatchingLabels(label)); err != nil {
 		if apierrors.IsNotFound(err) {
 			return nil, nil
@@ -403,6 +703,17 @@ func (r *AntreaInstallReconciler) getAppliedOperConfig() (*operatorv1.AntreaInst
 		AntreaControllerConfig: antreaConfig.Data[operatortypes.AntreaControllerConfigOption],
 		AntreaImage:            image,
 	}
+	// The Antrea version, the manifest source and the traffic options are not recorded in the
+	// Antrea objects. The effective ConfigMap is missing when Antrea was installed by an earlier
+	// operator or without the operator.
+	effectiveConfig := &corev1.ConfigMap{}
+	if err := crcClient.Get(context.TODO(), types.NamespacedName{Namespace: operatortypes.OperatorNameSpace, Name: operatortypes.EffectiveConfigMapName}, effectiveConfig); err == nil {
+		if err := configutil.RestoreAppliedSpec(effectiveConfig, &operConfigSpec); err != nil {
+			return nil, err
+		}
+	} else if !apierrors.IsNotFound(err) {
+		return nil, err
+	}
 	operConfig.Spec = operConfigSpec
 	return operConfig, nil
 }
@@ -430,7 +741,58 @@ func updateNetworkStatus(c cnoclient.Client, clusterConfig *configv1.Network, de
 	return nil
 }
 
-func updateStatusManagerAndSharedInfo(r *AntreaInstallReconciler, objs []*uns.Unstructured, clusterConfig *configv1.Network) error {
+// publishEffectiveConfig stores the filled configurations of operConfig in a read-only ConfigMap
+// and references it, together with the hash of each configuration, from AntreaInstall.Status.
+func publishEffectiveConfig(r *AntreaInstallReconciler, operConfig *operatorv1.AntreaInstall) error {
+	configMap := configutil.BuildEffectiveConfigMap(operConfig)
+	if err := controllerutil.SetControllerReference(operConfig, configMap, r.Scheme); err != nil {
+		log.Error(err, "failed to set owner reference", "resource", configMap.Name)
+		return err
+	}
+	if err := apply.ApplyObject(context.TODO(), r.Client, configMap, ""); err != nil {
+		log.Error(err, fmt.Sprintf("Could not apply (%s) %s/%s", configMap.GroupVersionKind(), configMap.Namespace, configMap.Name))
+		return err
+	}
+	return r.Status.SetEffectiveConfig(configutil.BuildEffectiveConfig(operConfig))
+}
+
+// renderManifests renders the manifest templates of spec.manifestSource, or the bundled ones of
+// spec.antreaVersion when it is not set. A failure is reported as a Degraded condition.
+func renderManifests(r *AntreaInstallReconciler, renderData *render.RenderData, operConfig *operatorv1.AntreaInstall) ([]*uns.Unstructured, error) {
+	var manifestDir string
+	var err error
+	if operConfig.Spec.ManifestSource == nil {
+		manifestDir, err = antreaversion.ManifestDir(operatortypes.ManifestDir, operConfig.Spec.AntreaVersion)
+		if err != nil {
+			log.Error(err, "failed to select bundled manifests")
+			r.Status.Warning(statusmanager.EventReasonRenderFailed, fmt.Sprintf("Failed to select manifests: %v", err))
+			r.Status.SetDegraded(statusmanager.OperatorConfig, "AntreaVersionError", fmt.Sprintf("Failed to select the manifests of spec.antreaVersion: %v", err))
+			return nil, err
+		}
+	} else {
+		manifestDir, err = manifestsource.Resolve(context.TODO(), r.APIReader, operConfig.Namespace, operConfig.Spec.ManifestSource)
+		if err != nil {
+			log.Error(err, "failed to load manifest source")
+			r.Status.Warning(statusmanager.EventReasonRenderFailed, fmt.Sprintf("Failed to load manifests: %v", err))
+			r.Status.SetDegraded(statusmanager.OperatorConfig, "ManifestSourceError", fmt.Sprintf("Failed to load manifests from spec.manifestSource: %v", err))
+			return nil, err
+		}
+	}
+	objs, err := render.RenderDir(manifestDir, renderData)
+	if err != nil {
+		log.Error(err, "failed to render configuration")
+		r.Status.Warning(statusmanager.EventReasonRenderFailed, fmt.Sprintf("Failed to render manifests: %v", err))
+		r.Status.SetDegraded(statusmanager.OperatorConfig, "RenderConfigError", fmt.Sprintf("Failed to render operator configurations: %v", err))
+		return nil, err
+	}
+	return objs, nil
+}
+
+// updateStatusManagerAndSharedInfo sets the Antrea objects whose status is reported, and the
+// antrea-agent DaemonSet and antrea-controller Deployment the pod controller keeps applied. These
+// are left as they are when the change is deferred, so that the objects held until the maintenance
+// window are not applied by the pod controller.
+func updateStatusManagerAndSharedInfo(r *AntreaInstallReconciler, objs []*uns.Unstructured, clusterConfig *configv1.Network, deferred bool) error {
 	var daemonSets, deployments []types.NamespacedName
 	var relatedObjects []configv1.ObjectReference
 	var daemonSetObject, deploymentObject *uns.Unstructured
@@ -477,15 +839,19 @@ func updateStatusManagerAndSharedInfo(r *AntreaInstallReconciler, objs []*uns.Un
 	r.Status.SetDaemonSets(daemonSets)
 	r.Status.SetDeployments(deployments)
 	r.Status.SetRelatedObjects(relatedObjects)
+	if deferred {
+		return nil
+	}
 	r.SharedInfo.AntreaAgentDaemonSetSpec = daemonSetObject.DeepCopy()
 	r.SharedInfo.AntreaControllerDeploymentSpec = deploymentObject.DeepCopy()
 	return nil
 }
 
-func (a *AdaptorK8s) UpdateStatusManagerAndSharedInfo(r *AntreaInstallReconciler, objs []*uns.Unstructured, clusterConfig *configv1.Network) error {
-	return updateStatusManagerAndSharedInfo(r, objs, clusterConfig)
+func (a *AdaptorK8s) UpdateStatusManagerAndSharedInfo(r *AntreaInstallReconciler, objs []*uns.Unstructured, clusterConfig *configv1.Network, deferred bool) error {
+	// The discovered cluster network is not an object which can own the Antrea objects.
+	return updateStatusManagerAndSharedInfo(r, objs, nil, deferred)
 }
 
-func (a *AdaptorOc) UpdateStatusManagerAndSharedInfo(r *AntreaInstallReconciler, objs []*uns.Unstructured, clusterConfig *configv1.Network) error {
-	return updateStatusManagerAndSharedInfo(r, objs, clusterConfig)
+func (a *AdaptorOc) UpdateStatusManagerAndSharedInfo(r *AntreaInstallReconciler, objs []*uns.Unstructured, clusterConfig *configv1.Network, deferred bool) error {
+	return updateStatusManagerAndSharedInfo(r, objs, clusterConfig, deferred)
 }
@@ -0,0 +1,57 @@
+/* Copyright © 2026 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package controllers
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	operatorv1 "github.com/vmware/antrea-operator-for-kubernetes/api/v1"
+	configutil "github.com/vmware/antrea-operator-for-kubernetes/controllers/config"
+	operatortypes "github.com/vmware/antrea-operator-for-kubernetes/controllers/types"
+)
+
+func TestReadAppliedOperConfig(t *testing.T) {
+	g := NewGomegaWithT(t)
+	scheme := runtime.NewScheme()
+	g.Expect(clientgoscheme.AddToScheme(scheme)).Should(Succeed())
+
+	// Antrea is not installed.
+	c := fake.NewClientBuilder().WithScheme(scheme).Build()
+	appliedConfig, err := ReadAppliedOperConfig(c)
+	g.Expect(err).ShouldNot(HaveOccurred())
+	g.Expect(appliedConfig).Should(BeNil())
+
+	// Antrea was installed without the operator, or by an operator which did not publish the
+	// effective ConfigMap.
+	c = fake.NewClientBuilder().WithScheme(scheme).WithObjects(appliedAntreaObjects()...).Build()
+	appliedConfig, err = ReadAppliedOperConfig(c)
+	g.Expect(err).ShouldNot(HaveOccurred())
+	g.Expect(appliedConfig.Spec.AntreaAgentConfig).Should(Equal("serviceCIDR: 10.96.0.0/12\n"))
+	g.Expect(appliedConfig.Spec.AntreaImage).Should(Equal("antrea/antrea-ubuntu:v1.14.1"))
+	g.Expect(appliedConfig.Spec.ManifestSource).Should(BeNil())
+
+	// The Antrea version, the manifest source and the traffic options are restored from the
+	// effective ConfigMap after a restart of the operator.
+	operConfig := newWebhookConfig()
+	operConfig.Spec.AntreaVersion = "v1.14.1"
+	operConfig.Spec.Traffic = &operatorv1.TrafficSpec{EncapMode: "hybrid", AllowMigration: true}
+	operConfig.Spec.ManifestSource = &operatorv1.ManifestSource{
+		Directory: "/manifests",
+		Digest:    "sha256:0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef",
+	}
+	effectiveConfig := configutil.BuildEffectiveConfigMap(operConfig)
+	g.Expect(effectiveConfig.Namespace).Should(Equal(operatortypes.OperatorNameSpace))
+	c = fake.NewClientBuilder().WithScheme(scheme).WithObjects(append(appliedAntreaObjects(), effectiveConfig)...).Build()
+	appliedConfig, err = ReadAppliedOperConfig(c)
+	g.Expect(err).ShouldNot(HaveOccurred())
+	g.Expect(appliedConfig.Spec.AntreaVersion).Should(Equal("v1.14.1"))
+	g.Expect(appliedConfig.Spec.ManifestSource).Should(Equal(operConfig.Spec.ManifestSource))
+	g.Expect(appliedConfig.Spec.Traffic).Should(Equal(operConfig.Spec.Traffic))
+	g.Expect(configutil.NeedManifestChange(appliedConfig, operConfig)).Should(BeFalse())
+}
@@ -0,0 +1,174 @@
+/* Copyright © 2026 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/discovery"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	operatorv1 "github.com/vmware/antrea-operator-for-kubernetes/api/v1"
+	"github.com/vmware/antrea-operator-for-kubernetes/controllers/antreaversion"
+	configutil "github.com/vmware/antrea-operator-for-kubernetes/controllers/config"
+	"github.com/vmware/antrea-operator-for-kubernetes/controllers/platform"
+	operatortypes "github.com/vmware/antrea-operator-for-kubernetes/controllers/types"
+)
+
+// +kubebuilder:webhook:path=/mutate-operator-antrea-vmware-com-v1-antreainstall,mutating=true,failurePolicy=fail,sideEffects=None,groups=operator.antrea.vmware.com,resources=antreainstalls,verbs=create;update,versions=v1,name=mantreainstall.kb.io,admissionReviewVersions=v1
+// +kubebuilder:webhook:path=/validate-operator-antrea-vmware-com-v1-antreainstall,mutating=false,failurePolicy=fail,sideEffects=None,groups=operator.antrea.vmware.com,resources=antreainstalls,verbs=create;update,versions=v1,name=vantreainstall.kb.io,admissionReviewVersions=v1
+
+// AntreaInstallValidator runs the validation of the controller when the antrea-install CR is
+// created or updated, so that an invalid spec is refused by the API server instead of being
+// reported as a Degraded condition.
+type AntreaInstallValidator struct {
+	reader   client.Reader
+	detector *platform.Detector
+}
+
+func NewAntreaInstallValidator(mgr ctrl.Manager) (*AntreaInstallValidator, error) {
+	discoveryClient, err := discovery.NewDiscoveryClientForConfig(mgr.GetConfig())
+	if err != nil {
+		return nil, err
+	}
+	return &AntreaInstallValidator{
+		reader:   mgr.GetAPIReader(),
+		detector: platform.NewDetector(discoveryClient, mgr.GetAPIReader()),
+	}, nil
+}
+
+// SetupWebhookWithManager serves the defaulting and the validating webhooks of the AntreaInstall
+// CR. The defaults are set before the validation.
+func (v *AntreaInstallValidator) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(&operatorv1.AntreaInstall{}).
+		WithDefaulter(&AntreaInstallDefaulter{}).
+		WithValidator(v).
+		Complete()
+}
+
+// AntreaInstallDefaulter writes the static defaults in the spec of the antrea-install CR, so
+// that the stored spec matches the applied one. The defaults which depend on the cluster state,
+// such as the service CIDRs, the MTU and NodeIPAM, are published in status.effectiveConfig.
+type AntreaInstallDefaulter struct{}
+
+func (d *AntreaInstallDefaulter) Default(ctx context.Context, obj runtime.Object) error {
+	operConfig, ok := obj.(*operatorv1.AntreaInstall)
+	if !ok {
+		return fmt.Errorf("expected an AntreaInstall, got %T", obj)
+	}
+	setDefaults(&operConfig.Spec)
+	return nil
+}
+
+// setDefaults sets the Antrea image, tagged with spec.antreaVersion, which FillConfigs would set
+// otherwise, and the defaults of the CRD, so that they are visible in the stored spec whichever
+// way it was created.
+func setDefaults(spec *operatorv1.AntreaInstallSpec) {
+	spec.AntreaImage = configutil.AntreaImage(spec, operatortypes.ManifestDir)
+	if spec.AntreaPlatform == "" {
+		spec.AntreaPlatform = platform.Auto
+	}
+	if spec.Profile == "" {
+		spec.Profile = configutil.ProfileAuto
+	}
+	if spec.ApprovalPolicy == "" {
+		spec.ApprovalPolicy = configutil.ApprovalPolicyAutomatic
+	}
+}
+
+func (v *AntreaInstallValidator) ValidateCreate(ctx context.Context, obj runtime.Object) error {
+	operConfig, ok := obj.(*operatorv1.AntreaInstall)
+	if !ok {
+		return fmt.Errorf("expected an AntreaInstall, got %T", obj)
+	}
+	return v.validate(ctx, nil, operConfig)
+}
+
+func (v *AntreaInstallValidator) ValidateUpdate(ctx context.Context, oldObj, newObj runtime.Object) error {
+	oldConfig, ok := oldObj.(*operatorv1.AntreaInstall)
+	if !ok {
+		return fmt.Errorf("expected an AntreaInstall, got %T", oldObj)
+	}
+	operConfig, ok := newObj.(*operatorv1.AntreaInstall)
+	if !ok {
+		return fmt.Errorf("expected an AntreaInstall, got %T", newObj)
+	}
+	// Do not block the removal of the finalizers of a deleted CR.
+	if operConfig.DeletionTimestamp != nil {
+		return nil
+	}
+	return v.validate(ctx, oldConfig, operConfig)
+}
+
+func (v *AntreaInstallValidator) ValidateDelete(ctx context.Context, obj runtime.Object) error {
+	return nil
+}
+
+// validate runs the checks of applyConfig on operConfig, whose previous version is oldConfig on
+// update: the schema of the Antrea configurations, the filling and the validation against the
+// cluster network and state, the platform, traffic mode and Antrea version changes of a running
+// installation, and the bundled manifests of the Antrea version.
+func (v *AntreaInstallValidator) validate(ctx context.Context, oldConfig, operConfig *operatorv1.AntreaInstall) error {
+	if operConfig.Namespace != operatortypes.OperatorNameSpace || operConfig.Name != operatortypes.OperatorConfigName {
+		return fmt.Errorf("the operator only reconciles AntreaInstall %s/%s, %s/%s would be ignored",
+			operatortypes.OperatorNameSpace, operatortypes.OperatorConfigName, operConfig.Namespace, operConfig.Name)
+	}
+	if schemaErrs, _ := configutil.ValidateSchema(operConfig, operatortypes.ManifestDir); len(schemaErrs) > 0 {
+		return apierrors.NewInvalid(operatorv1.GroupVersion.WithKind("AntreaInstall").GroupKind(), operConfig.Name, schemaErrs)
+	}
+
+	selected, detected, err := ResolvePlatform(ctx, v.detector, operConfig.Spec.AntreaPlatform)
+	if err != nil {
+		return fmt.Errorf("failed to detect platform: %v", err)
+	}
+	appliedConfig, err := ReadAppliedOperConfig(v.reader)
+	if err != nil {
+		return fmt.Errorf("failed to get applied configurations: %v", err)
+	}
+	if oldConfig != nil && appliedConfig != nil {
+		if oldSelected := selectPlatform(oldConfig.Spec.AntreaPlatform, detected); oldSelected != nil && oldSelected.Name != selected.Name {
+			return fmt.Errorf("changing the platform from %s to %s is not supported after Antrea has been installed", oldSelected.Name, selected.Name)
+		}
+	}
+
+	network, err := LoadPlatformNetwork(ctx, selected.Name, v.reader)
+	if err != nil {
+		return err
+	}
+	config, err := NewPlatformConfig(selected.Name, operConfig.Spec.Profile, selected.Distribution, network, operatortypes.ManifestDir)
+	if err != nil {
+		return err
+	}
+	clusterConfig := network.ClusterConfig
+
+	state, err := GetClusterState(ctx, v.reader)
+	if err != nil {
+		return fmt.Errorf("failed to get cluster state: %v", err)
+	}
+	state.AppliedConfig = appliedConfig
+	filledConfig := operConfig.DeepCopy()
+	if err := config.FillConfigs(clusterConfig, filledConfig, state); err != nil {
+		return fmt.Errorf("failed to fill configurations: %v", err)
+	}
+	if err := config.ValidateConfig(clusterConfig, filledConfig, state); err != nil {
+		return fmt.Errorf("the operator configuration is invalid: %v", err)
+	}
+	if err := configutil.ValidateTrafficModeChange(appliedConfig, filledConfig); err != nil {
+		return fmt.Errorf("the traffic mode change is refused: %v", err)
+	}
+	if err := configutil.ValidateVersionChange(appliedConfig, filledConfig); err != nil {
+		return fmt.Errorf("the Antrea version change is refused: %v", err)
+	}
+	if operConfig.Spec.ManifestSource == nil {
+		if _, err := antreaversion.ManifestDir(operatortypes.ManifestDir, operConfig.Spec.AntreaVersion); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,187 @@
+/* Copyright © 2026 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	discoveryfake "k8s.io/client-go/discovery/fake"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	clienttesting "k8s.io/client-go/testing"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	operatorv1 "github.com/vmware/antrea-operator-for-kubernetes/api/v1"
+	configutil "github.com/vmware/antrea-operator-for-kubernetes/controllers/config"
+	"github.com/vmware/antrea-operator-for-kubernetes/controllers/platform"
+	operatortypes "github.com/vmware/antrea-operator-for-kubernetes/controllers/types"
+)
+
+const (
+	testManifestDir = "../antrea-manifest"
+	testCNIConfig   = `{"cniVersion": "0.3.0", "name": "antrea", "plugins": [{"type": "antrea", "ipam": {"type": "host-local"}}]}`
+)
+
+// newWebhookConfig returns a valid AntreaInstall on Kubernetes.
+func newWebhookConfig() *operatorv1.AntreaInstall {
+	return &operatorv1.AntreaInstall{
+		ObjectMeta: metav1.ObjectMeta{Namespace: operatortypes.OperatorNameSpace, Name: operatortypes.OperatorConfigName},
+		Spec: operatorv1.AntreaInstallSpec{
+			AntreaPlatform:    platform.Kubernetes,
+			AntreaAgentConfig: "serviceCIDR: 10.96.0.0/12\n",
+			AntreaCNIConfig:   testCNIConfig,
+		},
+	}
+}
+
+// newTestValidator returns a validator on a Kubernetes cluster of one Node, whose client also
+// serves objs.
+func newTestValidator(g *WithT, objs ...client.Object) *AntreaInstallValidator {
+	scheme := runtime.NewScheme()
+	g.Expect(clientgoscheme.AddToScheme(scheme)).Should(Succeed())
+	g.Expect(operatorv1.AddToScheme(scheme)).Should(Succeed())
+	node := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: "node1"},
+		Spec:       corev1.NodeSpec{PodCIDR: "10.10.0.0/24"},
+		Status: corev1.NodeStatus{
+			Addresses: []corev1.NodeAddress{{Type: corev1.NodeInternalIP, Address: "192.168.1.10"}},
+		},
+	}
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(append(objs, node)...).Build()
+	discoveryClient := &discoveryfake.FakeDiscovery{Fake: &clienttesting.Fake{}}
+	return &AntreaInstallValidator{reader: c, detector: platform.NewDetector(discoveryClient, c)}
+}
+
+// appliedAntreaObjects returns the antrea-config ConfigMap and the antrea-controller Deployment
+// of an installed Antrea, from which the applied configurations are read.
+func appliedAntreaObjects() []client.Object {
+	return []client.Object{
+		&corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: operatortypes.AntreaNamespace,
+				Name:      operatortypes.AntreaConfigMapName,
+				Labels:    map[string]string{"app": "antrea"},
+			},
+			Data: map[string]string{operatortypes.AntreaAgentConfigOption: "serviceCIDR: 10.96.0.0/12\n"},
+		},
+		&appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Namespace: operatortypes.AntreaNamespace, Name: operatortypes.AntreaControllerDeploymentName},
+			Spec: appsv1.DeploymentSpec{
+				Template: corev1.PodTemplateSpec{Spec: corev1.PodSpec{Containers: []corev1.Container{{Name: "antrea-controller", Image: "antrea/antrea-ubuntu:v1.14.1"}}}},
+			},
+		},
+	}
+}
+
+func TestSetDefaults(t *testing.T) {
+	g := NewGomegaWithT(t)
+	manifestDir := operatortypes.ManifestDir
+	operatortypes.ManifestDir = testManifestDir
+	defer func() { operatortypes.ManifestDir = manifestDir }()
+	defaulter := &AntreaInstallDefaulter{}
+
+	operConfig := newWebhookConfig()
+	operConfig.Spec.AntreaPlatform = ""
+	g.Expect(defaulter.Default(context.TODO(), operConfig)).Should(Succeed())
+	g.Expect(operConfig.Spec.AntreaImage).Should(Equal(configutil.AntreaImage(&operConfig.Spec, testManifestDir)))
+	g.Expect(operConfig.Spec.AntreaImage).ShouldNot(BeEmpty())
+	g.Expect(operConfig.Spec.AntreaPlatform).Should(Equal(platform.Auto))
+	g.Expect(operConfig.Spec.Profile).Should(Equal(configutil.ProfileAuto))
+	g.Expect(operConfig.Spec.ApprovalPolicy).Should(Equal(configutil.ApprovalPolicyAutomatic))
+
+	// The values set in the spec are kept.
+	operConfig = newWebhookConfig()
+	operConfig.Spec.AntreaImage = "projects.registry.vmware.com/antrea/antrea-ubuntu:v1.14.1"
+	operConfig.Spec.Profile = configutil.ProfileGeneric
+	operConfig.Spec.ApprovalPolicy = configutil.ApprovalPolicyManual
+	expected := operConfig.Spec.DeepCopy()
+	g.Expect(defaulter.Default(context.TODO(), operConfig)).Should(Succeed())
+	g.Expect(operConfig.Spec).Should(Equal(*expected))
+
+	g.Expect(defaulter.Default(context.TODO(), &corev1.ConfigMap{})).Should(MatchError(ContainSubstring("expected an AntreaInstall")))
+}
+
+func TestValidateCreate(t *testing.T) {
+	g := NewGomegaWithT(t)
+	manifestDir := operatortypes.ManifestDir
+	operatortypes.ManifestDir = testManifestDir
+	defer func() { operatortypes.ManifestDir = manifestDir }()
+
+	for _, tc := range []struct {
+		name   string
+		modify func(operConfig *operatorv1.AntreaInstall)
+		// expectedErr is a substring of the error, none is expected when it is empty.
+		expectedErr string
+	}{
+		{
+			name:   "valid",
+			modify: func(operConfig *operatorv1.AntreaInstall) {},
+		},
+		{
+			name:        "wrong name",
+			modify:      func(operConfig *operatorv1.AntreaInstall) { operConfig.Name = "antrea" },
+			expectedErr: "the operator only reconciles AntreaInstall",
+		},
+		{
+			name:        "wrong namespace",
+			modify:      func(operConfig *operatorv1.AntreaInstall) { operConfig.Namespace = "default" },
+			expectedErr: "the operator only reconciles AntreaInstall",
+		},
+		{
+			// The service CIDR cannot be discovered on Kubernetes.
+			name:        "fill error",
+			modify:      func(operConfig *operatorv1.AntreaInstall) { operConfig.Spec.AntreaAgentConfig = "" },
+			expectedErr: "failed to fill configurations: serviceCIDR or serviceCIDRv6 should be specified",
+		},
+		{
+			name: "validation error",
+			modify: func(operConfig *operatorv1.AntreaInstall) {
+				operConfig.Spec.ApprovalTTL = &metav1.Duration{Duration: -1}
+			},
+			expectedErr: "the operator configuration is invalid: approvalTTL must be positive",
+		},
+	} {
+		operConfig := newWebhookConfig()
+		tc.modify(operConfig)
+		err := newTestValidator(g).ValidateCreate(context.TODO(), operConfig)
+		if tc.expectedErr == "" {
+			g.Expect(err).ShouldNot(HaveOccurred(), tc.name)
+		} else {
+			g.Expect(err).Should(MatchError(ContainSubstring(tc.expectedErr)), tc.name)
+		}
+	}
+}
+
+func TestValidateUpdate(t *testing.T) {
+	g := NewGomegaWithT(t)
+	manifestDir := operatortypes.ManifestDir
+	operatortypes.ManifestDir = testManifestDir
+	defer func() { operatortypes.ManifestDir = manifestDir }()
+	oldConfig := newWebhookConfig()
+	oldConfig.Spec.AntreaPlatform = platform.OpenShift
+
+	// The platform can be changed until Antrea is installed.
+	g.Expect(newTestValidator(g).ValidateUpdate(context.TODO(), oldConfig, newWebhookConfig())).Should(Succeed())
+
+	validator := newTestValidator(g, appliedAntreaObjects()...)
+	err := validator.ValidateUpdate(context.TODO(), oldConfig, newWebhookConfig())
+	g.Expect(err).Should(MatchError("changing the platform from openshift to kubernetes is not supported after Antrea has been installed"))
+	operConfig := newWebhookConfig()
+	operConfig.Spec.AntreaAgentConfig = "serviceCIDR: 10.96.0.0/12\ndefaultMTU: 1400\n"
+	g.Expect(validator.ValidateUpdate(context.TODO(), newWebhookConfig(), operConfig)).Should(Succeed())
+
+	// The removal of the finalizers of a deleted CR is not blocked.
+	operConfig = newWebhookConfig()
+	operConfig.DeletionTimestamp = &metav1.Time{}
+	g.Expect(validator.ValidateUpdate(context.TODO(), oldConfig, operConfig)).Should(Succeed())
+
+	err = validator.ValidateUpdate(context.TODO(), &corev1.ConfigMap{}, newWebhookConfig())
+	g.Expect(err).Should(MatchError("expected an AntreaInstall, got *v1.ConfigMap"))
+}
@@ -0,0 +1,174 @@
+/* Copyright © 2026 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+// Package antreaversion selects the bundled manifests and the image of spec.antreaVersion, and
+// validates the upgrade paths between Antrea versions.
+package antreaversion
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/vmware/antrea-operator-for-kubernetes/controllers/types"
+)
+
+// Version is an Antrea release, v<major>.<minor>.<patch>.
+type Version struct {
+	Major, Minor, Patch int
+}
+
+// Parse parses an Antrea release version, such as v1.14.1.
+func Parse(version string) (Version, error) {
+	var v Version
+	numbers := strings.Split(strings.TrimPrefix(version, "v"), ".")
+	if !strings.HasPrefix(version, "v") || len(numbers) != 3 {
+		return v, fmt.Errorf("invalid Antrea version %q, expected v<major>.<minor>.<patch>", version)
+	}
+	for i, field := range []*int{&v.Major, &v.Minor, &v.Patch} {
+		n, err := strconv.Atoi(numbers[i])
+		if err != nil || n < 0 || strconv.Itoa(n) != numbers[i] {
+			return v, fmt.Errorf("invalid Antrea version %q, expected v<major>.<minor>.<patch>", version)
+		}
+		*field = n
+	}
+	return v, nil
+}
+
+func (v Version) String() string {
+	return fmt.Sprintf("v%d.%d.%d", v.Major, v.Minor, v.Patch)
+}
+
+// Less tells whether v is an earlier release than other.
+func (v Version) Less(other Version) bool {
+	if v.Major != other.Major {
+		return v.Major < other.Major
+	}
+	if v.Minor != other.Minor {
+		return v.Minor < other.Minor
+	}
+	return v.Patch < other.Patch
+}
+
+// Bundled returns the versions whose manifests are bundled in manifestDir, one subdirectory per
+// version, from the earliest to the latest.
+func Bundled(manifestDir string) ([]Version, error) {
+	entries, err := os.ReadDir(manifestDir)
+	if err != nil {
+		return nil, err
+	}
+	var versions []Version
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		if version, err := Parse(entry.Name()); err == nil {
+			versions = append(versions, version)
+		}
+	}
+	sort.Slice(versions, func(i, j int) bool {
+		return versions[i].Less(versions[j])
+	})
+	return versions, nil
+}
+
+// ManifestDir returns the directory of the templates of version in manifestDir, the latest
+// bundled one when version is empty. A manifestDir without version subdirectories holds the
+// templates of a single, unversioned, release, which can only be used without a version.
+func ManifestDir(manifestDir, version string) (string, error) {
+	versions, err := Bundled(manifestDir)
+	if err != nil {
+		return "", fmt.Errorf("failed to list bundled Antrea versions: %v", err)
+	}
+	if version == "" {
+		if len(versions) == 0 {
+			return manifestDir, nil
+		}
+		return filepath.Join(manifestDir, versions[len(versions)-1].String()), nil
+	}
+	requested, err := Parse(version)
+	if err != nil {
+		return "", err
+	}
+	for _, bundled := range versions {
+		if bundled == requested {
+			return filepath.Join(manifestDir, bundled.String()), nil
+		}
+	}
+	return "", fmt.Errorf("version %s of Antrea is not bundled with the operator, the bundled versions are: %s", version, join(versions))
+}
+
+func join(versions []Version) string {
+	if len(versions) == 0 {
+		return "none"
+	}
+	names := make([]string, len(versions))
+	for i, version := range versions {
+		names[i] = version.String()
+	}
+	return strings.Join(names, ", ")
+}
+
+// splitImage splits image into its repository and tag, which is empty when the image has no tag.
+// An image pinned by digest is not split.
+func splitImage(image string) (string, string) {
+	if strings.Contains(image, "@") {
+		return image, ""
+	}
+	// A colon before the last slash separates the port of the registry.
+	if i := strings.LastIndex(image, ":"); i > strings.LastIndex(image, "/") {
+		return image[:i], image[i+1:]
+	}
+	return image, ""
+}
+
+// Image returns the image of version: the repository of image, or of the default Antrea image
+// when image is empty, tagged with version. An image pinned by digest is returned unchanged.
+func Image(image string, version Version) string {
+	if image == "" {
+		image = types.DefaultAntreaImage
+	}
+	if strings.Contains(image, "@") {
+		return image
+	}
+	repository, _ := splitImage(image)
+	return repository + ":" + version.String()
+}
+
+// ImageVersion returns the version of the tag of image, if it is an Antrea release.
+func ImageVersion(image string) (Version, bool) {
+	_, tag := splitImage(image)
+	version, err := Parse(tag)
+	return version, err == nil
+}
+
+// ValidateChange checks that Antrea can be changed from version from to version to. The supported
+// paths are:
+//   - upgrades within a major version, by at most one minor version at a time;
+//   - downgrades to an earlier patch release of the same minor version;
+//   - downgrades to the previous minor version, to roll back an upgrade, with allowDowngrade.
+//
+// Changing the major version is not supported.
+func ValidateChange(from, to Version, allowDowngrade bool) error {
+	switch {
+	case from == to:
+		return nil
+	case from.Major != to.Major:
+		return fmt.Errorf("changing Antrea from %s to %s is not supported, the major version cannot be changed", from, to)
+	case from.Less(to):
+		if to.Minor > from.Minor+1 {
+			return fmt.Errorf("upgrading Antrea from %s to %s skips minor versions, upgrade to v%d.%d first", from, to, from.Major, from.Minor+1)
+		}
+	case to.Minor == from.Minor:
+	case to.Minor == from.Minor-1:
+		if !allowDowngrade {
+			return fmt.Errorf("downgrading Antrea from %s to %s rolls back a minor version, set spec.allowDowngrade to proceed", from, to)
+		}
+	default:
+		return fmt.Errorf("downgrading Antrea from %s to %s is not supported, only the previous minor version can be rolled back to", from, to)
+	}
+	return nil
+}
@@ -0,0 +1,163 @@
+/* Copyright © 2026 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	configv1 "github.com/openshift/api/config/v1"
+	"github.com/openshift/cluster-network-operator/pkg/render"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	operatorv1 "github.com/vmware/antrea-operator-for-kubernetes/api/v1"
+	configutil "github.com/vmware/antrea-operator-for-kubernetes/controllers/config"
+	"github.com/vmware/antrea-operator-for-kubernetes/controllers/statusmanager"
+	operatortypes "github.com/vmware/antrea-operator-for-kubernetes/controllers/types"
+)
+
+// gateChange applies the Manual approval policy to a change of a running installation. It
+// returns true when the revision of operConfig is approved, and the approval has not expired.
+// Otherwise, it publishes the pending change with its revision, and drops the approval of another
+// revision, which is stale, or the expired approval.
+func gateChange(r *AntreaInstallReconciler, renderData *render.RenderData, appliedConfig, operConfig *operatorv1.AntreaInstall) (bool, error) {
+	revision := configutil.ConfigRevision(operConfig)
+	approvedRevision := operConfig.Annotations[operatortypes.ApprovedRevisionAnnotation]
+	notApproved := configv1.ClusterOperatorStatusCondition{
+		Type:    statusmanager.ConditionChangeApproved,
+		Status:  configv1.ConditionFalse,
+		Reason:  "NotApproved",
+		Message: fmt.Sprintf("Revision %s is not approved", revision),
+	}
+	if approvedRevision == revision {
+		approvedAt, err := observeApproval(r, operConfig, revision)
+		if err != nil {
+			return false, err
+		}
+		expiry := configutil.ApprovalExpiry(&operConfig.Spec, approvedAt)
+		if time.Now().Before(expiry) {
+			log.Info("applying approved revision", "revision", revision, "expiry", expiry)
+			r.Status.SetApprovalConditions(
+				configv1.ClusterOperatorStatusCondition{
+					Type:   statusmanager.ConditionChangePending,
+					Status: configv1.ConditionFalse,
+					Reason: "Approved",
+				},
+				configv1.ClusterOperatorStatusCondition{
+					Type:    statusmanager.ConditionChangeApproved,
+					Status:  configv1.ConditionTrue,
+					Reason:  "Approved",
+					Message: fmt.Sprintf("Revision %s is approved until %s", revision, expiry.UTC().Format(time.RFC3339)),
+				},
+			)
+			return true, nil
+		}
+		if err := removeAnnotation(r, operConfig, operatortypes.ApprovedRevisionAnnotation, operatortypes.ApprovedAtAnnotation); err != nil {
+			return false, err
+		}
+		r.Status.Warning(statusmanager.EventReasonApprovalExpired,
+			fmt.Sprintf("The approval of revision %s expired at %s before it was applied, approve it again to apply it", revision, expiry.UTC().Format(time.RFC3339)))
+		notApproved.Reason = "ApprovalExpired"
+		notApproved.Message = fmt.Sprintf("The approval of revision %s expired at %s", revision, expiry.UTC().Format(time.RFC3339))
+	} else if approvedRevision != "" {
+		if err := removeAnnotation(r, operConfig, operatortypes.ApprovedRevisionAnnotation, operatortypes.ApprovedAtAnnotation); err != nil {
+			return false, err
+		}
+		r.Status.Warning(statusmanager.EventReasonApprovalExpired,
+			fmt.Sprintf("The approval of revision %s expired, the pending revision is %s", approvedRevision, revision))
+	}
+
+	if _, err := publishPreview(r, renderData, appliedConfig, operConfig, revision); err != nil {
+		return false, err
+	}
+	r.Status.SetApprovalConditions(
+		configv1.ClusterOperatorStatusCondition{
+			Type:    statusmanager.ConditionChangePending,
+			Status:  configv1.ConditionTrue,
+			Reason:  "AwaitingApproval",
+			Message: fmt.Sprintf("Revision %s is waiting for approval, set the %s annotation to apply it", revision, operatortypes.ApprovedRevisionAnnotation),
+		},
+		notApproved,
+	)
+	r.Status.Normal(statusmanager.EventReasonChangePending, fmt.Sprintf("Revision %s is waiting for approval, see status.preview", revision))
+	return false, nil
+}
+
+// consumeApproval removes the approval of an applied revision, so that it does not approve a
+// later change back to the same configurations, and reports that no change is pending.
+func consumeApproval(r *AntreaInstallReconciler, operConfig *operatorv1.AntreaInstall) error {
+	revision := configutil.ConfigRevision(operConfig)
+	if err := removeAnnotation(r, operConfig, operatortypes.ApprovedRevisionAnnotation, operatortypes.ApprovedAtAnnotation); err != nil {
+		return err
+	}
+	r.Status.SetApprovalConditions(
+		configv1.ClusterOperatorStatusCondition{
+			Type:   statusmanager.ConditionChangePending,
+			Status: configv1.ConditionFalse,
+			Reason: "NoPendingChange",
+		},
+		configv1.ClusterOperatorStatusCondition{
+			Type:    statusmanager.ConditionChangeApproved,
+			Status:  configv1.ConditionTrue,
+			Reason:  "Applied",
+			Message: fmt.Sprintf("Revision %s was approved and applied", revision),
+		},
+	)
+	r.Status.Normal(statusmanager.EventReasonChangeApproved, fmt.Sprintf("Applied approved revision %s", revision))
+	return nil
+}
+
+// observeApproval returns the time the operator observed the approval of revision, recorded in
+// the approved-at annotation of the antrea-install CR, and records it when it is missing.
+func observeApproval(r *AntreaInstallReconciler, operConfig *operatorv1.AntreaInstall, revision string) (time.Time, error) {
+	if approvedAt, ok := configutil.ParseApprovalTime(operConfig.Annotations[operatortypes.ApprovedAtAnnotation], revision); ok {
+		return approvedAt, nil
+	}
+	approvedAt := time.Now()
+	crClient := r.Client.Default().CRClient()
+	antreaInstall := &operatorv1.AntreaInstall{}
+	if err := crClient.Get(context.TODO(), client.ObjectKeyFromObject(operConfig), antreaInstall); err != nil {
+		return time.Time{}, err
+	}
+	patch := client.MergeFrom(antreaInstall.DeepCopy())
+	if antreaInstall.Annotations == nil {
+		antreaInstall.Annotations = map[string]string{}
+	}
+	antreaInstall.Annotations[operatortypes.ApprovedAtAnnotation] = configutil.FormatApprovalTime(revision, approvedAt)
+	if err := crClient.Patch(context.TODO(), antreaInstall, patch); err != nil {
+		log.Error(err, "failed to record approval time", "annotation", operatortypes.ApprovedAtAnnotation)
+		return time.Time{}, err
+	}
+	return approvedAt, nil
+}
+
+// removeAnnotation removes the annotations with the given keys from the antrea-install CR.
+func removeAnnotation(r *AntreaInstallReconciler, operConfig *operatorv1.AntreaInstall, keys ...string) error {
+	crClient := r.Client.Default().CRClient()
+	antreaInstall := &operatorv1.AntreaInstall{}
+	if err := crClient.Get(context.TODO(), client.ObjectKeyFromObject(operConfig), antreaInstall); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+	patch := client.MergeFrom(antreaInstall.DeepCopy())
+	removed := false
+	for _, key := range keys {
+		if _, ok := antreaInstall.Annotations[key]; ok {
+			delete(antreaInstall.Annotations, key)
+			removed = true
+		}
+	}
+	if !removed {
+		return nil
+	}
+	if err := crClient.Patch(context.TODO(), antreaInstall, patch); err != nil {
+		log.Error(err, "failed to remove annotations", "annotations", keys)
+		return err
+	}
+	return nil
+}
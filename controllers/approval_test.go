@@ -0,0 +1,228 @@
+/* Copyright © 2026 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package controllers
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	configv1 "github.com/openshift/api/config/v1"
+	cnoclient "github.com/openshift/cluster-network-operator/pkg/client"
+	"github.com/openshift/cluster-network-operator/pkg/render"
+	"github.com/openshift/library-go/pkg/config/clusteroperator/v1helpers"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	operatorv1 "github.com/vmware/antrea-operator-for-kubernetes/api/v1"
+	configutil "github.com/vmware/antrea-operator-for-kubernetes/controllers/config"
+	"github.com/vmware/antrea-operator-for-kubernetes/controllers/manifestsource"
+	"github.com/vmware/antrea-operator-for-kubernetes/controllers/statusmanager"
+	operatortypes "github.com/vmware/antrea-operator-for-kubernetes/controllers/types"
+)
+
+// fakeClusterClient serves the controller-runtime client of the default cluster.
+type fakeClusterClient struct {
+	cnoclient.ClusterClient
+	client client.Client
+}
+
+func (c *fakeClusterClient) CRClient() client.Client {
+	return c.client
+}
+
+type fakeCNOClient struct {
+	cnoclient.Client
+	cluster *fakeClusterClient
+}
+
+func (c *fakeCNOClient) Default() cnoclient.ClusterClient {
+	return c.cluster
+}
+
+// newTestReconciler returns a reconciler on the Kubernetes platform, whose clients serve objs.
+func newTestReconciler(g *WithT, objs ...client.Object) (*AntreaInstallReconciler, client.Client, *record.FakeRecorder) {
+	scheme := runtime.NewScheme()
+	g.Expect(clientgoscheme.AddToScheme(scheme)).Should(Succeed())
+	g.Expect(operatorv1.AddToScheme(scheme)).Should(Succeed())
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(objs...).Build()
+	recorder := record.NewFakeRecorder(100)
+	status := statusmanager.New(c, nil, recorder, operatortypes.AntreaClusterOperatorName, operatortypes.OperatorNameSpace, "test")
+	status.Adaptor = &statusmanager.StatusK8s{}
+	r := &AntreaInstallReconciler{
+		Client:    &fakeCNOClient{cluster: &fakeClusterClient{client: c}},
+		Scheme:    scheme,
+		Status:    status,
+		APIReader: c,
+	}
+	return r, c, recorder
+}
+
+func receivedEvents(recorder *record.FakeRecorder) []string {
+	var events []string
+	for {
+		select {
+		case event := <-recorder.Events:
+			events = append(events, event)
+		default:
+			return events
+		}
+	}
+}
+
+// newApprovalConfig returns an AntreaInstall with the Manual approval policy, whose manifests are
+// one template of a directory.
+func newApprovalConfig(g *WithT, dir string) *operatorv1.AntreaInstall {
+	template := "apiVersion: v1\nkind: ServiceAccount\nmetadata:\n  name: antrea-agent\n  namespace: kube-system\n"
+	g.Expect(os.WriteFile(filepath.Join(dir, "antrea.yml"), []byte(template), 0644)).Should(Succeed())
+	files, err := manifestsource.ReadDir(dir)
+	g.Expect(err).ShouldNot(HaveOccurred())
+	return &operatorv1.AntreaInstall{
+		ObjectMeta: metav1.ObjectMeta{Namespace: operatortypes.OperatorNameSpace, Name: operatortypes.OperatorConfigName},
+		Spec: operatorv1.AntreaInstallSpec{
+			AntreaAgentConfig: "defaultMTU: 1400\n",
+			ApprovalPolicy:    configutil.ApprovalPolicyManual,
+			ManifestSource:    &operatorv1.ManifestSource{Directory: dir, Digest: files.Digest()},
+		},
+	}
+}
+
+func TestGateChange(t *testing.T) {
+	g := NewGomegaWithT(t)
+	cacheDir := manifestsource.CacheDir
+	manifestsource.CacheDir = t.TempDir()
+	defer func() { manifestsource.CacheDir = cacheDir }()
+	renderData := render.MakeRenderData()
+	appliedConfig := &operatorv1.AntreaInstall{Spec: operatorv1.AntreaInstallSpec{AntreaAgentConfig: "defaultMTU: 1450\n"}}
+	revision := configutil.ConfigRevision(newApprovalConfig(g, t.TempDir()))
+	expiredAt := configutil.FormatApprovalTime(revision, time.Now().Add(-configutil.DefaultApprovalTTL-time.Minute))
+
+	for _, tc := range []struct {
+		name        string
+		annotations map[string]string
+		approvalTTL *metav1.Duration
+		expected    bool
+		// expectedReason is the reason of the ChangeApproved condition.
+		expectedReason string
+		expectedEvent  string
+		// expectedApprovedAt tells whether the approval time is kept on the CR.
+		expectedApprovedAt bool
+	}{
+		{
+			name:           "pending",
+			expectedReason: "NotApproved",
+			expectedEvent:  "Normal ChangePending Revision " + revision + " is waiting for approval",
+		},
+		{
+			name:               "approved",
+			annotations:        map[string]string{operatortypes.ApprovedRevisionAnnotation: revision},
+			expected:           true,
+			expectedReason:     "Approved",
+			expectedApprovedAt: true,
+		},
+		{
+			// The time of the approval of another revision is not used.
+			name: "approved after another revision",
+			annotations: map[string]string{
+				operatortypes.ApprovedRevisionAnnotation: revision,
+				operatortypes.ApprovedAtAnnotation:       configutil.FormatApprovalTime("0123456789ab", time.Now().Add(-48*time.Hour)),
+			},
+			expected:           true,
+			expectedReason:     "Approved",
+			expectedApprovedAt: true,
+		},
+		{
+			name:           "mismatched revision",
+			annotations:    map[string]string{operatortypes.ApprovedRevisionAnnotation: "0123456789ab"},
+			expectedReason: "NotApproved",
+			expectedEvent:  "Warning ApprovalExpired The approval of revision 0123456789ab expired, the pending revision is " + revision,
+		},
+		{
+			name: "expired",
+			annotations: map[string]string{
+				operatortypes.ApprovedRevisionAnnotation: revision,
+				operatortypes.ApprovedAtAnnotation:       expiredAt,
+			},
+			expectedReason: "ApprovalExpired",
+			expectedEvent:  "Warning ApprovalExpired The approval of revision " + revision + " expired at",
+		},
+		{
+			name: "not expired with a longer TTL",
+			annotations: map[string]string{
+				operatortypes.ApprovedRevisionAnnotation: revision,
+				operatortypes.ApprovedAtAnnotation:       expiredAt,
+			},
+			approvalTTL:        &metav1.Duration{Duration: 2 * configutil.DefaultApprovalTTL},
+			expected:           true,
+			expectedReason:     "Approved",
+			expectedApprovedAt: true,
+		},
+	} {
+		operConfig := newApprovalConfig(g, t.TempDir())
+		operConfig.Annotations = tc.annotations
+		operConfig.Spec.ApprovalTTL = tc.approvalTTL
+		r, c, recorder := newTestReconciler(g, operConfig.DeepCopy())
+
+		approved, err := gateChange(r, &renderData, appliedConfig, operConfig)
+		g.Expect(err).ShouldNot(HaveOccurred(), tc.name)
+		g.Expect(approved).Should(Equal(tc.expected), tc.name)
+
+		antreaInstall := &operatorv1.AntreaInstall{}
+		g.Expect(c.Get(context.TODO(), client.ObjectKeyFromObject(operConfig), antreaInstall)).Should(Succeed())
+		condition := v1helpers.FindStatusCondition(antreaInstall.Status.Conditions, statusmanager.ConditionChangeApproved)
+		g.Expect(condition).ShouldNot(BeNil(), tc.name)
+		g.Expect(condition.Reason).Should(Equal(tc.expectedReason), tc.name)
+		expectedPending := configv1.ConditionTrue
+		if tc.expected {
+			expectedPending = configv1.ConditionFalse
+		}
+		g.Expect(v1helpers.FindStatusCondition(antreaInstall.Status.Conditions, statusmanager.ConditionChangePending).Status).Should(Equal(expectedPending), tc.name)
+
+		approvedAt, ok := configutil.ParseApprovalTime(antreaInstall.Annotations[operatortypes.ApprovedAtAnnotation], revision)
+		g.Expect(ok).Should(Equal(tc.expectedApprovedAt), tc.name)
+		if tc.expected {
+			g.Expect(antreaInstall.Annotations[operatortypes.ApprovedRevisionAnnotation]).Should(Equal(revision), tc.name)
+			g.Expect(condition.Message).Should(ContainSubstring(configutil.ApprovalExpiry(&operConfig.Spec, approvedAt).UTC().Format(time.RFC3339)), tc.name)
+		} else {
+			// The pending revision is published, and the stale or expired approval is dropped.
+			g.Expect(antreaInstall.Status.Preview.Revision).Should(Equal(revision), tc.name)
+			g.Expect(antreaInstall.Annotations).ShouldNot(HaveKey(operatortypes.ApprovedRevisionAnnotation), tc.name)
+		}
+		events := receivedEvents(recorder)
+		if tc.expectedEvent != "" {
+			g.Expect(strings.Join(events, "\n")).Should(ContainSubstring(tc.expectedEvent), tc.name)
+		}
+	}
+}
+
+func TestConsumeApproval(t *testing.T) {
+	g := NewGomegaWithT(t)
+	operConfig := newApprovalConfig(g, t.TempDir())
+	revision := configutil.ConfigRevision(operConfig)
+	operConfig.Annotations = map[string]string{
+		operatortypes.ApprovedRevisionAnnotation: revision,
+		operatortypes.ApprovedAtAnnotation:       configutil.FormatApprovalTime(revision, time.Now()),
+	}
+	r, c, recorder := newTestReconciler(g, operConfig.DeepCopy())
+
+	// The applied approval is removed, so that it does not approve a later change.
+	g.Expect(consumeApproval(r, operConfig)).Should(Succeed())
+	antreaInstall := &operatorv1.AntreaInstall{}
+	g.Expect(c.Get(context.TODO(), client.ObjectKeyFromObject(operConfig), antreaInstall)).Should(Succeed())
+	g.Expect(antreaInstall.Annotations).ShouldNot(HaveKey(operatortypes.ApprovedRevisionAnnotation))
+	g.Expect(antreaInstall.Annotations).ShouldNot(HaveKey(operatortypes.ApprovedAtAnnotation))
+	condition := v1helpers.FindStatusCondition(antreaInstall.Status.Conditions, statusmanager.ConditionChangeApproved)
+	g.Expect(condition.Status).Should(Equal(configv1.ConditionTrue))
+	g.Expect(condition.Reason).Should(Equal("Applied"))
+	g.Expect(v1helpers.FindStatusCondition(antreaInstall.Status.Conditions, statusmanager.ConditionChangePending).Status).Should(Equal(configv1.ConditionFalse))
+	g.Expect(receivedEvents(recorder)).Should(ContainElement(ContainSubstring("Applied approved revision " + revision)))
+}
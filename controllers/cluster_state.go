@@ -0,0 +1,88 @@
+/* Copyright © 2026 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	uns "k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	configutil "github.com/vmware/antrea-operator-for-kubernetes/controllers/config"
+	operatortypes "github.com/vmware/antrea-operator-for-kubernetes/controllers/types"
+)
+
+// externalIPPoolVersions are the served versions of the Antrea ExternalIPPool API, newest first.
+var externalIPPoolVersions = []string{"v1beta1", "v1alpha2"}
+
+// GetClusterState reads the Node InternalIPs and the ExternalIPPools, which the Antrea
+// configurations are validated against, and the Pod CIDRs and uplink MTUs of the Nodes.
+func GetClusterState(ctx context.Context, reader client.Reader) (*configutil.ClusterState, error) {
+	state := &configutil.ClusterState{
+		NodeInternalIPs: map[string][]string{},
+		ExternalIPPools: map[string][]string{},
+		NodePodCIDRs:    map[string][]string{},
+		NodeUplinkMTUs:  map[string]int{},
+	}
+
+	nodes := &corev1.NodeList{}
+	if err := reader.List(ctx, nodes); err != nil {
+		return nil, fmt.Errorf("failed to list Nodes: %v", err)
+	}
+	for _, node := range nodes.Items {
+		for _, address := range node.Status.Addresses {
+			if address.Type == corev1.NodeInternalIP {
+				state.NodeInternalIPs[node.Name] = append(state.NodeInternalIPs[node.Name], address.Address)
+			}
+		}
+		if len(node.Spec.PodCIDRs) > 0 {
+			state.NodePodCIDRs[node.Name] = node.Spec.PodCIDRs
+		} else if node.Spec.PodCIDR != "" {
+			state.NodePodCIDRs[node.Name] = []string{node.Spec.PodCIDR}
+		}
+		if value, ok := node.Annotations[operatortypes.UplinkMTUAnnotation]; ok {
+			mtu, err := strconv.Atoi(value)
+			if err != nil || mtu <= 0 {
+				log.Info("ignoring invalid uplink MTU annotation", "node", node.Name, "value", value)
+				continue
+			}
+			state.NodeUplinkMTUs[node.Name] = mtu
+		}
+	}
+
+	for _, version := range externalIPPoolVersions {
+		pools := &uns.UnstructuredList{}
+		pools.SetGroupVersionKind(schema.GroupVersionKind{Group: "crd.antrea.io", Version: version, Kind: "ExternalIPPoolList"})
+		err := reader.List(ctx, pools)
+		if meta.IsNoMatchError(err) || apierrors.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to list ExternalIPPools: %v", err)
+		}
+		for _, pool := range pools.Items {
+			ipRanges, _, _ := uns.NestedSlice(pool.Object, "spec", "ipRanges")
+			for _, ipRange := range ipRanges {
+				ipRange, ok := ipRange.(map[string]interface{})
+				if !ok {
+					continue
+				}
+				if cidr, ok := ipRange["cidr"].(string); ok && cidr != "" {
+					state.ExternalIPPools[pool.GetName()] = append(state.ExternalIPPools[pool.GetName()], cidr)
+				} else if start, ok := ipRange["start"].(string); ok {
+					end, _ := ipRange["end"].(string)
+					state.ExternalIPPools[pool.GetName()] = append(state.ExternalIPPools[pool.GetName()], start+"-"+end)
+				}
+			}
+		}
+		break
+	}
+	return state, nil
+}
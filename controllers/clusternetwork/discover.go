@@ -0,0 +1,367 @@
+/* Copyright © 2026 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package clusternetwork
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/ghodss/yaml"
+	configv1 "github.com/openshift/api/config/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	uns "k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/net"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	operatortypes "github.com/vmware/antrea-operator-for-kubernetes/controllers/types"
+)
+
+var log = logf.Log.WithName("clusternetwork")
+
+const (
+	// Sources of the cluster network, in the order they are consulted.
+	SourceKubeadmConfig  = "kubeadm-config"
+	SourceStaticPodArgs  = "static-pod-args"
+	SourceServiceCIDRAPI = "servicecidr-api"
+	SourceNodePodCIDRs   = "node-podcidrs"
+
+	// NodeIPAM tells which component allocates the Pod CIDRs of the Nodes.
+	NodeIPAMKubeControllerManager = "kube-controller-manager"
+	NodeIPAMAntrea                = "antrea"
+
+	kubeSystemNamespace     = "kube-system"
+	kubeadmConfigMapName    = "kubeadm-config"
+	kubeadmClusterConfigKey = "ClusterConfiguration"
+	defaultServiceCIDRName  = "kubernetes"
+
+	// The kube-controller-manager defaults of the size of the Node Pod CIDRs.
+	defaultNodeCIDRMaskSizeIPv4 = 24
+	defaultNodeCIDRMaskSizeIPv6 = 64
+)
+
+// Result is the cluster network discovered on a Kubernetes cluster.
+type Result struct {
+	ServiceNetwork       []string
+	ServiceNetworkSource string
+
+	ClusterNetwork       []configv1.ClusterNetworkEntry
+	ClusterNetworkSource string
+
+	// ServiceNodePortRange is the NodePort range of kube-apiserver, empty when unknown.
+	ServiceNodePortRange string
+
+	// NodeIPAM is the component which allocates the Pod CIDRs of the Nodes, it is empty when
+	// the cluster network is unknown and no Node has a Pod CIDR.
+	NodeIPAM string
+}
+
+// Network returns the cluster network as a config.openshift.io Network, the way it is known on
+// OpenShift, or nil if nothing was discovered.
+func (r *Result) Network() *configv1.Network {
+	if len(r.ServiceNetwork) == 0 && len(r.ClusterNetwork) == 0 {
+		return nil
+	}
+	network := &configv1.Network{}
+	network.Name = operatortypes.ClusterConfigName
+	network.Spec.ServiceNetwork = append([]string(nil), r.ServiceNetwork...)
+	network.Spec.ClusterNetwork = append([]configv1.ClusterNetworkEntry(nil), r.ClusterNetwork...)
+	network.Spec.NetworkType = "antrea"
+	network.Spec.ServiceNodePortRange = r.ServiceNodePortRange
+	return network
+}
+
+// AntreaNodeIPAM tells whether the antrea-controller should allocate the Pod CIDRs of the Nodes.
+func (r *Result) AntreaNodeIPAM() bool {
+	return r.NodeIPAM == NodeIPAMAntrea
+}
+
+// observation is what a single source tells about the cluster network.
+type observation struct {
+	serviceCIDRs []string
+	clusterCIDRs []string
+	maskSizeIPv4 int
+	maskSizeIPv6 int
+	// serviceNodePortRange is the NodePort range of kube-apiserver.
+	serviceNodePortRange string
+	// allocateNodeCIDRs is nil when the source does not tell whether kube-controller-manager
+	// allocates the Pod CIDRs of the Nodes.
+	allocateNodeCIDRs *bool
+}
+
+// merge fills the fields of the result which are still unknown from an observation.
+func (r *Result) merge(o *observation, source string) {
+	if len(r.ServiceNetwork) == 0 && len(o.serviceCIDRs) > 0 {
+		r.ServiceNetwork = o.serviceCIDRs
+		r.ServiceNetworkSource = source
+	}
+	if len(r.ClusterNetwork) == 0 && len(o.clusterCIDRs) > 0 {
+		for _, cidr := range o.clusterCIDRs {
+			entry := configv1.ClusterNetworkEntry{CIDR: cidr, HostPrefix: defaultNodeCIDRMaskSizeIPv4}
+			if o.maskSizeIPv4 > 0 {
+				entry.HostPrefix = uint32(o.maskSizeIPv4)
+			}
+			if net.IsIPv6CIDRString(cidr) {
+				entry.HostPrefix = defaultNodeCIDRMaskSizeIPv6
+				if o.maskSizeIPv6 > 0 {
+					entry.HostPrefix = uint32(o.maskSizeIPv6)
+				}
+			}
+			r.ClusterNetwork = append(r.ClusterNetwork, entry)
+		}
+		r.ClusterNetworkSource = source
+	}
+	if r.ServiceNodePortRange == "" {
+		r.ServiceNodePortRange = o.serviceNodePortRange
+	}
+	if r.NodeIPAM == "" && o.allocateNodeCIDRs != nil {
+		r.NodeIPAM = NodeIPAMAntrea
+		if *o.allocateNodeCIDRs {
+			r.NodeIPAM = NodeIPAMKubeControllerManager
+		}
+	}
+}
+
+// Discoverer discovers the service and Pod CIDRs of a Kubernetes cluster, which has no
+// cluster Network CR as OpenShift has.
+type Discoverer struct {
+	reader client.Reader
+}
+
+func NewDiscoverer(reader client.Reader) *Discoverer {
+	return &Discoverer{reader: reader}
+}
+
+// Discover consults, in order, the kubeadm-config ConfigMap, the args of the kube-apiserver and
+// kube-controller-manager static Pods, the ServiceCIDR API and the Pod CIDRs of the Nodes. The
+// first source which knows a value provides it. A source which cannot be read is skipped.
+func (d *Discoverer) Discover(ctx context.Context) *Result {
+	result := &Result{}
+	sources := []struct {
+		name    string
+		observe func(ctx context.Context) (*observation, error)
+	}{
+		{SourceKubeadmConfig, d.observeKubeadmConfig},
+		{SourceStaticPodArgs, d.observeStaticPods},
+		{SourceServiceCIDRAPI, d.observeServiceCIDRs},
+		{SourceNodePodCIDRs, d.observeNodes},
+	}
+	for _, source := range sources {
+		o, err := source.observe(ctx)
+		if err != nil {
+			log.Error(err, "Failed to read cluster network source, skipping it", "source", source.name)
+			continue
+		}
+		if o != nil {
+			result.merge(o, source.name)
+		}
+	}
+	// Without a cluster CIDR, antrea-controller has nothing to allocate the Node Pod CIDRs from.
+	if result.NodeIPAM == NodeIPAMAntrea && len(result.ClusterNetwork) == 0 {
+		result.NodeIPAM = ""
+	}
+	log.Info("Discovered cluster network", "serviceNetwork", result.ServiceNetwork, "serviceNetworkSource", result.ServiceNetworkSource,
+		"clusterNetwork", result.ClusterNetwork, "clusterNetworkSource", result.ClusterNetworkSource, "nodeIPAM", result.NodeIPAM)
+	return result
+}
+
+// kubeadmClusterConfiguration is the part of the kubeadm ClusterConfiguration the operator reads.
+type kubeadmClusterConfiguration struct {
+	Networking struct {
+		PodSubnet     string `json:"podSubnet"`
+		ServiceSubnet string `json:"serviceSubnet"`
+	} `json:"networking"`
+	APIServer struct {
+		// ExtraArgs is a map up to v1beta3 and a list of name/value pairs from v1beta4.
+		ExtraArgs interface{} `json:"extraArgs"`
+	} `json:"apiServer"`
+	ControllerManager struct {
+		ExtraArgs interface{} `json:"extraArgs"`
+	} `json:"controllerManager"`
+}
+
+func (d *Discoverer) observeKubeadmConfig(ctx context.Context) (*observation, error) {
+	cm := &corev1.ConfigMap{}
+	if err := d.reader.Get(ctx, types.NamespacedName{Namespace: kubeSystemNamespace, Name: kubeadmConfigMapName}, cm); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	data, ok := cm.Data[kubeadmClusterConfigKey]
+	if !ok {
+		return nil, nil
+	}
+	clusterConfig := kubeadmClusterConfiguration{}
+	if err := yaml.Unmarshal([]byte(data), &clusterConfig); err != nil {
+		return nil, fmt.Errorf("failed to parse %s of ConfigMap %s/%s: %v", kubeadmClusterConfigKey, kubeSystemNamespace, kubeadmConfigMapName, err)
+	}
+	o := observeControllerManagerArgs(kubeadmExtraArgs(clusterConfig.ControllerManager.ExtraArgs))
+	o.serviceCIDRs = splitCIDRs(clusterConfig.Networking.ServiceSubnet)
+	o.clusterCIDRs = splitCIDRs(clusterConfig.Networking.PodSubnet)
+	o.serviceNodePortRange = kubeadmExtraArgs(clusterConfig.APIServer.ExtraArgs)["service-node-port-range"]
+	// kubeadm turns on allocate-node-cidrs when a Pod subnet is set.
+	if o.allocateNodeCIDRs == nil && len(o.clusterCIDRs) > 0 {
+		allocate := true
+		o.allocateNodeCIDRs = &allocate
+	}
+	return o, nil
+}
+
+func kubeadmExtraArgs(extraArgs interface{}) map[string]string {
+	args := map[string]string{}
+	switch extraArgs := extraArgs.(type) {
+	case map[string]interface{}:
+		for name, value := range extraArgs {
+			args[name] = fmt.Sprint(value)
+		}
+	case []interface{}:
+		for _, arg := range extraArgs {
+			if arg, ok := arg.(map[string]interface{}); ok {
+				args[fmt.Sprint(arg["name"])] = fmt.Sprint(arg["value"])
+			}
+		}
+	}
+	return args
+}
+
+func (d *Discoverer) observeStaticPods(ctx context.Context) (*observation, error) {
+	apiServerArgs, err := d.staticPodArgs(ctx, "kube-apiserver")
+	if err != nil {
+		return nil, err
+	}
+	controllerManagerArgs, err := d.staticPodArgs(ctx, "kube-controller-manager")
+	if err != nil {
+		return nil, err
+	}
+	if apiServerArgs == nil && controllerManagerArgs == nil {
+		return nil, nil
+	}
+	o := &observation{}
+	if controllerManagerArgs != nil {
+		o = observeControllerManagerArgs(controllerManagerArgs)
+		// allocate-node-cidrs is off by default.
+		if o.allocateNodeCIDRs == nil {
+			allocate := false
+			o.allocateNodeCIDRs = &allocate
+		}
+	}
+	if serviceCIDRs := splitCIDRs(apiServerArgs["service-cluster-ip-range"]); len(serviceCIDRs) > 0 {
+		o.serviceCIDRs = serviceCIDRs
+	}
+	o.serviceNodePortRange = apiServerArgs["service-node-port-range"]
+	return o, nil
+}
+
+// staticPodArgs returns the flags of the first Pod of a control plane component, as labeled by
+// kubeadm, or nil if there is none.
+func (d *Discoverer) staticPodArgs(ctx context.Context, component string) (map[string]string, error) {
+	pods := &corev1.PodList{}
+	if err := d.reader.List(ctx, pods, client.InNamespace(kubeSystemNamespace), client.MatchingLabels{"component": component}); err != nil {
+		return nil, err
+	}
+	for i := range pods.Items {
+		for _, container := range pods.Items[i].Spec.Containers {
+			if container.Name == component {
+				return parseFlags(append(append([]string{}, container.Command...), container.Args...)), nil
+			}
+		}
+	}
+	return nil, nil
+}
+
+func observeControllerManagerArgs(args map[string]string) *observation {
+	o := &observation{
+		serviceCIDRs: splitCIDRs(args["service-cluster-ip-range"]),
+		clusterCIDRs: splitCIDRs(args["cluster-cidr"]),
+	}
+	if value, ok := args["allocate-node-cidrs"]; ok {
+		if allocate, err := strconv.ParseBool(value); err == nil {
+			o.allocateNodeCIDRs = &allocate
+		}
+	}
+	maskSize, _ := strconv.Atoi(args["node-cidr-mask-size"])
+	o.maskSizeIPv4, o.maskSizeIPv6 = maskSize, maskSize
+	if maskSize, err := strconv.Atoi(args["node-cidr-mask-size-ipv4"]); err == nil {
+		o.maskSizeIPv4 = maskSize
+	}
+	if maskSize, err := strconv.Atoi(args["node-cidr-mask-size-ipv6"]); err == nil {
+		o.maskSizeIPv6 = maskSize
+	}
+	return o
+}
+
+// parseFlags returns the values of the --name=value flags of a command line. A flag without a
+// value is true.
+func parseFlags(commandLine []string) map[string]string {
+	flags := map[string]string{}
+	for _, arg := range commandLine {
+		if !strings.HasPrefix(arg, "--") {
+			continue
+		}
+		name, value, found := strings.Cut(strings.TrimPrefix(arg, "--"), "=")
+		if !found {
+			value = "true"
+		}
+		flags[name] = value
+	}
+	return flags
+}
+
+// observeServiceCIDRs reads the default ServiceCIDR, from networking.k8s.io/v1 or v1beta1,
+// whichever is served.
+func (d *Discoverer) observeServiceCIDRs(ctx context.Context) (*observation, error) {
+	for _, version := range []string{"v1", "v1beta1"} {
+		serviceCIDRs := &uns.Unstructured{}
+		serviceCIDRs.SetGroupVersionKind(schema.GroupVersionKind{Group: "networking.k8s.io", Version: version, Kind: "ServiceCIDR"})
+		err := d.reader.Get(ctx, types.NamespacedName{Name: defaultServiceCIDRName}, serviceCIDRs)
+		if meta.IsNoMatchError(err) {
+			continue
+		}
+		if apierrors.IsNotFound(err) {
+			return nil, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		cidrs, _, err := uns.NestedStringSlice(serviceCIDRs.Object, "spec", "cidrs")
+		if err != nil {
+			return nil, err
+		}
+		return &observation{serviceCIDRs: cidrs}, nil
+	}
+	return nil, nil
+}
+
+// observeNodes tells whether the Pod CIDRs of the Nodes are allocated by kube-controller-manager.
+// A Node Pod CIDR is only a part of the cluster CIDR, so it does not provide the cluster network.
+func (d *Discoverer) observeNodes(ctx context.Context) (*observation, error) {
+	nodes := &corev1.NodeList{}
+	if err := d.reader.List(ctx, nodes); err != nil {
+		return nil, err
+	}
+	for i := range nodes.Items {
+		if len(nodes.Items[i].Spec.PodCIDRs) > 0 || nodes.Items[i].Spec.PodCIDR != "" {
+			allocate := true
+			return &observation{allocateNodeCIDRs: &allocate}, nil
+		}
+	}
+	return nil, nil
+}
+
+func splitCIDRs(cidrs string) []string {
+	var result []string
+	for _, cidr := range strings.Split(cidrs, ",") {
+		if cidr = strings.TrimSpace(cidr); cidr != "" {
+			result = append(result, cidr)
+		}
+	}
+	return result
+}
@@ -0,0 +1,342 @@
+/* Copyright © 2026 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package clusternetwork
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ghodss/yaml"
+	. "github.com/onsi/gomega"
+	configv1 "github.com/openshift/api/config/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	uns "k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestParseFlags(t *testing.T) {
+	g := NewGomegaWithT(t)
+	for _, tc := range []struct {
+		name        string
+		commandLine []string
+		expected    map[string]string
+	}{
+		{name: "empty", expected: map[string]string{}},
+		{
+			name:        "values",
+			commandLine: []string{"kube-controller-manager", "--cluster-cidr=10.244.0.0/16", "--node-cidr-mask-size=25"},
+			expected:    map[string]string{"cluster-cidr": "10.244.0.0/16", "node-cidr-mask-size": "25"},
+		},
+		{
+			name:        "boolean flag without value",
+			commandLine: []string{"--allocate-node-cidrs", "--use-service-account-credentials=false"},
+			expected:    map[string]string{"allocate-node-cidrs": "true", "use-service-account-credentials": "false"},
+		},
+		{
+			name:        "value with equal sign",
+			commandLine: []string{"--feature-gates=A=true,B=false"},
+			expected:    map[string]string{"feature-gates": "A=true,B=false"},
+		},
+		{
+			name:        "non flag arguments are skipped",
+			commandLine: []string{"kube-apiserver", "-v=2", "positional", "--service-cluster-ip-range=10.96.0.0/12"},
+			expected:    map[string]string{"service-cluster-ip-range": "10.96.0.0/12"},
+		},
+		{
+			name:        "last value wins",
+			commandLine: []string{"--cluster-cidr=10.0.0.0/16", "--cluster-cidr=10.1.0.0/16"},
+			expected:    map[string]string{"cluster-cidr": "10.1.0.0/16"},
+		},
+	} {
+		g.Expect(parseFlags(tc.commandLine)).Should(Equal(tc.expected), tc.name)
+	}
+}
+
+func TestKubeadmExtraArgs(t *testing.T) {
+	g := NewGomegaWithT(t)
+	for _, tc := range []struct {
+		name     string
+		config   string
+		expected map[string]string
+	}{
+		{name: "no extraArgs", config: `controllerManager: {}`, expected: map[string]string{}},
+		{
+			name: "v1beta3 map",
+			config: `
+controllerManager:
+  extraArgs:
+    allocate-node-cidrs: "true"
+    node-cidr-mask-size: 26
+`,
+			expected: map[string]string{"allocate-node-cidrs": "true", "node-cidr-mask-size": "26"},
+		},
+		{
+			name: "v1beta4 list",
+			config: `
+controllerManager:
+  extraArgs:
+  - name: allocate-node-cidrs
+    value: "false"
+  - name: node-cidr-mask-size-ipv6
+    value: "80"
+`,
+			expected: map[string]string{"allocate-node-cidrs": "false", "node-cidr-mask-size-ipv6": "80"},
+		},
+		{
+			name: "invalid list items are skipped",
+			config: `
+controllerManager:
+  extraArgs:
+  - node-cidr-mask-size
+  - name: cluster-cidr
+    value: 10.244.0.0/16
+`,
+			expected: map[string]string{"cluster-cidr": "10.244.0.0/16"},
+		},
+	} {
+		clusterConfig := kubeadmClusterConfiguration{}
+		g.Expect(yaml.Unmarshal([]byte(tc.config), &clusterConfig)).Should(Succeed(), tc.name)
+		g.Expect(kubeadmExtraArgs(clusterConfig.ControllerManager.ExtraArgs)).Should(Equal(tc.expected), tc.name)
+	}
+}
+
+func kubeadmConfigMap(clusterConfiguration string) *corev1.ConfigMap {
+	return &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Namespace: kubeSystemNamespace, Name: kubeadmConfigMapName},
+		Data:       map[string]string{kubeadmClusterConfigKey: clusterConfiguration},
+	}
+}
+
+func staticPod(component string, args ...string) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Namespace: kubeSystemNamespace, Name: component + "-node1", Labels: map[string]string{"component": component}},
+		Spec: corev1.PodSpec{Containers: []corev1.Container{{
+			Name:    component,
+			Command: append([]string{component}, args...),
+		}}},
+	}
+}
+
+func serviceCIDR(version string, cidrs ...string) *uns.Unstructured {
+	obj := &uns.Unstructured{}
+	obj.SetAPIVersion("networking.k8s.io/" + version)
+	obj.SetKind("ServiceCIDR")
+	obj.SetName(defaultServiceCIDRName)
+	var values []interface{}
+	for _, cidr := range cidrs {
+		values = append(values, cidr)
+	}
+	uns.SetNestedSlice(obj.Object, values, "spec", "cidrs")
+	return obj
+}
+
+func nodeWithPodCIDR(cidr string) *corev1.Node {
+	return &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node1"}, Spec: corev1.NodeSpec{PodCIDR: cidr, PodCIDRs: []string{cidr}}}
+}
+
+// noServiceCIDRv1Reader serves the ServiceCIDR API in networking.k8s.io/v1beta1 only.
+type noServiceCIDRv1Reader struct {
+	client.Reader
+}
+
+func (r *noServiceCIDRv1Reader) Get(ctx context.Context, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+	if gvk := obj.GetObjectKind().GroupVersionKind(); gvk.Kind == "ServiceCIDR" && gvk.Version == "v1" {
+		return &meta.NoKindMatchError{GroupKind: gvk.GroupKind(), SearchedVersions: []string{gvk.Version}}
+	}
+	return r.Reader.Get(ctx, key, obj, opts...)
+}
+
+func TestDiscover(t *testing.T) {
+	g := NewGomegaWithT(t)
+	kubeadmConfig := kubeadmConfigMap(`
+networking:
+  podSubnet: 10.244.0.0/16
+  serviceSubnet: 10.96.0.0/12
+apiServer:
+  extraArgs:
+    service-node-port-range: 30000-31000
+controllerManager:
+  extraArgs:
+  - name: node-cidr-mask-size
+    value: "25"
+`)
+	apiServer := staticPod("kube-apiserver", "--service-cluster-ip-range=10.100.0.0/16", "--service-node-port-range=32000-32767")
+	controllerManager := staticPod("kube-controller-manager", "--cluster-cidr=10.200.0.0/16", "--node-cidr-mask-size=26", "--allocate-node-cidrs=true")
+
+	for _, tc := range []struct {
+		name          string
+		objs          []client.Object
+		noServiceCIDR bool
+		expected      Result
+	}{
+		{
+			name:     "nothing discovered",
+			expected: Result{},
+		},
+		{
+			name: "kubeadm-config",
+			objs: []client.Object{kubeadmConfig},
+			expected: Result{
+				ServiceNetwork:       []string{"10.96.0.0/12"},
+				ServiceNetworkSource: SourceKubeadmConfig,
+				ClusterNetwork:       []configv1.ClusterNetworkEntry{{CIDR: "10.244.0.0/16", HostPrefix: 25}},
+				ClusterNetworkSource: SourceKubeadmConfig,
+				ServiceNodePortRange: "30000-31000",
+				NodeIPAM:             NodeIPAMKubeControllerManager,
+			},
+		},
+		{
+			// kubeadm-config takes precedence over the static Pods, the ServiceCIDR API and the Nodes.
+			name: "kubeadm-config over the other sources",
+			objs: []client.Object{kubeadmConfig, apiServer, controllerManager, serviceCIDR("v1", "10.50.0.0/16"), nodeWithPodCIDR("10.244.1.0/25")},
+			expected: Result{
+				ServiceNetwork:       []string{"10.96.0.0/12"},
+				ServiceNetworkSource: SourceKubeadmConfig,
+				ClusterNetwork:       []configv1.ClusterNetworkEntry{{CIDR: "10.244.0.0/16", HostPrefix: 25}},
+				ClusterNetworkSource: SourceKubeadmConfig,
+				ServiceNodePortRange: "30000-31000",
+				NodeIPAM:             NodeIPAMKubeControllerManager,
+			},
+		},
+		{
+			name: "static Pods",
+			objs: []client.Object{apiServer, controllerManager, serviceCIDR("v1", "10.50.0.0/16")},
+			expected: Result{
+				ServiceNetwork:       []string{"10.100.0.0/16"},
+				ServiceNetworkSource: SourceStaticPodArgs,
+				ClusterNetwork:       []configv1.ClusterNetworkEntry{{CIDR: "10.200.0.0/16", HostPrefix: 26}},
+				ClusterNetworkSource: SourceStaticPodArgs,
+				ServiceNodePortRange: "32000-32767",
+				NodeIPAM:             NodeIPAMKubeControllerManager,
+			},
+		},
+		{
+			// The static Pods complete the values kubeadm-config does not know.
+			name: "kubeadm-config completed by the static Pods",
+			objs: []client.Object{kubeadmConfigMap("networking:\n  podSubnet: 10.244.0.0/16\n"), apiServer, controllerManager},
+			expected: Result{
+				ServiceNetwork:       []string{"10.100.0.0/16"},
+				ServiceNetworkSource: SourceStaticPodArgs,
+				ClusterNetwork:       []configv1.ClusterNetworkEntry{{CIDR: "10.244.0.0/16", HostPrefix: defaultNodeCIDRMaskSizeIPv4}},
+				ClusterNetworkSource: SourceKubeadmConfig,
+				ServiceNodePortRange: "32000-32767",
+				NodeIPAM:             NodeIPAMKubeControllerManager,
+			},
+		},
+		{
+			// kube-controller-manager does not allocate the Node Pod CIDRs by default.
+			name: "static Pods without allocate-node-cidrs",
+			objs: []client.Object{staticPod("kube-controller-manager", "--cluster-cidr=10.200.0.0/16")},
+			expected: Result{
+				ClusterNetwork:       []configv1.ClusterNetworkEntry{{CIDR: "10.200.0.0/16", HostPrefix: defaultNodeCIDRMaskSizeIPv4}},
+				ClusterNetworkSource: SourceStaticPodArgs,
+				NodeIPAM:             NodeIPAMAntrea,
+			},
+		},
+		{
+			name: "ServiceCIDR API",
+			objs: []client.Object{serviceCIDR("v1", "10.50.0.0/16", "fd00:50::/108"), nodeWithPodCIDR("10.244.1.0/24")},
+			expected: Result{
+				ServiceNetwork:       []string{"10.50.0.0/16", "fd00:50::/108"},
+				ServiceNetworkSource: SourceServiceCIDRAPI,
+				NodeIPAM:             NodeIPAMKubeControllerManager,
+			},
+		},
+		{
+			name:          "ServiceCIDR API v1beta1",
+			objs:          []client.Object{serviceCIDR("v1beta1", "10.60.0.0/16")},
+			noServiceCIDR: true,
+			expected: Result{
+				ServiceNetwork:       []string{"10.60.0.0/16"},
+				ServiceNetworkSource: SourceServiceCIDRAPI,
+			},
+		},
+		{
+			// The Node Pod CIDRs do not provide the cluster network.
+			name:     "node Pod CIDRs",
+			objs:     []client.Object{nodeWithPodCIDR("10.244.1.0/24")},
+			expected: Result{NodeIPAM: NodeIPAMKubeControllerManager},
+		},
+		{
+			name: "IPv6 mask default",
+			objs: []client.Object{kubeadmConfigMap("networking:\n  podSubnet: fd00:10:244::/56\n")},
+			expected: Result{
+				ClusterNetwork:       []configv1.ClusterNetworkEntry{{CIDR: "fd00:10:244::/56", HostPrefix: defaultNodeCIDRMaskSizeIPv6}},
+				ClusterNetworkSource: SourceKubeadmConfig,
+				NodeIPAM:             NodeIPAMKubeControllerManager,
+			},
+		},
+		{
+			name: "dual-stack mask defaults",
+			objs: []client.Object{kubeadmConfigMap("networking:\n  podSubnet: 10.244.0.0/16,fd00:10:244::/56\n  serviceSubnet: 10.96.0.0/12,fd00:10:96::/112\n")},
+			expected: Result{
+				ServiceNetwork:       []string{"10.96.0.0/12", "fd00:10:96::/112"},
+				ServiceNetworkSource: SourceKubeadmConfig,
+				ClusterNetwork: []configv1.ClusterNetworkEntry{
+					{CIDR: "10.244.0.0/16", HostPrefix: defaultNodeCIDRMaskSizeIPv4},
+					{CIDR: "fd00:10:244::/56", HostPrefix: defaultNodeCIDRMaskSizeIPv6},
+				},
+				ClusterNetworkSource: SourceKubeadmConfig,
+				NodeIPAM:             NodeIPAMKubeControllerManager,
+			},
+		},
+		{
+			name: "dual-stack mask sizes",
+			objs: []client.Object{staticPod("kube-controller-manager", "--cluster-cidr=10.244.0.0/16,fd00:10:244::/56",
+				"--allocate-node-cidrs", "--node-cidr-mask-size-ipv4=25", "--node-cidr-mask-size-ipv6=72")},
+			expected: Result{
+				ClusterNetwork: []configv1.ClusterNetworkEntry{
+					{CIDR: "10.244.0.0/16", HostPrefix: 25},
+					{CIDR: "fd00:10:244::/56", HostPrefix: 72},
+				},
+				ClusterNetworkSource: SourceStaticPodArgs,
+				NodeIPAM:             NodeIPAMKubeControllerManager,
+			},
+		},
+		{
+			// Without cluster CIDR, antrea-controller cannot allocate the Node Pod CIDRs.
+			name:     "Antrea NodeIPAM without cluster CIDR",
+			objs:     []client.Object{staticPod("kube-controller-manager", "--allocate-node-cidrs=false")},
+			expected: Result{},
+		},
+		{
+			// An invalid kubeadm-config is skipped.
+			name: "invalid kubeadm-config",
+			objs: []client.Object{kubeadmConfigMap("networking: ["), apiServer},
+			expected: Result{
+				ServiceNetwork:       []string{"10.100.0.0/16"},
+				ServiceNetworkSource: SourceStaticPodArgs,
+				ServiceNodePortRange: "32000-32767",
+			},
+		},
+	} {
+		var reader client.Reader = fake.NewClientBuilder().WithScheme(clientgoscheme.Scheme).WithObjects(tc.objs...).Build()
+		if tc.noServiceCIDR {
+			reader = &noServiceCIDRv1Reader{Reader: reader}
+		}
+		g.Expect(*NewDiscoverer(reader).Discover(context.TODO())).Should(Equal(tc.expected), tc.name)
+	}
+}
+
+func TestNetwork(t *testing.T) {
+	g := NewGomegaWithT(t)
+	g.Expect((&Result{}).Network()).Should(BeNil())
+
+	result := &Result{
+		ServiceNetwork:       []string{"10.96.0.0/12"},
+		ClusterNetwork:       []configv1.ClusterNetworkEntry{{CIDR: "10.244.0.0/16", HostPrefix: 24}},
+		ServiceNodePortRange: "30000-31000",
+		NodeIPAM:             NodeIPAMAntrea,
+	}
+	network := result.Network()
+	g.Expect(network.Spec.ServiceNetwork).Should(Equal(result.ServiceNetwork))
+	g.Expect(network.Spec.ClusterNetwork).Should(Equal(result.ClusterNetwork))
+	g.Expect(network.Spec.ServiceNodePortRange).Should(Equal("30000-31000"))
+	g.Expect(network.Spec.NetworkType).Should(Equal("antrea"))
+	g.Expect(result.AntreaNodeIPAM()).Should(BeTrue())
+}
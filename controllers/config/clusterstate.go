@@ -0,0 +1,241 @@
+/* Copyright © 2026 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package config
+
+import (
+	"bytes"
+	"fmt"
+	gonet "net"
+	"sort"
+	"strconv"
+	"strings"
+
+	configv1 "github.com/openshift/api/config/v1"
+
+	operatorv1 "github.com/vmware/antrea-operator-for-kubernetes/api/v1"
+	"github.com/vmware/antrea-operator-for-kubernetes/controllers/types"
+)
+
+const (
+	transportInterfaceCIDRsOption = "transportInterfaceCIDRs"
+	nodePortLocalOption           = "nodePortLocal"
+
+	// defaultServiceNodePortRange is the kube-apiserver default of --service-node-port-range.
+	defaultServiceNodePortRange = "30000-32767"
+	// defaultNodePortLocalPortRange is the antrea-agent default of nodePortLocal.portRange.
+	defaultNodePortLocalPortRange = "61000-62000"
+)
+
+// ClusterState holds the addresses in use in the cluster which the Antrea configurations must
+// not overlap with, and the Node properties and applied configurations the configurations are
+// derived from.
+type ClusterState struct {
+	// NodeInternalIPs maps the Node names to their InternalIPs.
+	NodeInternalIPs map[string][]string
+	// ExternalIPPools maps the ExternalIPPool names to their IP ranges, CIDRs or start-end ranges.
+	ExternalIPPools map[string][]string
+	// NodePodCIDRs maps the Node names to their Pod CIDRs, for the Nodes which have some.
+	NodePodCIDRs map[string][]string
+	// NodeUplinkMTUs maps the Node names to the MTU of their uplink, for the Nodes annotated with
+	// it.
+	NodeUplinkMTUs map[string]int
+	// AppliedConfig holds the configurations of the running installation, nil on a fresh
+	// installation.
+	AppliedConfig *operatorv1.AntreaInstall
+}
+
+// ValidationErrors holds all the findings of a validation, one per line.
+type ValidationErrors []error
+
+func (errs ValidationErrors) Error() string {
+	msgs := make([]string, 0, len(errs))
+	for _, err := range errs {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "\n")
+}
+
+// ipRange is an inclusive range of addresses, in their 16-byte form.
+type ipRange struct {
+	name  string
+	first gonet.IP
+	last  gonet.IP
+}
+
+func parseIPRange(value string) (*ipRange, error) {
+	if _, ipNet, err := gonet.ParseCIDR(value); err == nil {
+		last := make(gonet.IP, len(ipNet.IP))
+		for i := range ipNet.IP {
+			last[i] = ipNet.IP[i] | ^ipNet.Mask[i]
+		}
+		return &ipRange{name: value, first: ipNet.IP.To16(), last: last.To16()}, nil
+	}
+	if start, end, found := strings.Cut(value, "-"); found {
+		first, last := gonet.ParseIP(strings.TrimSpace(start)), gonet.ParseIP(strings.TrimSpace(end))
+		if first != nil && last != nil && (first.To4() == nil) == (last.To4() == nil) && bytes.Compare(first.To16(), last.To16()) <= 0 {
+			return &ipRange{name: value, first: first.To16(), last: last.To16()}, nil
+		}
+	}
+	return nil, fmt.Errorf("invalid IP range %q", value)
+}
+
+func (r *ipRange) overlaps(other *ipRange) bool {
+	return bytes.Compare(r.first, other.last) <= 0 && bytes.Compare(other.first, r.last) <= 0
+}
+
+func (r *ipRange) contains(ip gonet.IP) bool {
+	return bytes.Compare(r.first, ip.To16()) <= 0 && bytes.Compare(ip.To16(), r.last) <= 0
+}
+
+// parseIPRanges parses the valid ranges, the invalid ones are reported by the other validations.
+func parseIPRanges(values []string) []*ipRange {
+	var ranges []*ipRange
+	for _, value := range values {
+		if r, err := parseIPRange(value); err == nil {
+			ranges = append(ranges, r)
+		}
+	}
+	return ranges
+}
+
+func sortedKeys(m map[string][]string) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// validateNetworkOverlaps checks that the cluster and service networks do not overlap with each
+// other, with the Node InternalIPs, with the ExternalIPPools and with the transport interface
+// CIDRs of the agent configuration.
+func validateNetworkOverlaps(clusterConfig *configv1.Network, antreaAgentConfig map[string]interface{}, state *ClusterState) []error {
+	var errs []error
+	var serviceCIDRs, clusterCIDRs []string
+	if clusterConfig != nil && len(clusterConfig.Spec.ServiceNetwork) > 0 {
+		serviceCIDRs = clusterConfig.Spec.ServiceNetwork
+	} else {
+		for _, option := range []string{types.ServiceCIDROption, types.ServiceCIDRv6Option} {
+			if serviceCIDR, ok := antreaAgentConfig[option].(string); ok {
+				serviceCIDRs = append(serviceCIDRs, serviceCIDR)
+			}
+		}
+	}
+	if clusterConfig != nil {
+		for _, entry := range clusterConfig.Spec.ClusterNetwork {
+			clusterCIDRs = append(clusterCIDRs, entry.CIDR)
+		}
+	}
+	services, pods := parseIPRanges(serviceCIDRs), parseIPRanges(clusterCIDRs)
+	networks := map[string][]*ipRange{"cluster network": pods, "service network": services}
+	networkNames := []string{"cluster network", "service network"}
+
+	for _, pod := range pods {
+		for _, service := range services {
+			if pod.overlaps(service) {
+				errs = append(errs, fmt.Errorf("cluster network %s overlaps with service network %s", pod.name, service.name))
+			}
+		}
+	}
+
+	var transportCIDRs []string
+	if values, ok := antreaAgentConfig[transportInterfaceCIDRsOption].([]interface{}); ok {
+		for _, value := range values {
+			transportCIDRs = append(transportCIDRs, fmt.Sprint(value))
+		}
+	}
+	for _, transport := range transportCIDRs {
+		transportRange, err := parseIPRange(transport)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("invalid %s: %v", transportInterfaceCIDRsOption, err))
+			continue
+		}
+		for _, networkName := range networkNames {
+			for _, network := range networks[networkName] {
+				if transportRange.overlaps(network) {
+					errs = append(errs, fmt.Errorf("transport interface CIDR %s overlaps with %s %s", transport, networkName, network.name))
+				}
+			}
+		}
+	}
+
+	if state == nil {
+		return errs
+	}
+	for _, node := range sortedKeys(state.NodeInternalIPs) {
+		for _, address := range state.NodeInternalIPs[node] {
+			ip := gonet.ParseIP(address)
+			if ip == nil {
+				continue
+			}
+			for _, networkName := range networkNames {
+				for _, network := range networks[networkName] {
+					if network.contains(ip) {
+						errs = append(errs, fmt.Errorf("InternalIP %s of Node %s is in %s %s", address, node, networkName, network.name))
+					}
+				}
+			}
+		}
+	}
+	for _, pool := range sortedKeys(state.ExternalIPPools) {
+		for _, value := range state.ExternalIPPools[pool] {
+			poolRange, err := parseIPRange(value)
+			if err != nil {
+				continue
+			}
+			for _, networkName := range networkNames {
+				for _, network := range networks[networkName] {
+					if poolRange.overlaps(network) {
+						errs = append(errs, fmt.Errorf("range %s of ExternalIPPool %s overlaps with %s %s", value, pool, networkName, network.name))
+					}
+				}
+			}
+		}
+	}
+	return errs
+}
+
+// parsePortRange parses a port range formatted as "first-last".
+func parsePortRange(portRange string) (int, int, error) {
+	start, end, found := strings.Cut(portRange, "-")
+	if found {
+		first, err1 := strconv.Atoi(strings.TrimSpace(start))
+		last, err2 := strconv.Atoi(strings.TrimSpace(end))
+		if err1 == nil && err2 == nil && first > 0 && first <= last && last <= 65535 {
+			return first, last, nil
+		}
+	}
+	return 0, 0, fmt.Errorf("invalid port range %q", portRange)
+}
+
+// validateNodePortLocal checks that the NodePortLocal port range, when NodePortLocal is enabled,
+// does not collide with the NodePort range of kube-apiserver.
+func validateNodePortLocal(clusterConfig *configv1.Network, antreaAgentConfig map[string]interface{}) []error {
+	nodePortLocal, ok := antreaAgentConfig[nodePortLocalOption].(map[interface{}]interface{})
+	if !ok || nodePortLocal["enable"] != true {
+		return nil
+	}
+	portRange := defaultNodePortLocalPortRange
+	if value, ok := nodePortLocal["portRange"].(string); ok && value != "" {
+		portRange = value
+	}
+	nodePortRange := defaultServiceNodePortRange
+	if clusterConfig != nil && clusterConfig.Spec.ServiceNodePortRange != "" {
+		nodePortRange = clusterConfig.Spec.ServiceNodePortRange
+	}
+
+	first, last, err := parsePortRange(portRange)
+	if err != nil {
+		return []error{fmt.Errorf("invalid nodePortLocal.portRange: %v", err)}
+	}
+	nodePortFirst, nodePortLast, err := parsePortRange(nodePortRange)
+	if err != nil {
+		return []error{fmt.Errorf("invalid service NodePort range: %v", err)}
+	}
+	if first <= nodePortLast && nodePortFirst <= last {
+		return []error{fmt.Errorf("nodePortLocal.portRange %s collides with the service NodePort range %s", portRange, nodePortRange)}
+	}
+	return nil
+}
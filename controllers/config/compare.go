@@ -0,0 +1,49 @@
+/* Copyright © 2026 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package config
+
+import (
+	"fmt"
+	"reflect"
+
+	"gopkg.in/yaml.v2"
+)
+
+// configEqual tells whether two configurations, YAML or JSON, are semantically equal: formatting,
+// key order, comments and null values are ignored. Antrea reads its configurations only at
+// startup, so any other difference is a change which requires a restart. Configurations which
+// cannot be parsed are compared as strings.
+func configEqual(a, b string) bool {
+	if a == b {
+		return true
+	}
+	var valueA, valueB interface{}
+	if yaml.Unmarshal([]byte(a), &valueA) != nil || yaml.Unmarshal([]byte(b), &valueB) != nil {
+		return false
+	}
+	return reflect.DeepEqual(normalizeConfig(valueA), normalizeConfig(valueB))
+}
+
+// normalizeConfig converts a value decoded by yaml.v2 into maps keyed by strings, without the null
+// values.
+func normalizeConfig(value interface{}) interface{} {
+	switch value := value.(type) {
+	case map[interface{}]interface{}:
+		normalized := make(map[string]interface{}, len(value))
+		for key, item := range value {
+			if item != nil {
+				normalized[fmt.Sprint(key)] = normalizeConfig(item)
+			}
+		}
+		return normalized
+	case []interface{}:
+		normalized := make([]interface{}, len(value))
+		for i, item := range value {
+			normalized[i] = normalizeConfig(item)
+		}
+		return normalized
+	default:
+		return value
+	}
+}
@@ -4,61 +4,107 @@
 package config
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
 	"errors"
 	"fmt"
-
-	"k8s.io/utils/net"
+	"reflect"
+	"strings"
+	"time"
 
 	ctlconfig "antrea.io/antrea/pkg/config/controller"
 	"antrea.io/antrea/pkg/features"
-	gocni "github.com/containerd/go-cni"
 	configv1 "github.com/openshift/api/config/v1"
 	ocoperv1 "github.com/openshift/api/operator/v1"
 	"github.com/openshift/cluster-network-operator/pkg/network"
 	"github.com/openshift/cluster-network-operator/pkg/render"
 	"gopkg.in/yaml.v2"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	ctrl "sigs.k8s.io/controller-runtime"
 
 	operatorv1 "github.com/vmware/antrea-operator-for-kubernetes/api/v1"
+	"github.com/vmware/antrea-operator-for-kubernetes/controllers/manifestsource"
 	"github.com/vmware/antrea-operator-for-kubernetes/controllers/types"
 	"github.com/vmware/antrea-operator-for-kubernetes/internal/version"
 )
 
+const (
+	ApprovalPolicyAutomatic = "Automatic"
+	ApprovalPolicyManual    = "Manual"
+
+	// DefaultApprovalTTL is how long an approval is valid when spec.approvalTTL is not set.
+	DefaultApprovalTTL = 24 * time.Hour
+
+	// revisionLength is the number of hexadecimal digits of a configuration revision.
+	revisionLength = 12
+)
+
 var log = ctrl.Log.WithName("config")
 
 type Config interface {
-	FillConfigs(clusterConfig *configv1.Network, operConfig *operatorv1.AntreaInstall) error
-	ValidateConfig(clusterConfig *configv1.Network, operConfig *operatorv1.AntreaInstall) error
+	FillConfigs(clusterConfig *configv1.Network, operConfig *operatorv1.AntreaInstall, state *ClusterState) error
+	ValidateConfig(clusterConfig *configv1.Network, operConfig *operatorv1.AntreaInstall, state *ClusterState) error
 	GenerateRenderData(operatorNetwork *ocoperv1.Network, operConfig *operatorv1.AntreaInstall) (*render.RenderData, error)
 }
 
-type ConfigOc struct{}
+type ConfigOc struct {
+	// ManifestDir is the directory of the bundled manifests, whose latest Antrea version tags the
+	// default image.
+	ManifestDir string
+}
+
+// ConfigK8s is the Config of the kubernetes platform, with the defaults of a distribution
+// Profile. The generic profile is used when Profile is nil.
+type ConfigK8s struct {
+	Profile *Profile
+	// NodeIPAM tells whether antrea-controller allocates the Pod CIDRs of the Nodes from the
+	// discovered cluster network.
+	NodeIPAM bool
+	// ManifestDir is the directory of the bundled manifests, whose latest Antrea version tags the
+	// default image.
+	ManifestDir string
+}
 
-type ConfigK8s struct{}
+func (c *ConfigK8s) profile() *Profile {
+	if c.Profile == nil {
+		return Profiles[ProfileGeneric]
+	}
+	return c.Profile
+}
 
-func fillAgentConfig(clusterConfig *configv1.Network, operConfig *operatorv1.AntreaInstall) error {
+func fillAgentConfig(clusterConfig *configv1.Network, operConfig *operatorv1.AntreaInstall, profile *Profile, state *ClusterState) error {
 	antreaAgentConfig := make(map[string]interface{})
 	err := yaml.Unmarshal([]byte(operConfig.Spec.AntreaAgentConfig), &antreaAgentConfig)
 	if err != nil {
 		return fmt.Errorf("failed to parse AntreaAgentConfig: %v", err)
 	}
-	// Set service CIDR.
-	if clusterConfig == nil {
-		if _, ok := antreaAgentConfig[types.ServiceCIDROption]; !ok {
-			return errors.New("serviceCIDR should be specified on kubernetes.")
+	// Set service CIDRs of both IP families.
+	if clusterConfig == nil || len(clusterConfig.Spec.ServiceNetwork) == 0 {
+		_, ipv4 := antreaAgentConfig[types.ServiceCIDROption]
+		_, ipv6 := antreaAgentConfig[types.ServiceCIDRv6Option]
+		if !ipv4 && !ipv6 {
+			return errors.New("serviceCIDR or serviceCIDRv6 should be specified on kubernetes.")
 		}
 	} else {
-		if serviceCIDR, ok := antreaAgentConfig[types.ServiceCIDROption].(string); !ok {
-			antreaAgentConfig[types.ServiceCIDROption] = clusterConfig.Spec.ServiceNetwork[0]
-		} else if found := inSlice(serviceCIDR, clusterConfig.Spec.ServiceNetwork); !found {
-			log.Info("WARNING: ServiceCIDROption is overwritten by cluster config")
-			antreaAgentConfig[types.ServiceCIDROption] = clusterConfig.Spec.ServiceNetwork[0]
-		}
+		families, _ := splitNetworkFamilies(clusterConfig)
+		fillServiceCIDR(antreaAgentConfig, types.ServiceCIDROption, families.serviceIPv4)
+		fillServiceCIDR(antreaAgentConfig, types.ServiceCIDRv6Option, families.serviceIPv6)
+	}
+	// Set the traffic options and the default traffic mode, then the MTU which depends on them.
+	defaults := selectDatapathDefaults(operConfig, profile, state)
+	fillTraffic(antreaAgentConfig, operConfig.Spec.Traffic)
+	fillCNIChaining(antreaAgentConfig, operConfig, defaults.cniChaining)
+	if _, ok := antreaAgentConfig[trafficEncapModeOption]; !ok {
+		antreaAgentConfig[trafficEncapModeOption] = defaults.trafficEncapMode
+	}
+	if err := fillDefaultMTU(antreaAgentConfig, operConfig.Spec.DefaultMTU, defaults.defaultMTU, state); err != nil {
+		return err
 	}
-	// Set default MTU.
-	_, ok := antreaAgentConfig[types.DefaultMTUOption]
-	if !ok {
-		antreaAgentConfig[types.DefaultMTUOption] = types.DefaultMTU
+	// Set the feature gates required by the datapath of the profile.
+	if antreaAgentConfig[trafficEncapModeOption] == profile.TrafficEncapMode {
+		setFeatureGates(antreaAgentConfig, profile.AgentFeatureGates)
 	}
 	updatedAntreaAgentConfig, err := yaml.Marshal(antreaAgentConfig)
 	if err != nil {
@@ -68,56 +114,59 @@ func fillAgentConfig(clusterConfig *configv1.Network, operConfig *operatorv1.Ant
 	return nil
 }
 
-func fillControllerConfig(clusterConfig *configv1.Network, operConfig *operatorv1.AntreaInstall) error {
+// setFeatureGates sets gates in the featureGates option of a configuration parsed as a map,
+// overriding the values set by the user.
+func setFeatureGates(config map[string]interface{}, gates map[string]bool) {
+	if len(gates) == 0 {
+		return
+	}
+	featureGates, ok := config[featureGatesOption].(map[interface{}]interface{})
+	if !ok {
+		featureGates = make(map[interface{}]interface{})
+	}
+	for name, enabled := range gates {
+		featureGates[name] = enabled
+	}
+	config[featureGatesOption] = featureGates
+}
+
+func fillControllerConfig(clusterConfig *configv1.Network, operConfig *operatorv1.AntreaInstall, profile *Profile) error {
 	var controllerConfig ctlconfig.ControllerConfig
 	err := yaml.Unmarshal([]byte(operConfig.Spec.AntreaControllerConfig), &controllerConfig)
 	if err != nil {
 		return fmt.Errorf("failed to parse AntreaControllerConfig: %v", err)
 	}
 
-	//Turn on NodeIPAM featureGate.
+	// Turn on the feature gates required by the profile, and NodeIPAM.
 	if controllerConfig.FeatureGates == nil {
 		controllerConfig.FeatureGates = make(map[string]bool)
 	}
+	for name, enabled := range profile.ControllerFeatureGates {
+		controllerConfig.FeatureGates[name] = enabled
+	}
 	controllerConfig.FeatureGates[string(features.NodeIPAM)] = true
 	controllerConfig.NodeIPAM.EnableNodeIPAM = true
 
 	if clusterConfig != nil {
-		ip4found := false
-		ip6found := false
-
-		for _, cidr := range clusterConfig.Spec.ClusterNetwork {
-			if net.IsIPv4CIDRString(cidr.CIDR) {
-				if !ip4found {
-					ip4found = true
-					controllerConfig.NodeIPAM.ClusterCIDRs = append(controllerConfig.NodeIPAM.ClusterCIDRs, cidr.CIDR)
-					controllerConfig.NodeIPAM.NodeCIDRMaskSizeIPv4 = int(cidr.HostPrefix)
-				}
-			} else {
-				if !ip6found {
-					ip6found = true
-					controllerConfig.NodeIPAM.ClusterCIDRs = append(controllerConfig.NodeIPAM.ClusterCIDRs, cidr.CIDR)
-					controllerConfig.NodeIPAM.NodeCIDRMaskSizeIPv6 = int(cidr.HostPrefix)
-				}
-			}
+		families, _ := splitNetworkFamilies(clusterConfig)
+
+		// Set cluster CIDRs, all the entries of a family share the same host prefix.
+		controllerConfig.NodeIPAM.ClusterCIDRs = nil
+		for _, entry := range families.clusterIPv4 {
+			controllerConfig.NodeIPAM.ClusterCIDRs = append(controllerConfig.NodeIPAM.ClusterCIDRs, entry.CIDR)
+			controllerConfig.NodeIPAM.NodeCIDRMaskSizeIPv4 = int(entry.HostPrefix)
+		}
+		for _, entry := range families.clusterIPv6 {
+			controllerConfig.NodeIPAM.ClusterCIDRs = append(controllerConfig.NodeIPAM.ClusterCIDRs, entry.CIDR)
+			controllerConfig.NodeIPAM.NodeCIDRMaskSizeIPv6 = int(entry.HostPrefix)
 		}
 
-		// Set service CIDR
-		ip4found = false
-		ip6found = false
-
-		for _, svcCIDR := range clusterConfig.Spec.ServiceNetwork {
-			if net.IsIPv4CIDRString(svcCIDR) {
-				if !ip4found {
-					ip4found = true
-					controllerConfig.NodeIPAM.ServiceCIDR = svcCIDR
-				}
-			} else {
-				if !ip6found {
-					ip6found = true
-					controllerConfig.NodeIPAM.ServiceCIDRv6 = svcCIDR
-				}
-			}
+		// Set service CIDRs.
+		if len(families.serviceIPv4) > 0 {
+			controllerConfig.NodeIPAM.ServiceCIDR = families.serviceIPv4[0]
+		}
+		if len(families.serviceIPv6) > 0 {
+			controllerConfig.NodeIPAM.ServiceCIDRv6 = families.serviceIPv6[0]
 		}
 	}
 
@@ -129,86 +178,153 @@ func fillControllerConfig(clusterConfig *configv1.Network, operConfig *operatorv
 	return nil
 }
 
-func fillConfig(clusterConfig *configv1.Network, operConfig *operatorv1.AntreaInstall, isOpenShift bool) error {
-	err := fillAgentConfig(clusterConfig, operConfig)
+func fillConfig(clusterConfig *configv1.Network, operConfig *operatorv1.AntreaInstall, state *ClusterState, profile *Profile, nodeIPAM bool, manifestDir string) error {
+	if clusterConfig != nil {
+		if errs := validateClusterNetwork(clusterConfig); len(errs) > 0 {
+			return ValidationErrors(errs)
+		}
+	}
+
+	err := fillAgentConfig(clusterConfig, operConfig, profile, state)
 	if err != nil {
 		return err
 	}
 
-	if isOpenShift {
-		err = fillControllerConfig(clusterConfig, operConfig)
+	if nodeIPAM {
+		err = fillControllerConfig(clusterConfig, operConfig, profile)
 		if err != nil {
 			return err
 		}
 	}
 
-	// Set Antrea image.
-	if operConfig.Spec.AntreaImage == "" {
-		operConfig.Spec.AntreaImage = types.DefaultAntreaImage
-	}
+	// Set Antrea image, tagged with the Antrea version.
+	operConfig.Spec.AntreaImage = AntreaImage(&operConfig.Spec, manifestDir)
 
 	return nil
 }
 
-func (c *ConfigOc) FillConfigs(clusterConfig *configv1.Network, operConfig *operatorv1.AntreaInstall) error {
-	return fillConfig(clusterConfig, operConfig, true)
+func (c *ConfigOc) FillConfigs(clusterConfig *configv1.Network, operConfig *operatorv1.AntreaInstall, state *ClusterState) error {
+	return fillConfig(clusterConfig, operConfig, state, Profiles[ProfileOpenShift], true, c.ManifestDir)
 }
 
-func (c *ConfigK8s) FillConfigs(clusterConfig *configv1.Network, operConfig *operatorv1.AntreaInstall) error {
-	return fillConfig(clusterConfig, operConfig, false)
+// FillConfigs fills the configurations from the discovered cluster network. Unlike on OpenShift,
+// the serviceCIDR and serviceCIDRv6 set in the agent configuration are kept, since discovery may
+// be incomplete.
+func (c *ConfigK8s) FillConfigs(clusterConfig *configv1.Network, operConfig *operatorv1.AntreaInstall, state *ClusterState) error {
+	clusterConfig, err := withUserServiceCIDRs(clusterConfig, operConfig)
+	if err != nil {
+		return err
+	}
+	return fillConfig(clusterConfig, operConfig, state, c.profile(), c.NodeIPAM && clusterConfig != nil, c.ManifestDir)
 }
 
-func validateConfig(clusterConfig *configv1.Network, operConfig *operatorv1.AntreaInstall) error {
+// withUserServiceCIDRs returns a copy of clusterConfig whose first service networks are the
+// serviceCIDR and serviceCIDRv6 set in the agent configuration, if any.
+func withUserServiceCIDRs(clusterConfig *configv1.Network, operConfig *operatorv1.AntreaInstall) (*configv1.Network, error) {
+	if clusterConfig == nil {
+		return nil, nil
+	}
+	antreaAgentConfig := make(map[string]interface{})
+	if err := yaml.Unmarshal([]byte(operConfig.Spec.AntreaAgentConfig), &antreaAgentConfig); err != nil {
+		return nil, fmt.Errorf("failed to parse AntreaAgentConfig: %v", err)
+	}
+	var userCIDRs []string
+	for _, option := range []string{types.ServiceCIDROption, types.ServiceCIDRv6Option} {
+		if serviceCIDR, ok := antreaAgentConfig[option].(string); ok && serviceCIDR != "" && !inSlice(serviceCIDR, clusterConfig.Spec.ServiceNetwork) {
+			userCIDRs = append(userCIDRs, serviceCIDR)
+		}
+	}
+	if len(userCIDRs) == 0 {
+		return clusterConfig, nil
+	}
+	clusterConfig = clusterConfig.DeepCopy()
+	clusterConfig.Spec.ServiceNetwork = append(userCIDRs, clusterConfig.Spec.ServiceNetwork...)
+	return clusterConfig, nil
+}
+
+// validateConfig returns all the findings of the validation, as ValidationErrors.
+func validateConfig(clusterConfig *configv1.Network, operConfig *operatorv1.AntreaInstall, profile *Profile, state *ClusterState) error {
 	var errs []error
 
 	if operConfig.Spec.AntreaImage == "" {
 		errs = append(errs, fmt.Errorf("antreaImage option can not be empty"))
 	}
+	if err := validateAntreaVersion(&operConfig.Spec); err != nil {
+		errs = append(errs, err)
+	}
 
 	antreaAgentConfig := make(map[string]interface{})
 	err := yaml.Unmarshal([]byte(operConfig.Spec.AntreaAgentConfig), &antreaAgentConfig)
 	if err != nil {
 		errs = append(errs, fmt.Errorf("failed to parse AntreaAgentConfig: %v", err))
-		return fmt.Errorf("invalidate configuration: %v", errs)
+		return ValidationErrors(errs)
 	}
 
-	if clusterConfig == nil {
-		if _, ok := antreaAgentConfig[types.ServiceCIDROption]; !ok {
-			errs = append(errs, fmt.Errorf("serviceCIDR option can not be empty"))
+	if clusterConfig == nil || len(clusterConfig.Spec.ServiceNetwork) == 0 {
+		_, ipv4 := antreaAgentConfig[types.ServiceCIDROption]
+		_, ipv6 := antreaAgentConfig[types.ServiceCIDRv6Option]
+		if !ipv4 && !ipv6 {
+			errs = append(errs, fmt.Errorf("serviceCIDR or serviceCIDRv6 option can not be empty"))
 		}
 	} else {
-		if serviceCIDR, ok := antreaAgentConfig[types.ServiceCIDROption].(string); !ok {
-			errs = append(errs, fmt.Errorf("serviceCIDR option can not be empty"))
-		} else if found := inSlice(serviceCIDR, clusterConfig.Spec.ServiceNetwork); !found {
-			errs = append(errs, fmt.Errorf("invalid serviceCIDR option: %s, available values are: %s", serviceCIDR, clusterConfig.Spec.ServiceNetwork))
+		errs = append(errs, validateClusterNetwork(clusterConfig)...)
+		families, _ := splitNetworkFamilies(clusterConfig)
+		if err := validateServiceCIDR(antreaAgentConfig, types.ServiceCIDROption, families.serviceIPv4, clusterConfig.Spec.ServiceNetwork); err != nil {
+			errs = append(errs, err)
+		}
+		if err := validateServiceCIDR(antreaAgentConfig, types.ServiceCIDRv6Option, families.serviceIPv6, clusterConfig.Spec.ServiceNetwork); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	errs = append(errs, validateNetworkOverlaps(clusterConfig, antreaAgentConfig, state)...)
+	errs = append(errs, validateNodePortLocal(clusterConfig, antreaAgentConfig)...)
+	errs = append(errs, validateTraffic(clusterConfig, operConfig, antreaAgentConfig, profile, state)...)
+	errs = append(errs, validateMaintenanceWindows(operConfig.Spec.MaintenanceWindows)...)
+	if ttl := operConfig.Spec.ApprovalTTL; ttl != nil && ttl.Duration <= 0 {
+		errs = append(errs, fmt.Errorf("approvalTTL must be positive"))
+	}
+	if operConfig.Spec.ManifestSource != nil {
+		if err := manifestsource.Validate(operConfig.Spec.ManifestSource); err != nil {
+			errs = append(errs, err)
 		}
 	}
 	if len(errs) > 0 {
-		return fmt.Errorf("invalidate configuration: %v", errs)
+		return ValidationErrors(errs)
 	}
 	return nil
 }
 
-func (c *ConfigOc) ValidateConfig(clusterConfig *configv1.Network, operConfig *operatorv1.AntreaInstall) error {
-	return validateConfig(clusterConfig, operConfig)
+func (c *ConfigOc) ValidateConfig(clusterConfig *configv1.Network, operConfig *operatorv1.AntreaInstall, state *ClusterState) error {
+	return validateConfig(clusterConfig, operConfig, Profiles[ProfileOpenShift], state)
 }
 
-func (c *ConfigK8s) ValidateConfig(clusterConfig *configv1.Network, operConfig *operatorv1.AntreaInstall) error {
-	return validateConfig(clusterConfig, operConfig)
+func (c *ConfigK8s) ValidateConfig(clusterConfig *configv1.Network, operConfig *operatorv1.AntreaInstall, state *ClusterState) error {
+	clusterConfig, err := withUserServiceCIDRs(clusterConfig, operConfig)
+	if err != nil {
+		return err
+	}
+	return validateConfig(clusterConfig, operConfig, c.profile(), state)
 }
 
+// NeedApplyChange tells which Antrea Pods must be restarted to apply curConfig. Antrea reads its
+// configurations only at startup and has no option which is reloaded at runtime, so every option
+// is startup-only: a change of AntreaAgentConfig or AntreaCNIConfig restarts antrea-agent, a change
+// of AntreaControllerConfig restarts antrea-controller, and a change of AntreaImage rolls out both.
+// The spec fields which override options, such as Traffic and DefaultMTU, are compared through the
+// filled configurations. The configurations are compared semantically, so that a formatting-only
+// edit does not restart the Antrea Pods.
 func NeedApplyChange(preConfig, curConfig *operatorv1.AntreaInstall) (agentNeedChange, controllerNeedChange, imageChange bool) {
 	if preConfig == nil {
 		return true, true, false
 	}
 
-	if preConfig.Spec.AntreaAgentConfig != curConfig.Spec.AntreaAgentConfig {
+	if !configEqual(preConfig.Spec.AntreaAgentConfig, curConfig.Spec.AntreaAgentConfig) {
 		agentNeedChange = true
 	}
-	if preConfig.Spec.AntreaCNIConfig != curConfig.Spec.AntreaCNIConfig {
+	if !configEqual(preConfig.Spec.AntreaCNIConfig, curConfig.Spec.AntreaCNIConfig) {
 		agentNeedChange = true
 	}
-	if preConfig.Spec.AntreaControllerConfig != curConfig.Spec.AntreaControllerConfig {
+	if !configEqual(preConfig.Spec.AntreaControllerConfig, curConfig.Spec.AntreaControllerConfig) {
 		controllerNeedChange = true
 	}
 	if preConfig.Spec.AntreaImage != curConfig.Spec.AntreaImage {
@@ -219,6 +335,17 @@ func NeedApplyChange(preConfig, curConfig *operatorv1.AntreaInstall) (agentNeedC
 	return
 }
 
+// NeedManifestChange tells whether the Antrea objects must be rendered again to apply curConfig,
+// because it selects other manifests: a new manifest source or Antrea version changes the objects,
+// but not necessarily the configurations of the Pods.
+func NeedManifestChange(preConfig, curConfig *operatorv1.AntreaInstall) bool {
+	if preConfig == nil {
+		return false
+	}
+	return !reflect.DeepEqual(preConfig.Spec.ManifestSource, curConfig.Spec.ManifestSource) ||
+		preConfig.Spec.AntreaVersion != curConfig.Spec.AntreaVersion
+}
+
 func HasClusterNetworkConfigChange(preConfig, curConfig *configv1.Network) bool {
 	// TODO: We may need to save the applied cluster network config in somewhere else. Thus operator can
 	// retrieve the applied config on restart.
@@ -241,33 +368,24 @@ func HasClusterNetworkConfigChange(preConfig, curConfig *configv1.Network) bool
 	return false
 }
 
+// HasDefaultMTUChange compares the defaultMTU options of the configurations by value, so that
+// 1450, 1450.0 and "1450" are the same MTU.
 func HasDefaultMTUChange(preConfig, curConfig *operatorv1.AntreaInstall) (bool, int, error) {
-
-	curAntreaAgentConfig := make(map[string]interface{})
-	err := yaml.Unmarshal([]byte(curConfig.Spec.AntreaAgentConfig), &curAntreaAgentConfig)
+	curDefaultMTU, err := agentDefaultMTU(curConfig.Spec.AntreaAgentConfig)
 	if err != nil {
 		return false, types.DefaultMTU, err
 	}
-	curDefaultMTU, ok := curAntreaAgentConfig[types.DefaultMTUOption]
-	if !ok {
-		return false, types.DefaultMTU, fmt.Errorf("%s option can not be empty", types.DefaultMTUOption)
-	}
 
 	if preConfig == nil {
-		return true, curDefaultMTU.(int), nil
+		return true, curDefaultMTU, nil
 	}
 
-	preAntreaAgentConfig := make(map[string]interface{})
-	err = yaml.Unmarshal([]byte(preConfig.Spec.AntreaAgentConfig), &preAntreaAgentConfig)
+	preDefaultMTU, err := agentDefaultMTU(preConfig.Spec.AntreaAgentConfig)
 	if err != nil {
 		return false, types.DefaultMTU, err
 	}
-	preDefaultMTU, ok := preAntreaAgentConfig[types.DefaultMTUOption]
-	if !ok {
-		return false, types.DefaultMTU, fmt.Errorf("%s option can not be empty", types.DefaultMTUOption)
-	}
 
-	return preDefaultMTU != curDefaultMTU, curDefaultMTU.(int), nil
+	return preDefaultMTU != curDefaultMTU, curDefaultMTU, nil
 }
 
 func BuildNetworkStatus(clusterConfig *configv1.Network, defaultMTU int) *configv1.NetworkStatus {
@@ -289,6 +407,150 @@ func BuildNetworkStatus(clusterConfig *configv1.Network, defaultMTU int) *config
 	return &status
 }
 
+// BuildEffectiveConfig summarizes the filled configurations of operConfig, so that they can be
+// published in AntreaInstall.Status.
+func BuildEffectiveConfig(operConfig *operatorv1.AntreaInstall) *operatorv1.EffectiveConfig {
+	defaultMTU, _ := agentDefaultMTU(operConfig.Spec.AntreaAgentConfig)
+	effectiveConfig := &operatorv1.EffectiveConfig{
+		ConfigMapName:              types.EffectiveConfigMapName,
+		AntreaImage:                operConfig.Spec.AntreaImage,
+		AntreaAgentConfigHash:      hashConfig(operConfig.Spec.AntreaAgentConfig),
+		AntreaCNIConfigHash:        hashConfig(operConfig.Spec.AntreaCNIConfig),
+		AntreaControllerConfigHash: hashConfig(operConfig.Spec.AntreaControllerConfig),
+		DefaultMTU:                 defaultMTU,
+	}
+	// Publish the defaults which depend on the cluster state, as they are not set in the spec.
+	antreaAgentConfig := make(map[string]interface{})
+	if err := yaml.Unmarshal([]byte(operConfig.Spec.AntreaAgentConfig), &antreaAgentConfig); err == nil {
+		effectiveConfig.ServiceCIDR, _ = antreaAgentConfig[types.ServiceCIDROption].(string)
+		effectiveConfig.ServiceCIDRv6, _ = antreaAgentConfig[types.ServiceCIDRv6Option].(string)
+		effectiveConfig.TrafficEncapMode, _ = antreaAgentConfig[trafficEncapModeOption].(string)
+	}
+	var controllerConfig ctlconfig.ControllerConfig
+	if err := yaml.Unmarshal([]byte(operConfig.Spec.AntreaControllerConfig), &controllerConfig); err == nil {
+		effectiveConfig.NodeIPAM = controllerConfig.NodeIPAM.EnableNodeIPAM
+	}
+	return effectiveConfig
+}
+
+// BuildEffectiveConfigMap returns the read-only ConfigMap which holds the filled configurations of
+// operConfig, in its namespace, and the fields of its spec which select the rendered manifests and
+// the traffic mode: the Antrea version, the manifest source and the traffic options.
+func BuildEffectiveConfigMap(operConfig *operatorv1.AntreaInstall) *corev1.ConfigMap {
+	configMap := &corev1.ConfigMap{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "v1",
+			Kind:       "ConfigMap",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      types.EffectiveConfigMapName,
+			Namespace: operConfig.Namespace,
+			Labels:    map[string]string{"app": "antrea-operator"},
+		},
+		Data: map[string]string{
+			types.AntreaAgentConfigOption:      operConfig.Spec.AntreaAgentConfig,
+			types.AntreaCNIConfigOption:        operConfig.Spec.AntreaCNIConfig,
+			types.AntreaControllerConfigOption: operConfig.Spec.AntreaControllerConfig,
+			types.AntreaImageOption:            operConfig.Spec.AntreaImage,
+		},
+	}
+	if operConfig.Spec.AntreaVersion != "" {
+		configMap.Data[types.AntreaVersionOption] = operConfig.Spec.AntreaVersion
+	}
+	// The ManifestSource and TrafficSpec structs only hold strings and bools, their marshaling does
+	// not fail.
+	if operConfig.Spec.ManifestSource != nil {
+		data, _ := json.Marshal(operConfig.Spec.ManifestSource)
+		configMap.Data[types.ManifestSourceOption] = string(data)
+	}
+	if operConfig.Spec.Traffic != nil {
+		data, _ := json.Marshal(operConfig.Spec.Traffic)
+		configMap.Data[types.TrafficOption] = string(data)
+	}
+	return configMap
+}
+
+// RestoreAppliedSpec sets the fields of spec which cannot be read from the Antrea objects, the
+// Antrea version, the manifest source and the traffic options, from the effective ConfigMap
+// published with the applied configurations.
+func RestoreAppliedSpec(configMap *corev1.ConfigMap, spec *operatorv1.AntreaInstallSpec) error {
+	spec.AntreaVersion = configMap.Data[types.AntreaVersionOption]
+	if data, ok := configMap.Data[types.ManifestSourceOption]; ok {
+		spec.ManifestSource = &operatorv1.ManifestSource{}
+		if err := json.Unmarshal([]byte(data), spec.ManifestSource); err != nil {
+			return fmt.Errorf("failed to parse %s of ConfigMap %s: %v", types.ManifestSourceOption, configMap.Name, err)
+		}
+	}
+	if data, ok := configMap.Data[types.TrafficOption]; ok {
+		spec.Traffic = &operatorv1.TrafficSpec{}
+		if err := json.Unmarshal([]byte(data), spec.Traffic); err != nil {
+			return fmt.Errorf("failed to parse %s of ConfigMap %s: %v", types.TrafficOption, configMap.Name, err)
+		}
+	}
+	return nil
+}
+
+// ApprovalExpiry returns the time the approval of spec observed at approvedAt expires.
+func ApprovalExpiry(spec *operatorv1.AntreaInstallSpec, approvedAt time.Time) time.Time {
+	ttl := DefaultApprovalTTL
+	if spec.ApprovalTTL != nil {
+		ttl = spec.ApprovalTTL.Duration
+	}
+	return approvedAt.Add(ttl)
+}
+
+// FormatApprovalTime returns the value of the approved-at annotation of the approval of revision
+// observed at approvedAt: the revision and the time in RFC 3339 format, separated by "@", so that
+// the time of an approval is never used for another revision.
+func FormatApprovalTime(revision string, approvedAt time.Time) string {
+	return revision + "@" + approvedAt.UTC().Format(time.RFC3339)
+}
+
+// ParseApprovalTime returns the time of the approval of revision in the value of the approved-at
+// annotation, and false when the value is invalid or records the approval of another revision.
+func ParseApprovalTime(value, revision string) (time.Time, bool) {
+	valueRevision, approvedAt, ok := strings.Cut(value, "@")
+	if !ok || valueRevision != revision {
+		return time.Time{}, false
+	}
+	t, err := time.Parse(time.RFC3339, approvedAt)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return t, true
+}
+
+// ConfigRevision identifies the filled configurations, the image, the Antrea version and the
+// manifest digest of operConfig, for the approval of the changes.
+func ConfigRevision(operConfig *operatorv1.AntreaInstall) string {
+	hash := sha256.New()
+	for _, value := range []string{
+		operConfig.Spec.AntreaAgentConfig,
+		operConfig.Spec.AntreaCNIConfig,
+		operConfig.Spec.AntreaControllerConfig,
+		operConfig.Spec.AntreaImage,
+	} {
+		hash.Write([]byte(value))
+		hash.Write([]byte{0})
+	}
+	// The Antrea version and the manifest source are only part of the revision when they are set,
+	// so that the revisions of the latest bundled manifests are unchanged.
+	if operConfig.Spec.AntreaVersion != "" {
+		hash.Write([]byte(operConfig.Spec.AntreaVersion))
+		hash.Write([]byte{0})
+	}
+	if operConfig.Spec.ManifestSource != nil {
+		hash.Write([]byte(operConfig.Spec.ManifestSource.Digest))
+		hash.Write([]byte{0})
+	}
+	return hex.EncodeToString(hash.Sum(nil))[:revisionLength]
+}
+
+func hashConfig(config string) string {
+	hash := sha256.Sum256([]byte(config))
+	return hex.EncodeToString(hash[:])
+}
+
 func inSlice(str string, s []string) bool {
 	for _, v := range s {
 		if str == v {
@@ -320,29 +582,42 @@ func pluginCNIConfDir(conf *ocoperv1.NetworkSpec) string {
 	return network.SystemCNIConfDir
 }
 
-func generateRenderData(operatorNetwork *ocoperv1.Network, operConfig *operatorv1.AntreaInstall) *render.RenderData {
+func generateRenderData(operatorNetwork *ocoperv1.Network, operConfig *operatorv1.AntreaInstall, profile *Profile) *render.RenderData {
 	renderData := render.MakeRenderData()
 	renderData.Data[types.ReleaseVersion] = version.GetVersion()
 	renderData.Data[types.AntreaAgentConfigRenderKey] = operConfig.Spec.AntreaAgentConfig
 	renderData.Data[types.AntreaCNIConfigRenderKey] = operConfig.Spec.AntreaCNIConfig
 	renderData.Data[types.AntreaControllerConfigRenderKey] = operConfig.Spec.AntreaControllerConfig
 	renderData.Data[types.AntreaImageRenderKey] = operConfig.Spec.AntreaImage
+	chaining, primaryConfList := cniChaining(operConfig)
+	renderData.Data[types.CNIChainingRenderKey] = chaining
+	renderData.Data[types.CNIPrimaryConfListRenderKey] = primaryConfList
 	if operatorNetwork == nil {
-		renderData.Data[types.CNIConfDirRenderKey] = gocni.DefaultNetDir
-		renderData.Data[types.CNIBinDirRenderKey] = gocni.DefaultCNIDir
+		renderData.Data[types.CNIConfDirRenderKey] = profile.CNIConfDir
+		renderData.Data[types.CNIBinDirRenderKey] = profile.CNIBinDir
 	} else {
 		renderData.Data[types.CNIConfDirRenderKey] = pluginCNIConfDir(&operatorNetwork.Spec)
 		renderData.Data[types.CNIBinDirRenderKey] = network.CNIBinDir
 	}
+	// The antrea plugin is chained in the conflist of the primary CNI by cni-chaining, the
+	// conflist of install-cni must not be loaded by the container runtime.
+	renderData.Data[types.InstallCNIConfDirRenderKey] = renderData.Data[types.CNIConfDirRenderKey]
+	if chaining {
+		renderData.Data[types.InstallCNIConfDirRenderKey] = types.ChainedInstallCNIConfDir
+	}
 	return &renderData
 }
 
 func (c *ConfigK8s) GenerateRenderData(operatorNetwork *ocoperv1.Network, operConfig *operatorv1.AntreaInstall) (*render.RenderData, error) {
-	renderData := generateRenderData(operatorNetwork, operConfig)
+	renderData := generateRenderData(operatorNetwork, operConfig, c.profile())
 	return renderData, nil
 }
 
 func (c *ConfigOc) GenerateRenderData(operatorNetwork *ocoperv1.Network, operConfig *operatorv1.AntreaInstall) (*render.RenderData, error) {
-	renderData := generateRenderData(operatorNetwork, operConfig)
+	// Without a configuration, the cluster network operator enables Multus.
+	if operatorNetwork == nil {
+		operatorNetwork = &ocoperv1.Network{}
+	}
+	renderData := generateRenderData(operatorNetwork, operConfig, Profiles[ProfileOpenShift])
 	return renderData, nil
 }
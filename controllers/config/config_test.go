@@ -5,8 +5,14 @@ package config
 
 import (
 	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
+	ctlconfig "antrea.io/antrea/pkg/config/controller"
+	"antrea.io/antrea/pkg/features"
 	gocni "github.com/containerd/go-cni"
 	"github.com/ghodss/yaml"
 	. "github.com/onsi/gomega"
@@ -15,9 +21,14 @@ import (
 	"github.com/openshift/cluster-network-operator/pkg/network"
 	"github.com/openshift/cluster-network-operator/pkg/render"
 	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	uns "k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/validation/field"
 
 	operatorv1 "github.com/vmware/antrea-operator-for-kubernetes/api/v1"
+	"github.com/vmware/antrea-operator-for-kubernetes/controllers/antreaversion"
 	operatortypes "github.com/vmware/antrea-operator-for-kubernetes/controllers/types"
 	"github.com/vmware/antrea-operator-for-kubernetes/internal/version"
 )
@@ -69,15 +80,18 @@ var mockOperConfig = operatorv1.AntreaInstall{
 	},
 }
 
-var oc = &ConfigOc{}
-var k8s = &ConfigK8s{}
+// testManifestDir holds the bundled manifests, one subdirectory per Antrea version.
+const testManifestDir = "../../antrea-manifest"
+
+var oc = &ConfigOc{ManifestDir: testManifestDir}
+var k8s = &ConfigK8s{ManifestDir: testManifestDir}
 
 func TestFillDefaultsOc(t *testing.T) {
 	g := NewGomegaWithT(t)
 
 	clusterConfig := mockClusterConfig.DeepCopy()
 	operConfig := mockOperConfig.DeepCopy()
-	err := oc.FillConfigs(clusterConfig, operConfig)
+	err := oc.FillConfigs(clusterConfig, operConfig, nil)
 	g.Expect(err).ShouldNot(HaveOccurred())
 
 	antreaAgentConfig := make(map[string]interface{})
@@ -85,21 +99,21 @@ func TestFillDefaultsOc(t *testing.T) {
 	g.Expect(err).ShouldNot(HaveOccurred())
 	g.Expect(antreaAgentConfig[operatortypes.ServiceCIDROption]).Should(Equal(clusterConfig.Spec.ServiceNetwork[0]))
 	g.Expect(int(antreaAgentConfig[operatortypes.DefaultMTUOption].(float64))).Should(Equal(operatortypes.DefaultMTU))
-	g.Expect(operConfig.Spec.AntreaImage).Should(Equal(operatortypes.DefaultAntreaImage))
+	g.Expect(operConfig.Spec.AntreaImage).Should(Equal(latestImage(g)))
 }
 
 func TestFillDefaultsK8s(t *testing.T) {
 	g := NewGomegaWithT(t)
 
 	operConfig := mockOperConfig.DeepCopy()
-	err := k8s.FillConfigs(nil, operConfig)
+	err := k8s.FillConfigs(nil, operConfig, nil)
 	g.Expect(err).ShouldNot(HaveOccurred())
 
 	antreaAgentConfig := make(map[string]interface{})
 	err = yaml.Unmarshal([]byte(operConfig.Spec.AntreaAgentConfig), &antreaAgentConfig)
 	g.Expect(err).ShouldNot(HaveOccurred())
 	g.Expect(int(antreaAgentConfig[operatortypes.DefaultMTUOption].(float64))).Should(Equal(operatortypes.DefaultMTU))
-	g.Expect(operConfig.Spec.AntreaImage).Should(Equal(operatortypes.DefaultAntreaImage))
+	g.Expect(operConfig.Spec.AntreaImage).Should(Equal(latestImage(g)))
 }
 
 func TestValidateConfigOc(t *testing.T) {
@@ -107,9 +121,9 @@ func TestValidateConfigOc(t *testing.T) {
 
 	clusterConfig := mockClusterConfig.DeepCopy()
 	operConfig := mockOperConfig.DeepCopy()
-	err := oc.FillConfigs(clusterConfig, operConfig)
+	err := oc.FillConfigs(clusterConfig, operConfig, nil)
 	g.Expect(err).ShouldNot(HaveOccurred())
-	err = oc.ValidateConfig(clusterConfig, operConfig)
+	err = oc.ValidateConfig(clusterConfig, operConfig, nil)
 	g.Expect(err).ShouldNot(HaveOccurred())
 
 	// Validate service CIDR
@@ -117,7 +131,7 @@ func TestValidateConfigOc(t *testing.T) {
 	operConfig = mockOperConfig.DeepCopy()
 	clusterConfig.Spec.ServiceNetwork = []string{"10.96.0.0.0/12"}
 	operConfig.Spec.AntreaAgentConfig = "serviceCIDR: 10.96.0.0.1/12"
-	err = oc.ValidateConfig(clusterConfig, operConfig)
+	err = oc.ValidateConfig(clusterConfig, operConfig, nil)
 	g.Expect(err).Should(HaveOccurred())
 	g.Expect(err.Error()).Should(ContainSubstring("invalid serviceCIDR option"))
 	g.Expect(err.Error()).Should(ContainSubstring("available values are"))
@@ -126,7 +140,7 @@ func TestValidateConfigOc(t *testing.T) {
 	clusterConfig = mockClusterConfig.DeepCopy()
 	operConfig = mockOperConfig.DeepCopy()
 	operConfig.Spec.AntreaAgentConfig = `serviceCIDR:---`
-	err = oc.ValidateConfig(clusterConfig, operConfig)
+	err = oc.ValidateConfig(clusterConfig, operConfig, nil)
 	g.Expect(err).Should(HaveOccurred())
 	g.Expect(err.Error()).Should(ContainSubstring("failed to parse AntreaAgentConfig"))
 }
@@ -135,36 +149,51 @@ func TestValidateConfigK8s(t *testing.T) {
 	g := NewGomegaWithT(t)
 
 	operConfig := mockOperConfig.DeepCopy()
-	err := k8s.FillConfigs(nil, operConfig)
+	err := k8s.FillConfigs(nil, operConfig, nil)
 	g.Expect(err).ShouldNot(HaveOccurred())
-	err = k8s.ValidateConfig(nil, operConfig)
+	err = k8s.ValidateConfig(nil, operConfig, nil)
 	g.Expect(err).ShouldNot(HaveOccurred())
 
 	// Validate service CIDR and antrea image
 	operConfig = mockOperConfig.DeepCopy()
-	err = k8s.ValidateConfig(nil, operConfig)
+	err = k8s.ValidateConfig(nil, operConfig, nil)
 	g.Expect(err).Should(HaveOccurred())
 	g.Expect(err.Error()).Should(ContainSubstring("antreaImage option can not be empty"))
 
 	// Validate antrea-agent config
 	operConfig = mockOperConfig.DeepCopy()
 	operConfig.Spec.AntreaAgentConfig = `serviceCIDR:---`
-	err = k8s.ValidateConfig(nil, operConfig)
+	err = k8s.ValidateConfig(nil, operConfig, nil)
 	g.Expect(err).Should(HaveOccurred())
 	g.Expect(err.Error()).Should(ContainSubstring("failed to parse AntreaAgentConfig"))
 }
 
+// bundledManifestDir returns the directory of the latest bundled manifests.
+func bundledManifestDir(g *WithT) string {
+	manifestDir, err := antreaversion.ManifestDir(testManifestDir, "")
+	g.Expect(err).ShouldNot(HaveOccurred())
+	return manifestDir
+}
+
+// latestImage returns the default image, tagged with the latest bundled Antrea version.
+func latestImage(g *WithT) string {
+	versions, err := antreaversion.Bundled(testManifestDir)
+	g.Expect(err).ShouldNot(HaveOccurred())
+	g.Expect(versions).ShouldNot(BeEmpty())
+	return antreaversion.Image("", versions[len(versions)-1])
+}
+
 func TestRenderOc(t *testing.T) {
 	g := NewGomegaWithT(t)
 
 	clusterConfig := mockClusterConfig.DeepCopy()
 	operConfig := mockOperConfig.DeepCopy()
 	operatorNetwork := mockOperatorNetwork.DeepCopy()
-	err := oc.FillConfigs(clusterConfig, operConfig)
+	err := oc.FillConfigs(clusterConfig, operConfig, nil)
 	g.Expect(err).ShouldNot(HaveOccurred())
 	renderData, err := oc.GenerateRenderData(operatorNetwork, operConfig)
 	g.Expect(err).ShouldNot(HaveOccurred())
-	objs, err := render.RenderDir("../../antrea-manifest", renderData)
+	objs, err := render.RenderDir(bundledManifestDir(g), renderData)
 	g.Expect(err).ShouldNot(HaveOccurred())
 
 	for _, obj := range objs {
@@ -178,7 +207,7 @@ func TestRenderOc(t *testing.T) {
 			antreaDeployment := &appsv1.Deployment{}
 			err = runtime.DefaultUnstructuredConverter.FromUnstructured(obj.UnstructuredContent(), antreaDeployment)
 			g.Expect(err).ShouldNot(HaveOccurred())
-			g.Expect(antreaDeployment.Spec.Template.Spec.Containers[0].Image).Should(Equal(operatortypes.DefaultAntreaImage))
+			g.Expect(antreaDeployment.Spec.Template.Spec.Containers[0].Image).Should(Equal(latestImage(g)))
 			g.Expect(antreaDeployment.Annotations["release.openshift.io/version"]).Should(Equal(version.GetVersion()))
 		} else if obj.GetKind() == "DaemonSet" && obj.GetNamespace() == "kube-system" && obj.GetName() == "antrea-agent" {
 			antreaDaemonSet := &appsv1.DaemonSet{}
@@ -193,11 +222,11 @@ func TestRenderK8s(t *testing.T) {
 	g := NewGomegaWithT(t)
 
 	operConfig := mockOperConfig.DeepCopy()
-	err := k8s.FillConfigs(nil, operConfig)
+	err := k8s.FillConfigs(nil, operConfig, nil)
 	g.Expect(err).ShouldNot(HaveOccurred())
 	renderData, err := k8s.GenerateRenderData(nil, operConfig)
 	g.Expect(err).ShouldNot(HaveOccurred())
-	objs, err := render.RenderDir("../../antrea-manifest", renderData)
+	objs, err := render.RenderDir(bundledManifestDir(g), renderData)
 	g.Expect(err).ShouldNot(HaveOccurred())
 
 	for _, obj := range objs {
@@ -211,7 +240,7 @@ func TestRenderK8s(t *testing.T) {
 			antreaDeployment := &appsv1.Deployment{}
 			err = runtime.DefaultUnstructuredConverter.FromUnstructured(obj.UnstructuredContent(), antreaDeployment)
 			g.Expect(err).ShouldNot(HaveOccurred())
-			g.Expect(antreaDeployment.Spec.Template.Spec.Containers[0].Image).Should(Equal(operatortypes.DefaultAntreaImage))
+			g.Expect(antreaDeployment.Spec.Template.Spec.Containers[0].Image).Should(Equal(latestImage(g)))
 			g.Expect(antreaDeployment.Annotations["release.openshift.io/version"]).Should(Equal(version.GetVersion()))
 		} else if obj.GetKind() == "DaemonSet" && obj.GetNamespace() == "kube-system" && obj.GetName() == "antrea-agent" {
 			antreaDaemonSet := &appsv1.DaemonSet{}
@@ -279,12 +308,85 @@ func TestHasDefaultMTUChange(t *testing.T) {
 	g.Expect(defaultMTUChanged).Should(Equal(true))
 	g.Expect(curDefaultMTU).Should(Equal(testCurMtu))
 
+	// The MTUs are compared by value, whatever their YAML type.
+	for _, value := range []string{"1600.0", `"1600"`} {
+		preConfig.Spec.AntreaAgentConfig = fmt.Sprintf("%s: %s", operatortypes.DefaultMTUOption, value)
+		defaultMTUChanged, curDefaultMTU, err = HasDefaultMTUChange(preConfig, curConfig)
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(defaultMTUChanged).Should(Equal(false))
+		g.Expect(curDefaultMTU).Should(Equal(testCurMtu))
+	}
+
+	curConfig.Spec.AntreaAgentConfig = fmt.Sprintf("%s: 1450.5", operatortypes.DefaultMTUOption)
+	_, _, err = HasDefaultMTUChange(preConfig, curConfig)
+	g.Expect(err).Should(HaveOccurred())
+
 	preConfig = mockOperConfig.DeepCopy()
 	curConfig = mockOperConfig.DeepCopy()
 	_, _, err = HasDefaultMTUChange(preConfig, curConfig)
 	g.Expect(err).Should(HaveOccurred())
 }
 
+func TestFillDefaultMTU(t *testing.T) {
+	g := NewGomegaWithT(t)
+	k8s := &ConfigK8s{}
+	auto := intstr.FromString(MTUAuto)
+
+	for _, tc := range []struct {
+		name        string
+		agentConfig string
+		defaultMTU  *intstr.IntOrString
+		state       *ClusterState
+		expectedMTU int
+		// expectedFallback is true when the MTU is derived from DefaultUplinkMTU.
+		expectedFallback bool
+		expectedErr      string
+	}{
+		{name: "profile default", expectedMTU: operatortypes.DefaultMTU},
+		{name: "string agent option", agentConfig: `defaultMTU: "1400"`, expectedMTU: 1400},
+		{name: "agent option out of range", agentConfig: "defaultMTU: 65000", expectedErr: "invalid defaultMTU option"},
+		{name: "spec overrides agent option", agentConfig: "defaultMTU: 1400", defaultMTU: intstrPtr(intstr.FromInt(1300)), expectedMTU: 1300},
+		{name: "spec out of range", defaultMTU: intstrPtr(intstr.FromString("100")), expectedErr: "invalid spec.defaultMTU"},
+		{name: "auto without annotations", defaultMTU: &auto, expectedMTU: DefaultUplinkMTU - geneveOverhead, expectedFallback: true},
+		{name: "auto with empty annotations", defaultMTU: &auto, state: &ClusterState{NodeUplinkMTUs: map[string]int{}}, expectedMTU: DefaultUplinkMTU - geneveOverhead, expectedFallback: true},
+		{
+			name:        "auto uses the smallest uplink",
+			defaultMTU:  &auto,
+			state:       &ClusterState{NodeUplinkMTUs: map[string]int{"node1": 9001, "node2": 1500}},
+			expectedMTU: 1450,
+		},
+		{name: "auto with gre and ipsec", agentConfig: "tunnelType: gre\ntrafficEncryptionMode: ipsec", defaultMTU: &auto, expectedMTU: 1500 - 38 - 38, expectedFallback: true},
+		{name: "auto with wireguard", agentConfig: "trafficEncryptionMode: wireGuard", defaultMTU: &auto, expectedMTU: 1500 - 80, expectedFallback: true},
+		{name: "auto in noEncap mode", agentConfig: "trafficEncapMode: noEncap", defaultMTU: &auto, expectedMTU: 1500, expectedFallback: true},
+		{name: "auto with IPv6", agentConfig: "serviceCIDRv6: fd00::/112", defaultMTU: &auto, expectedMTU: 1500 - 50 - 20, expectedFallback: true},
+		{
+			name:        "auto below the minimum",
+			defaultMTU:  &auto,
+			state:       &ClusterState{NodeUplinkMTUs: map[string]int{"node1": 600}},
+			expectedErr: "failed to derive the MTU from the uplink MTU 600",
+		},
+	} {
+		operConfig := mockOperConfig.DeepCopy()
+		operConfig.Spec.AntreaAgentConfig = "serviceCIDR: 10.96.0.0/12\n" + tc.agentConfig
+		operConfig.Spec.DefaultMTU = tc.defaultMTU
+		g.Expect(UplinkMTUFallback(tc.defaultMTU, tc.state)).Should(Equal(tc.expectedFallback), tc.name)
+		err := k8s.FillConfigs(nil, operConfig, tc.state)
+		if tc.expectedErr != "" {
+			g.Expect(err).Should(MatchError(ContainSubstring(tc.expectedErr)), tc.name)
+			continue
+		}
+		g.Expect(err).ShouldNot(HaveOccurred(), tc.name)
+		mtu, err := agentDefaultMTU(operConfig.Spec.AntreaAgentConfig)
+		g.Expect(err).ShouldNot(HaveOccurred(), tc.name)
+		g.Expect(mtu).Should(Equal(tc.expectedMTU), tc.name)
+		g.Expect(BuildEffectiveConfig(operConfig).DefaultMTU).Should(Equal(tc.expectedMTU), tc.name)
+	}
+}
+
+func intstrPtr(value intstr.IntOrString) *intstr.IntOrString {
+	return &value
+}
+
 func TestBuildNetworkStatus(t *testing.T) {
 	g := NewGomegaWithT(t)
 
@@ -300,6 +402,74 @@ func TestBuildNetworkStatus(t *testing.T) {
 	g.Expect(updatedClusterConfig.ClusterNetworkMTU).Should(Equal(defaultMtu))
 }
 
+func TestBuildEffectiveConfig(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	operConfig := mockOperConfig.DeepCopy()
+	err := k8s.FillConfigs(nil, operConfig, nil)
+	g.Expect(err).ShouldNot(HaveOccurred())
+	effectiveConfig := BuildEffectiveConfig(operConfig)
+	g.Expect(effectiveConfig.ConfigMapName).Should(Equal(operatortypes.EffectiveConfigMapName))
+	g.Expect(effectiveConfig.AntreaImage).Should(Equal(latestImage(g)))
+	g.Expect(effectiveConfig.AntreaAgentConfigHash).Should(HaveLen(64))
+	g.Expect(effectiveConfig.AntreaCNIConfigHash).Should(HaveLen(64))
+	g.Expect(effectiveConfig.AntreaControllerConfigHash).Should(HaveLen(64))
+	// The defaults which depend on the cluster state are published.
+	g.Expect(effectiveConfig.ServiceCIDR).Should(Equal("10.96.0.0/12"))
+	g.Expect(effectiveConfig.ServiceCIDRv6).Should(BeEmpty())
+	g.Expect(effectiveConfig.TrafficEncapMode).Should(Equal(Profiles[ProfileGeneric].TrafficEncapMode))
+	g.Expect(effectiveConfig.NodeIPAM).Should(BeFalse())
+	operConfig = mockOperConfig.DeepCopy()
+	g.Expect(oc.FillConfigs(mockClusterConfig.DeepCopy(), operConfig, nil)).ShouldNot(HaveOccurred())
+	g.Expect(BuildEffectiveConfig(operConfig).NodeIPAM).Should(BeTrue())
+	operConfig = mockOperConfig.DeepCopy()
+	g.Expect(k8s.FillConfigs(nil, operConfig, nil)).ShouldNot(HaveOccurred())
+
+	// The hash only changes with the configuration it covers.
+	operConfig.Spec.AntreaAgentConfig += "defaultMTU: 1500\n"
+	updatedEffectiveConfig := BuildEffectiveConfig(operConfig)
+	g.Expect(updatedEffectiveConfig.AntreaAgentConfigHash).ShouldNot(Equal(effectiveConfig.AntreaAgentConfigHash))
+	g.Expect(updatedEffectiveConfig.AntreaCNIConfigHash).Should(Equal(effectiveConfig.AntreaCNIConfigHash))
+	g.Expect(updatedEffectiveConfig.AntreaControllerConfigHash).Should(Equal(effectiveConfig.AntreaControllerConfigHash))
+}
+
+func TestRestoreAppliedSpec(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	operConfig := mockOperConfig.DeepCopy()
+	configMap := BuildEffectiveConfigMap(operConfig)
+	g.Expect(configMap.Data).ShouldNot(HaveKey(operatortypes.ManifestSourceOption))
+	spec := &operatorv1.AntreaInstallSpec{}
+	g.Expect(RestoreAppliedSpec(configMap, spec)).Should(Succeed())
+	g.Expect(spec.ManifestSource).Should(BeNil())
+
+	// The manifest source the Antrea objects were rendered from is restored.
+	operConfig.Spec.ManifestSource = &operatorv1.ManifestSource{
+		ConfigMap: "antrea-manifests",
+		Digest:    "sha256:0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef",
+	}
+	configMap = BuildEffectiveConfigMap(operConfig)
+	g.Expect(RestoreAppliedSpec(configMap, spec)).Should(Succeed())
+	g.Expect(spec.ManifestSource).Should(Equal(operConfig.Spec.ManifestSource))
+
+	// The Antrea version and the traffic options are restored.
+	noSNAT := true
+	operConfig.Spec.AntreaVersion = "v1.14.1"
+	operConfig.Spec.Traffic = &operatorv1.TrafficSpec{EncapMode: "noEncap", NoSNAT: &noSNAT, AllowMigration: true}
+	configMap = BuildEffectiveConfigMap(operConfig)
+	spec = &operatorv1.AntreaInstallSpec{}
+	g.Expect(RestoreAppliedSpec(configMap, spec)).Should(Succeed())
+	g.Expect(spec.AntreaVersion).Should(Equal("v1.14.1"))
+	g.Expect(spec.Traffic).Should(Equal(operConfig.Spec.Traffic))
+	g.Expect(spec.ManifestSource).Should(Equal(operConfig.Spec.ManifestSource))
+
+	configMap.Data[operatortypes.ManifestSourceOption] = "{"
+	g.Expect(RestoreAppliedSpec(configMap, spec)).Should(MatchError(ContainSubstring("failed to parse manifest-source")))
+	delete(configMap.Data, operatortypes.ManifestSourceOption)
+	configMap.Data[operatortypes.TrafficOption] = "{"
+	g.Expect(RestoreAppliedSpec(configMap, spec)).Should(MatchError(ContainSubstring("failed to parse traffic")))
+}
+
 func TestGenerateRenderDataOc(t *testing.T) {
 	g := NewGomegaWithT(t)
 
@@ -340,3 +510,938 @@ func TestGenerateRenderDataK8s(t *testing.T) {
 	g.Expect(renderData.Data[operatortypes.CNIConfDirRenderKey]).Should(Equal(gocni.DefaultNetDir))
 	g.Expect(renderData.Data[operatortypes.CNIBinDirRenderKey]).Should(Equal(gocni.DefaultCNIDir))
 }
+
+func TestFillDefaultsK8sProfile(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	operConfig := mockOperConfig.DeepCopy()
+	config := &ConfigK8s{Profile: Profiles[ProfileGKE]}
+	err := config.FillConfigs(nil, operConfig, nil)
+	g.Expect(err).ShouldNot(HaveOccurred())
+
+	antreaAgentConfig := make(map[string]interface{})
+	err = yaml.Unmarshal([]byte(operConfig.Spec.AntreaAgentConfig), &antreaAgentConfig)
+	g.Expect(err).ShouldNot(HaveOccurred())
+	g.Expect(int(antreaAgentConfig[operatortypes.DefaultMTUOption].(float64))).Should(Equal(Profiles[ProfileGKE].DefaultMTU))
+	g.Expect(antreaAgentConfig[trafficEncapModeOption]).Should(Equal(TrafficEncapModeNoEncap))
+
+	renderData, err := config.GenerateRenderData(nil, operConfig)
+	g.Expect(err).ShouldNot(HaveOccurred())
+	g.Expect(renderData.Data[operatortypes.CNIConfDirRenderKey]).Should(Equal(Profiles[ProfileGKE].CNIConfDir))
+	g.Expect(renderData.Data[operatortypes.CNIBinDirRenderKey]).Should(Equal(Profiles[ProfileGKE].CNIBinDir))
+}
+
+func TestFillDefaultsK8sProfileRunning(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	fill := func(profileName string, applied *operatorv1.AntreaInstall) map[string]interface{} {
+		operConfig := mockOperConfig.DeepCopy()
+		operConfig.Spec.AntreaAgentConfig = "serviceCIDR: 10.96.0.0/12\n"
+		operConfig.Spec.Profile = profileName
+		config := &ConfigK8s{Profile: Profiles[ProfileEKS]}
+		g.Expect(config.FillConfigs(nil, operConfig, &ClusterState{AppliedConfig: applied})).Should(Succeed())
+		g.Expect(config.ValidateConfig(nil, operConfig, nil)).Should(Succeed())
+		antreaAgentConfig := make(map[string]interface{})
+		g.Expect(yaml.Unmarshal([]byte(operConfig.Spec.AntreaAgentConfig), &antreaAgentConfig)).Should(Succeed())
+		return antreaAgentConfig
+	}
+	applied := mockOperConfig.DeepCopy()
+	applied.Spec.AntreaAgentConfig = "trafficEncapMode: encap\ndefaultMTU: 1400\n"
+
+	// A fresh installation uses the defaults of the detected profile.
+	agentConfig := fill(ProfileAuto, nil)
+	g.Expect(agentConfig[trafficEncapModeOption]).Should(Equal(TrafficEncapModeNetworkPolicyOnly))
+	g.Expect(agentConfig[operatortypes.DefaultMTUOption]).Should(BeNumerically("==", 9001))
+	g.Expect(agentConfig[featureGatesOption]).Should(HaveKeyWithValue(string(features.Egress), false))
+
+	// A running installation keeps its datapath when the profile is detected.
+	agentConfig = fill(ProfileAuto, applied)
+	g.Expect(agentConfig[trafficEncapModeOption]).Should(Equal(TrafficEncapModeEncap))
+	g.Expect(agentConfig[operatortypes.DefaultMTUOption]).Should(BeNumerically("==", 1400))
+	g.Expect(agentConfig).ShouldNot(HaveKey(featureGatesOption))
+
+	// Without applied options, the generic defaults are kept.
+	applied.Spec.AntreaAgentConfig = "{}"
+	agentConfig = fill("", applied)
+	g.Expect(agentConfig[trafficEncapModeOption]).Should(Equal(TrafficEncapModeEncap))
+	g.Expect(agentConfig[operatortypes.DefaultMTUOption]).Should(BeNumerically("==", operatortypes.DefaultMTU))
+
+	// An explicit profile sets its defaults.
+	agentConfig = fill(ProfileEKS, applied)
+	g.Expect(agentConfig[trafficEncapModeOption]).Should(Equal(TrafficEncapModeNetworkPolicyOnly))
+	g.Expect(agentConfig[operatortypes.DefaultMTUOption]).Should(BeNumerically("==", 9001))
+}
+
+func TestSelectProfile(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	for _, tc := range []struct {
+		name         string
+		distribution string
+		openShift    bool
+		expected     string
+		expectErr    bool
+	}{
+		{name: ProfileAuto, distribution: "k3s", expected: ProfileK3s},
+		{name: "", distribution: "unknown", expected: ProfileGeneric},
+		{name: ProfileAuto, distribution: "openshift", openShift: true, expected: ProfileOpenShift},
+		{name: ProfileMicroK8s, distribution: "generic", expected: ProfileMicroK8s},
+		{name: ProfileK3s, openShift: true, expectErr: true},
+		{name: ProfileOpenShift, expectErr: true},
+		{name: "minikube", expectErr: true},
+	} {
+		profile, err := SelectProfile(tc.name, tc.distribution, tc.openShift)
+		if tc.expectErr {
+			g.Expect(err).Should(HaveOccurred(), fmt.Sprintf("profile %q", tc.name))
+			continue
+		}
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(profile.Name).Should(Equal(tc.expected))
+	}
+}
+
+func TestFillDefaultsK8sDiscoveredNetwork(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	clusterConfig := mockClusterConfig.DeepCopy()
+	operConfig := mockOperConfig.DeepCopy()
+	operConfig.Spec.AntreaAgentConfig = "{}"
+	config := &ConfigK8s{NodeIPAM: true}
+	err := config.FillConfigs(clusterConfig, operConfig, nil)
+	g.Expect(err).ShouldNot(HaveOccurred())
+	g.Expect(config.ValidateConfig(clusterConfig, operConfig, nil)).ShouldNot(HaveOccurred())
+
+	antreaAgentConfig := make(map[string]interface{})
+	err = yaml.Unmarshal([]byte(operConfig.Spec.AntreaAgentConfig), &antreaAgentConfig)
+	g.Expect(err).ShouldNot(HaveOccurred())
+	g.Expect(antreaAgentConfig[operatortypes.ServiceCIDROption]).Should(Equal(clusterConfig.Spec.ServiceNetwork[0]))
+
+	var controllerConfig ctlconfig.ControllerConfig
+	err = yaml.Unmarshal([]byte(operConfig.Spec.AntreaControllerConfig), &controllerConfig)
+	g.Expect(err).ShouldNot(HaveOccurred())
+	g.Expect(controllerConfig.NodeIPAM.EnableNodeIPAM).Should(BeTrue())
+	g.Expect(controllerConfig.NodeIPAM.ClusterCIDRs).Should(Equal([]string{clusterConfig.Spec.ClusterNetwork[0].CIDR}))
+	g.Expect(controllerConfig.NodeIPAM.ServiceCIDR).Should(Equal(clusterConfig.Spec.ServiceNetwork[0]))
+
+	// A serviceCIDR set by the user is kept.
+	operConfig = mockOperConfig.DeepCopy()
+	operConfig.Spec.AntreaAgentConfig = `{"serviceCIDR": "10.100.0.0/16"}`
+	err = k8s.FillConfigs(clusterConfig, operConfig, nil)
+	g.Expect(err).ShouldNot(HaveOccurred())
+	g.Expect(k8s.ValidateConfig(clusterConfig, operConfig, nil)).ShouldNot(HaveOccurred())
+	err = yaml.Unmarshal([]byte(operConfig.Spec.AntreaAgentConfig), &antreaAgentConfig)
+	g.Expect(err).ShouldNot(HaveOccurred())
+	g.Expect(antreaAgentConfig[operatortypes.ServiceCIDROption]).Should(Equal("10.100.0.0/16"))
+	g.Expect(operConfig.Spec.AntreaControllerConfig).Should(Equal(mockOperConfig.Spec.AntreaControllerConfig))
+
+	// Empty service CIDRs are not set by the user.
+	operConfig = mockOperConfig.DeepCopy()
+	operConfig.Spec.AntreaAgentConfig = `{"serviceCIDR": "", "serviceCIDRv6": ""}`
+	err = k8s.FillConfigs(clusterConfig, operConfig, nil)
+	g.Expect(err).ShouldNot(HaveOccurred())
+	g.Expect(k8s.ValidateConfig(clusterConfig, operConfig, nil)).ShouldNot(HaveOccurred())
+}
+
+func TestFillDefaultsDualStack(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	clusterConfig := mockClusterConfig.DeepCopy()
+	clusterConfig.Spec.ServiceNetwork = []string{"10.96.0.0/12", "fd00:10:96::/112"}
+	clusterConfig.Spec.ClusterNetwork = []configv1.ClusterNetworkEntry{
+		{CIDR: "192.168.0.0/16", HostPrefix: 24},
+		{CIDR: "fd00:10:244::/56", HostPrefix: 64},
+		{CIDR: "172.16.0.0/16", HostPrefix: 24},
+	}
+	operConfig := mockOperConfig.DeepCopy()
+	err := oc.FillConfigs(clusterConfig, operConfig, nil)
+	g.Expect(err).ShouldNot(HaveOccurred())
+	g.Expect(oc.ValidateConfig(clusterConfig, operConfig, nil)).ShouldNot(HaveOccurred())
+
+	antreaAgentConfig := make(map[string]interface{})
+	err = yaml.Unmarshal([]byte(operConfig.Spec.AntreaAgentConfig), &antreaAgentConfig)
+	g.Expect(err).ShouldNot(HaveOccurred())
+	g.Expect(antreaAgentConfig[operatortypes.ServiceCIDROption]).Should(Equal("10.96.0.0/12"))
+	g.Expect(antreaAgentConfig[operatortypes.ServiceCIDRv6Option]).Should(Equal("fd00:10:96::/112"))
+
+	var controllerConfig ctlconfig.ControllerConfig
+	err = yaml.Unmarshal([]byte(operConfig.Spec.AntreaControllerConfig), &controllerConfig)
+	g.Expect(err).ShouldNot(HaveOccurred())
+	g.Expect(controllerConfig.NodeIPAM.ClusterCIDRs).Should(Equal([]string{"192.168.0.0/16", "172.16.0.0/16", "fd00:10:244::/56"}))
+	g.Expect(controllerConfig.NodeIPAM.NodeCIDRMaskSizeIPv4).Should(Equal(24))
+	g.Expect(controllerConfig.NodeIPAM.NodeCIDRMaskSizeIPv6).Should(Equal(64))
+	g.Expect(controllerConfig.NodeIPAM.ServiceCIDRv6).Should(Equal("fd00:10:96::/112"))
+
+	// IPv6-only, the IPv4 service CIDR set by the user is removed.
+	clusterConfig.Spec.ServiceNetwork = []string{"fd00:10:96::/112"}
+	clusterConfig.Spec.ClusterNetwork = []configv1.ClusterNetworkEntry{{CIDR: "fd00:10:244::/56", HostPrefix: 64}}
+	operConfig = mockOperConfig.DeepCopy()
+	err = oc.FillConfigs(clusterConfig, operConfig, nil)
+	g.Expect(err).ShouldNot(HaveOccurred())
+	g.Expect(oc.ValidateConfig(clusterConfig, operConfig, nil)).ShouldNot(HaveOccurred())
+	antreaAgentConfig = make(map[string]interface{})
+	err = yaml.Unmarshal([]byte(operConfig.Spec.AntreaAgentConfig), &antreaAgentConfig)
+	g.Expect(err).ShouldNot(HaveOccurred())
+	g.Expect(antreaAgentConfig).ShouldNot(HaveKey(operatortypes.ServiceCIDROption))
+	g.Expect(antreaAgentConfig[operatortypes.ServiceCIDRv6Option]).Should(Equal("fd00:10:96::/112"))
+}
+
+func TestValidateClusterNetwork(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	for _, tc := range []struct {
+		serviceNetwork []string
+		clusterNetwork []configv1.ClusterNetworkEntry
+		expectedErr    string
+	}{
+		{
+			serviceNetwork: []string{"10.96.0.0/12", "fd00:10:96::/112"},
+			clusterNetwork: []configv1.ClusterNetworkEntry{{CIDR: "192.168.0.0/16", HostPrefix: 24}},
+			expectedErr:    "mixed IP families are not supported: the service network is dual-stack and the cluster network is IPv4",
+		},
+		{
+			serviceNetwork: []string{"10.96.0.0/12"},
+			clusterNetwork: []configv1.ClusterNetworkEntry{{CIDR: "fd00:10:244::/56", HostPrefix: 64}},
+			expectedErr:    "mixed IP families are not supported",
+		},
+		{
+			serviceNetwork: []string{"10.96.0.0/12"},
+			clusterNetwork: []configv1.ClusterNetworkEntry{{CIDR: "192.168.0.0/16", HostPrefix: 24}, {CIDR: "172.16.0.0/16", HostPrefix: 26}},
+			expectedErr:    "inconsistent IPv4 host prefixes",
+		},
+		{
+			serviceNetwork: []string{"10.96.0.0/12"},
+			clusterNetwork: []configv1.ClusterNetworkEntry{{CIDR: "192.168.0.0/16", HostPrefix: 8}},
+			expectedErr:    "host prefix 8 is too small for cluster network 192.168.0.0/16",
+		},
+	} {
+		clusterConfig := mockClusterConfig.DeepCopy()
+		clusterConfig.Spec.ServiceNetwork = tc.serviceNetwork
+		clusterConfig.Spec.ClusterNetwork = tc.clusterNetwork
+		err := oc.FillConfigs(clusterConfig, mockOperConfig.DeepCopy(), nil)
+		g.Expect(err).Should(HaveOccurred())
+		g.Expect(err.Error()).Should(ContainSubstring(tc.expectedErr))
+	}
+}
+
+func TestValidateConfigClusterState(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	clusterConfig := mockClusterConfig.DeepCopy()
+	operConfig := mockOperConfig.DeepCopy()
+	operConfig.Spec.AntreaAgentConfig = `
+serviceCIDR: 10.96.0.0/12
+transportInterfaceCIDRs:
+- 192.168.10.0/24
+nodePortLocal:
+  enable: true
+  portRange: 32000-33000
+`
+	err := oc.FillConfigs(clusterConfig, operConfig, nil)
+	g.Expect(err).ShouldNot(HaveOccurred())
+	state := &ClusterState{
+		NodeInternalIPs: map[string][]string{"node1": {"172.16.0.10"}, "node2": {"10.100.0.2"}},
+		ExternalIPPools: map[string][]string{"pool1": {"192.168.1.10-192.168.1.20"}, "pool2": {"172.20.0.0/24"}},
+	}
+	err = oc.ValidateConfig(clusterConfig, operConfig, state)
+	g.Expect(err).Should(HaveOccurred())
+	g.Expect(err).Should(BeAssignableToTypeOf(ValidationErrors{}))
+	g.Expect(err.(ValidationErrors)).Should(HaveLen(4))
+	g.Expect(err.Error()).Should(Equal(`transport interface CIDR 192.168.10.0/24 overlaps with cluster network 192.168.0.0/16
+InternalIP 10.100.0.2 of Node node2 is in service network 10.96.0.0/12
+range 192.168.1.10-192.168.1.20 of ExternalIPPool pool1 overlaps with cluster network 192.168.0.0/16
+nodePortLocal.portRange 32000-33000 collides with the service NodePort range 30000-32767`))
+
+	// Overlapping cluster and service networks.
+	clusterConfig.Spec.ClusterNetwork = []configv1.ClusterNetworkEntry{{CIDR: "10.96.0.0/16", HostPrefix: 24}}
+	operConfig = mockOperConfig.DeepCopy()
+	err = oc.FillConfigs(clusterConfig, operConfig, nil)
+	g.Expect(err).ShouldNot(HaveOccurred())
+	err = oc.ValidateConfig(clusterConfig, operConfig, &ClusterState{})
+	g.Expect(err).Should(HaveOccurred())
+	g.Expect(err.Error()).Should(Equal("cluster network 10.96.0.0/16 overlaps with service network 10.96.0.0/12"))
+}
+
+func TestValidateSchema(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	operConfig := mockOperConfig.DeepCopy()
+	errs, warnings := ValidateSchema(operConfig, testManifestDir)
+	g.Expect(errs).Should(BeEmpty())
+	g.Expect(warnings).Should(BeEmpty())
+
+	operConfig.Spec.AntreaAgentConfig = `
+serviceCIDR: 10.96.0.0/12
+defaultMTU: "1450"
+unknownOption: true
+featureGates:
+  Egress: yes-please
+nodePortLocal:
+  enable: true
+  ports: 61000-62000
+`
+	operConfig.Spec.AntreaControllerConfig = "nodeIPAM:\n  clusterCIDRs: 10.10.0.0/16\n  nodeCIDRMaskSizeIPv4: \"24\"\n"
+	operConfig.Spec.AntreaCNIConfig = `{
+		"cniVersion": "0.2.5",
+		"name": "antrea",
+		"plugins": [
+			{"type": "antrea", "ipam": {"type": "dhcp"}},
+			{"type": "portmap"},
+			{"type": "tuning"}
+		]
+	}`
+	errs, warnings = ValidateSchema(operConfig, testManifestDir)
+	var paths []string
+	for _, err := range errs {
+		paths = append(paths, err.Field)
+	}
+	g.Expect(paths).Should(Equal([]string{
+		"spec.antreaAgentConfig.defaultMTU",
+		"spec.antreaAgentConfig.featureGates.Egress",
+		"spec.antreaControllerConfig.nodeIPAM.nodeCIDRMaskSizeIPv4",
+		"spec.antreaCNIConfig.cniVersion",
+		"spec.antreaCNIConfig.plugins[0].ipam.type",
+	}))
+	g.Expect([]string(warnings)).Should(Equal([]string{
+		"spec.antreaAgentConfig.nodePortLocal.ports: unknown field",
+		"spec.antreaAgentConfig.unknownOption: unknown field",
+		`spec.antreaCNIConfig.plugins[2].type: unknown plugin type "tuning"`,
+	}))
+
+	// The options of the rendered Antrea version are described by its default configurations only,
+	// the options which are commented out in them are unknown.
+	operConfig = mockOperConfig.DeepCopy()
+	operConfig.Spec.AntreaAgentConfig = "egress:\n  maxEgressIPsPerNode: many\novsDatapathType: system\n"
+	errs, warnings = ValidateSchema(operConfig, testManifestDir)
+	g.Expect(errs).Should(HaveLen(1))
+	g.Expect(errs[0].Field).Should(Equal("spec.antreaAgentConfig.egress.maxEgressIPsPerNode"))
+	g.Expect([]string(warnings)).Should(Equal([]string{"spec.antreaAgentConfig.ovsDatapathType: unknown field"}))
+	// Without manifests, and with the manifests of spec.manifestSource, only the YAML is checked.
+	errs, warnings = ValidateSchema(operConfig, "")
+	g.Expect(errs).Should(BeEmpty())
+	g.Expect(warnings).Should(BeEmpty())
+	operConfig.Spec.ManifestSource = &operatorv1.ManifestSource{ConfigMap: "antrea-manifests"}
+	errs, _ = ValidateSchema(operConfig, testManifestDir)
+	g.Expect(errs).Should(BeEmpty())
+	operConfig.Spec.AntreaAgentConfig = "egress: ["
+	errs, _ = ValidateSchema(operConfig, "")
+	g.Expect(errs).Should(HaveLen(1))
+	g.Expect(errs[0].Field).Should(Equal("spec.antreaAgentConfig"))
+
+	// Every bundled Antrea version must provide its default configurations.
+	manifestDir := t.TempDir()
+	versionDir := filepath.Join(manifestDir, "v1.14.1")
+	g.Expect(os.MkdirAll(versionDir, 0755)).Should(Succeed())
+	g.Expect(os.WriteFile(filepath.Join(versionDir, "antrea.yml"), nil, 0644)).Should(Succeed())
+	operConfig = mockOperConfig.DeepCopy()
+	operConfig.Spec.AntreaAgentConfig = "defaultMTU: 1450\n"
+	operConfig.Spec.AntreaControllerConfig = ""
+	errs, _ = ValidateSchema(operConfig, manifestDir)
+	g.Expect(errs).Should(HaveLen(1))
+	g.Expect(errs[0].Type).Should(Equal(field.ErrorTypeInternal))
+	g.Expect(errs[0].Error()).Should(ContainSubstring("antrea-agent.conf"))
+
+	operConfig = mockOperConfig.DeepCopy()
+	operConfig.Spec.AntreaCNIConfig = `{"cniVersion": "0.3.0", "plugins": []}`
+	errs, _ = ValidateSchema(operConfig, testManifestDir)
+	g.Expect(errs).Should(HaveLen(1))
+	g.Expect(errs[0].Error()).Should(ContainSubstring("no name"))
+}
+
+func TestNeedApplyChange(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	preConfig := mockOperConfig.DeepCopy()
+	preConfig.Spec.AntreaAgentConfig = "serviceCIDR: 10.96.0.0/12\ndefaultMTU: 1450\nfeatureGates:\n  Egress: true\n"
+
+	agentNeedChange, controllerNeedChange, imageChange := NeedApplyChange(nil, preConfig)
+	g.Expect([]bool{agentNeedChange, controllerNeedChange, imageChange}).Should(Equal([]bool{true, true, false}))
+
+	// Reformatting, reordering keys, adding comments or null values is not a change.
+	curConfig := preConfig.DeepCopy()
+	curConfig.Spec.AntreaAgentConfig = `# Antrea agent configuration.
+featureGates: {Egress: true}
+transportInterfaceCIDRs:
+defaultMTU: 1450
+serviceCIDR: "10.96.0.0/12"
+`
+	curConfig.Spec.AntreaControllerConfig = "# API port.\napiPort:   10349"
+	curConfig.Spec.AntreaCNIConfig = `{"name": "antrea", "cniVersion": "0.3.0", "plugins": [{"type": "antrea", "ipam": {"type": "host-local"}}, {"type": "portmap", "capabilities": {"portMappings": true}}]}`
+	agentNeedChange, controllerNeedChange, imageChange = NeedApplyChange(preConfig, curConfig)
+	g.Expect([]bool{agentNeedChange, controllerNeedChange, imageChange}).Should(Equal([]bool{false, false, false}))
+
+	// A field change is a change.
+	curConfig.Spec.AntreaAgentConfig = "serviceCIDR: 10.96.0.0/12\ndefaultMTU: 1450\nfeatureGates:\n  Egress: false\n"
+	curConfig.Spec.AntreaControllerConfig = "apiPort: 10350\n"
+	agentNeedChange, controllerNeedChange, imageChange = NeedApplyChange(preConfig, curConfig)
+	g.Expect([]bool{agentNeedChange, controllerNeedChange, imageChange}).Should(Equal([]bool{true, true, false}))
+
+	curConfig = preConfig.DeepCopy()
+	curConfig.Spec.AntreaImage = "antrea/antrea-ubi:v1.6.0"
+	agentNeedChange, controllerNeedChange, imageChange = NeedApplyChange(preConfig, curConfig)
+	g.Expect([]bool{agentNeedChange, controllerNeedChange, imageChange}).Should(Equal([]bool{true, true, true}))
+}
+
+// TestNeedApplyChangeFields documents which changes restart the Antrea Pods: every option of
+// Antrea is read at startup only.
+func TestNeedApplyChangeFields(t *testing.T) {
+	g := NewGomegaWithT(t)
+	agentConfig := "serviceCIDR: 10.96.0.0/12\nfeatureGates:\n  Egress: true\nnodePortLocal:\n  enable: false\ntransportInterfaceCIDRs: [10.0.0.0/24, 10.0.1.0/24]\n"
+	preConfig := mockOperConfig.DeepCopy()
+	preConfig.Spec.AntreaAgentConfig = agentConfig
+	g.Expect(k8s.FillConfigs(nil, preConfig, nil)).Should(Succeed())
+
+	for _, tc := range []struct {
+		name                 string
+		modify               func(spec *operatorv1.AntreaInstallSpec)
+		expectedAgentRestart bool
+		expectedCtrlRestart  bool
+		expectedImageChange  bool
+	}{
+		{name: "no change", modify: func(spec *operatorv1.AntreaInstallSpec) {}},
+		{
+			name: "agent feature gate",
+			modify: func(spec *operatorv1.AntreaInstallSpec) {
+				spec.AntreaAgentConfig = strings.Replace(spec.AntreaAgentConfig, "Egress: true", "Egress: false", 1)
+			},
+			expectedAgentRestart: true,
+		},
+		{
+			name: "agent nested option",
+			modify: func(spec *operatorv1.AntreaInstallSpec) {
+				spec.AntreaAgentConfig = strings.Replace(spec.AntreaAgentConfig, "enable: false", "enable: true", 1)
+			},
+			expectedAgentRestart: true,
+		},
+		{
+			name: "agent option added",
+			modify: func(spec *operatorv1.AntreaInstallSpec) {
+				spec.AntreaAgentConfig += "enablePrometheusMetrics: true\n"
+			},
+			expectedAgentRestart: true,
+		},
+		{
+			name: "list items reordered",
+			modify: func(spec *operatorv1.AntreaInstallSpec) {
+				spec.AntreaAgentConfig = strings.Replace(spec.AntreaAgentConfig, "[10.0.0.0/24, 10.0.1.0/24]", "[10.0.1.0/24, 10.0.0.0/24]", 1)
+			},
+			expectedAgentRestart: true,
+		},
+		{
+			name: "CNI plugin removed",
+			modify: func(spec *operatorv1.AntreaInstallSpec) {
+				spec.AntreaCNIConfig = `{"cniVersion": "0.3.0", "name": "antrea", "plugins": [{"type": "antrea", "ipam": {"type": "host-local"}}]}`
+			},
+			expectedAgentRestart: true,
+		},
+		{
+			name: "traffic mode",
+			modify: func(spec *operatorv1.AntreaInstallSpec) {
+				spec.Traffic = &operatorv1.TrafficSpec{EncapMode: "hybrid"}
+			},
+			expectedAgentRestart: true,
+		},
+		{
+			name: "default MTU",
+			modify: func(spec *operatorv1.AntreaInstallSpec) {
+				mtu := intstr.FromInt(1400)
+				spec.DefaultMTU = &mtu
+			},
+			expectedAgentRestart: true,
+		},
+		{
+			name: "controller option",
+			modify: func(spec *operatorv1.AntreaInstallSpec) {
+				spec.AntreaControllerConfig = "apiPort: 10350\n"
+			},
+			expectedCtrlRestart: true,
+		},
+		{
+			name: "image",
+			modify: func(spec *operatorv1.AntreaInstallSpec) {
+				spec.AntreaImage = "antrea/antrea-ubi:v1.14.0"
+			},
+			expectedAgentRestart: true,
+			expectedCtrlRestart:  true,
+			expectedImageChange:  true,
+		},
+	} {
+		curConfig := mockOperConfig.DeepCopy()
+		curConfig.Spec.AntreaAgentConfig = agentConfig
+		tc.modify(&curConfig.Spec)
+		if curConfig.Spec.AntreaImage == "" {
+			curConfig.Spec.AntreaImage = preConfig.Spec.AntreaImage
+		}
+		g.Expect(k8s.FillConfigs(nil, curConfig, nil)).Should(Succeed(), tc.name)
+		agentNeedChange, controllerNeedChange, imageChange := NeedApplyChange(preConfig, curConfig)
+		g.Expect([]bool{agentNeedChange, controllerNeedChange, imageChange}).Should(
+			Equal([]bool{tc.expectedAgentRestart, tc.expectedCtrlRestart, tc.expectedImageChange}), tc.name)
+	}
+}
+
+func TestValidateTraffic(t *testing.T) {
+	g := NewGomegaWithT(t)
+	noSNAT := true
+	nodes := &ClusterState{
+		NodeInternalIPs: map[string][]string{"node1": {"192.168.0.1"}, "node2": {"192.168.0.2"}},
+		NodePodCIDRs:    map[string][]string{"node1": {"10.244.0.0/24"}},
+		NodeUplinkMTUs:  map[string]int{"node1": 1500},
+	}
+
+	for _, tc := range []struct {
+		name        string
+		config      *ConfigK8s
+		agentConfig string
+		traffic     *operatorv1.TrafficSpec
+		expectedErr string
+	}{
+		{name: "default encap mode", config: &ConfigK8s{}},
+		{name: "noSNAT in encap mode", config: &ConfigK8s{}, traffic: &operatorv1.TrafficSpec{NoSNAT: &noSNAT}, expectedErr: "noSNAT option is only supported in noEncap mode"},
+		{
+			name:        "noEncap without Pod CIDRs",
+			config:      &ConfigK8s{},
+			traffic:     &operatorv1.TrafficSpec{EncapMode: TrafficEncapModeNoEncap, NoSNAT: &noSNAT},
+			expectedErr: "noEncap mode requires routable Pod CIDRs, but the cluster network is unknown and Nodes node2 have no Pod CIDR",
+		},
+		{
+			name:        "networkPolicyOnly without chaining",
+			config:      &ConfigK8s{},
+			traffic:     &operatorv1.TrafficSpec{EncapMode: TrafficEncapModeNetworkPolicyOnly},
+			expectedErr: "networkPolicyOnly mode requires Antrea to be chained to the primary CNI of the cluster, set spec.cniChaining.enabled",
+		},
+		{name: "networkPolicyOnly on EKS", config: &ConfigK8s{Profile: Profiles[ProfileEKS]}, agentConfig: "defaultMTU: 1450"},
+		{
+			name:        "MTU too large for the tunnel",
+			config:      &ConfigK8s{},
+			agentConfig: "defaultMTU: 1480",
+			traffic:     &operatorv1.TrafficSpec{TunnelType: tunnelTypeVXLAN},
+			expectedErr: "defaultMTU 1480 plus the 50 bytes of encapsulation and encryption overhead exceeds the uplink MTU 1500 of the Nodes",
+		},
+		{name: "MTU fits the GRE tunnel", config: &ConfigK8s{}, agentConfig: "defaultMTU: 1462", traffic: &operatorv1.TrafficSpec{TunnelType: tunnelTypeGRE}},
+		{
+			name:        "invalid transport interface",
+			config:      &ConfigK8s{},
+			traffic:     &operatorv1.TrafficSpec{TransportInterface: "a-very-long-interface-name"},
+			expectedErr: "invalid transportInterface option",
+		},
+	} {
+		operConfig := mockOperConfig.DeepCopy()
+		operConfig.Spec.AntreaAgentConfig = "serviceCIDR: 10.96.0.0/12\n" + tc.agentConfig
+		operConfig.Spec.Traffic = tc.traffic
+		err := tc.config.FillConfigs(nil, operConfig, nil)
+		g.Expect(err).ShouldNot(HaveOccurred(), tc.name)
+		err = tc.config.ValidateConfig(nil, operConfig, nodes)
+		if tc.expectedErr == "" {
+			g.Expect(err).ShouldNot(HaveOccurred(), tc.name)
+		} else {
+			g.Expect(err).Should(MatchError(ContainSubstring(tc.expectedErr)), tc.name)
+		}
+	}
+}
+
+func TestValidateTrafficModeChange(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	preConfig := mockOperConfig.DeepCopy()
+	preConfig.Spec.AntreaAgentConfig = "trafficEncapMode: encap\n"
+	curConfig := preConfig.DeepCopy()
+	g.Expect(ValidateTrafficModeChange(nil, curConfig)).Should(Succeed())
+	g.Expect(ValidateTrafficModeChange(preConfig, curConfig)).Should(Succeed())
+
+	curConfig.Spec.AntreaAgentConfig = "trafficEncapMode: encap\ntunnelType: vxlan\n"
+	g.Expect(ValidateTrafficModeChange(preConfig, curConfig)).Should(MatchError("changing tunnelType from geneve to vxlan requires draining the Nodes, set spec.traffic.allowMigration to proceed"))
+
+	curConfig.Spec.AntreaAgentConfig = "trafficEncapMode: noEncap\n"
+	g.Expect(ValidateTrafficModeChange(preConfig, curConfig)).Should(MatchError(ContainSubstring("changing trafficEncapMode from encap to noEncap")))
+
+	curConfig.Spec.Traffic = &operatorv1.TrafficSpec{AllowMigration: true}
+	g.Expect(ValidateTrafficModeChange(preConfig, curConfig)).Should(Succeed())
+}
+
+func TestCNIChaining(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	cniChainingEnv := func(config Config, operConfig *operatorv1.AntreaInstall) map[string]string {
+		renderData, err := config.GenerateRenderData(nil, operConfig)
+		g.Expect(err).ShouldNot(HaveOccurred())
+		objs, err := render.RenderDir(bundledManifestDir(g), renderData)
+		g.Expect(err).ShouldNot(HaveOccurred())
+		env := map[string]string{}
+		for _, obj := range objs {
+			if obj.GetKind() != "DaemonSet" || obj.GetName() != operatortypes.AntreaAgentDaemonSetName {
+				continue
+			}
+			antreaDaemonSet := &appsv1.DaemonSet{}
+			err = runtime.DefaultUnstructuredConverter.FromUnstructured(obj.UnstructuredContent(), antreaDaemonSet)
+			g.Expect(err).ShouldNot(HaveOccurred())
+			for _, container := range antreaDaemonSet.Spec.Template.Spec.InitContainers {
+				if container.Name == "cni-chaining" {
+					for _, variable := range container.Env {
+						env[variable.Name] = variable.Value
+					}
+				}
+			}
+			// The conflist of install-cni is only written to the CNI configuration directory
+			// without chaining.
+			for _, volume := range antreaDaemonSet.Spec.Template.Spec.Volumes {
+				if volume.Name == "host-cni-conf-install" {
+					env["INSTALL_CNI_CONF_DIR"] = volume.HostPath.Path
+				}
+			}
+		}
+		return env
+	}
+
+	// The eks profile chains Antrea by default.
+	eks := &ConfigK8s{Profile: Profiles[ProfileEKS]}
+	operConfig := mockOperConfig.DeepCopy()
+	operConfig.Spec.AntreaAgentConfig = "serviceCIDR: 10.96.0.0/12\n"
+	g.Expect(eks.FillConfigs(nil, operConfig, nil)).Should(Succeed())
+	g.Expect(eks.ValidateConfig(nil, operConfig, nil)).Should(Succeed())
+	encapMode, _, err := trafficMode(operConfig.Spec.AntreaAgentConfig)
+	g.Expect(err).ShouldNot(HaveOccurred())
+	g.Expect(encapMode).Should(Equal(TrafficEncapModeNetworkPolicyOnly))
+	g.Expect(cniChainingEnv(eks, operConfig)).Should(Equal(map[string]string{
+		"CNI_CHAINING": "true", "PRIMARY_CONFLIST": "", "INSTALL_CNI_CONF_DIR": operatortypes.ChainedInstallCNIConfDir}))
+
+	// A traffic mode set by the user is kept, without chaining by default.
+	operConfig = mockOperConfig.DeepCopy()
+	operConfig.Spec.AntreaAgentConfig = "serviceCIDR: 10.96.0.0/12\ntrafficEncapMode: encap\n"
+	g.Expect(eks.FillConfigs(nil, operConfig, nil)).Should(Succeed())
+	g.Expect(eks.ValidateConfig(nil, operConfig, nil)).Should(Succeed())
+	encapMode, _, err = trafficMode(operConfig.Spec.AntreaAgentConfig)
+	g.Expect(err).ShouldNot(HaveOccurred())
+	g.Expect(encapMode).Should(Equal(TrafficEncapModeEncap))
+	g.Expect(cniChainingEnv(eks, operConfig)).Should(Equal(map[string]string{
+		"CNI_CHAINING": "false", "PRIMARY_CONFLIST": "", "INSTALL_CNI_CONF_DIR": gocni.DefaultNetDir}))
+
+	// Chaining explicitly does not overwrite it either, and is refused.
+	operConfig.Spec.CNIChaining = &operatorv1.CNIChainingSpec{Enabled: true}
+	g.Expect(eks.FillConfigs(nil, operConfig, nil)).Should(Succeed())
+	g.Expect(eks.ValidateConfig(nil, operConfig, nil)).Should(MatchError(ContainSubstring("CNI chaining requires networkPolicyOnly mode, but trafficEncapMode is encap")))
+
+	// Disabling the chaining undoes it.
+	operConfig = mockOperConfig.DeepCopy()
+	operConfig.Spec.CNIChaining = &operatorv1.CNIChainingSpec{Enabled: false}
+	operConfig.Spec.Traffic = &operatorv1.TrafficSpec{EncapMode: TrafficEncapModeEncap}
+	g.Expect(eks.FillConfigs(nil, operConfig, nil)).Should(Succeed())
+	g.Expect(eks.ValidateConfig(nil, operConfig, nil)).Should(Succeed())
+	g.Expect(cniChainingEnv(eks, operConfig)).Should(Equal(map[string]string{
+		"CNI_CHAINING": "false", "PRIMARY_CONFLIST": "", "INSTALL_CNI_CONF_DIR": gocni.DefaultNetDir}))
+
+	// The chaining can be enabled on other distributions, with a given primary conflist.
+	operConfig = mockOperConfig.DeepCopy()
+	operConfig.Spec.AntreaAgentConfig = "serviceCIDR: 10.96.0.0/12\n"
+	operConfig.Spec.CNIChaining = &operatorv1.CNIChainingSpec{Enabled: true, PrimaryConfList: "10-gke-ptp.conflist"}
+	gke := &ConfigK8s{Profile: Profiles[ProfileGKE]}
+	g.Expect(gke.FillConfigs(nil, operConfig, nil)).Should(Succeed())
+	g.Expect(gke.ValidateConfig(nil, operConfig, nil)).Should(Succeed())
+	g.Expect(cniChainingEnv(gke, operConfig)).Should(Equal(map[string]string{
+		"CNI_CHAINING": "true", "PRIMARY_CONFLIST": "10-gke-ptp.conflist", "INSTALL_CNI_CONF_DIR": operatortypes.ChainedInstallCNIConfDir}))
+
+	operConfig.Spec.CNIChaining.PrimaryConfList = "../10-gke-ptp.conf"
+	operConfig.Spec.Traffic = &operatorv1.TrafficSpec{EncapMode: TrafficEncapModeNoEncap}
+	g.Expect(gke.FillConfigs(nil, operConfig, nil)).Should(Succeed())
+	err = gke.ValidateConfig(nil, operConfig, nil)
+	g.Expect(err).Should(MatchError(ContainSubstring("CNI chaining requires networkPolicyOnly mode, but trafficEncapMode is noEncap")))
+	g.Expect(err).Should(MatchError(ContainSubstring(`invalid spec.cniChaining.primaryConfList: "../10-gke-ptp.conf" is not the file name of a conflist`)))
+
+	// Chaining is not supported on OpenShift.
+	operConfig = mockOperConfig.DeepCopy()
+	operConfig.Spec.CNIChaining = &operatorv1.CNIChainingSpec{Enabled: true}
+	g.Expect(oc.FillConfigs(mockClusterConfig.DeepCopy(), operConfig, nil)).Should(Succeed())
+	g.Expect(oc.ValidateConfig(mockClusterConfig.DeepCopy(), operConfig, nil)).Should(MatchError(ContainSubstring("CNI chaining is not supported on OpenShift")))
+}
+
+func TestConfigRevision(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	preConfig := mockOperConfig.DeepCopy()
+	revision := ConfigRevision(preConfig)
+	g.Expect(revision).Should(HaveLen(revisionLength))
+	g.Expect(ConfigRevision(preConfig.DeepCopy())).Should(Equal(revision))
+
+	// Each configuration and the image are part of the revision.
+	curConfig := preConfig.DeepCopy()
+	curConfig.Spec.AntreaImage = "antrea/antrea-ubi:latest"
+	g.Expect(ConfigRevision(curConfig)).ShouldNot(Equal(revision))
+	curConfig = preConfig.DeepCopy()
+	curConfig.Spec.AntreaCNIConfig += " "
+	g.Expect(ConfigRevision(curConfig)).ShouldNot(Equal(revision))
+
+	// The configurations are delimited, so moving text between them changes the revision.
+	preConfig.Spec.AntreaAgentConfig, preConfig.Spec.AntreaCNIConfig = "ab", "c"
+	curConfig = preConfig.DeepCopy()
+	curConfig.Spec.AntreaAgentConfig, curConfig.Spec.AntreaCNIConfig = "a", "bc"
+	g.Expect(ConfigRevision(curConfig)).ShouldNot(Equal(ConfigRevision(preConfig)))
+}
+
+func TestApprovalTime(t *testing.T) {
+	g := NewGomegaWithT(t)
+	approvedAt := time.Date(2026, time.October, 17, 1, 30, 0, 0, time.FixedZone("CEST", 2*3600))
+
+	value := FormatApprovalTime("0123456789ab", approvedAt)
+	g.Expect(value).Should(Equal("0123456789ab@2026-10-16T23:30:00Z"))
+	parsed, ok := ParseApprovalTime(value, "0123456789ab")
+	g.Expect(ok).Should(BeTrue())
+	g.Expect(parsed).Should(BeTemporally("==", approvedAt))
+	// The approval time of another revision, or an invalid one, is not used.
+	for _, value := range []string{"", "2026-10-16T23:30:00Z", "ba9876543210@2026-10-16T23:30:00Z", "0123456789ab@yesterday"} {
+		_, ok := ParseApprovalTime(value, "0123456789ab")
+		g.Expect(ok).Should(BeFalse(), value)
+	}
+
+	spec := &operatorv1.AntreaInstallSpec{}
+	g.Expect(ApprovalExpiry(spec, approvedAt)).Should(BeTemporally("==", approvedAt.Add(DefaultApprovalTTL)))
+	spec.ApprovalTTL = &metav1.Duration{Duration: time.Hour}
+	g.Expect(ApprovalExpiry(spec, approvedAt)).Should(BeTemporally("==", approvedAt.Add(time.Hour)))
+
+	operConfig := mockOperConfig.DeepCopy()
+	operConfig.Spec.ApprovalTTL = &metav1.Duration{}
+	g.Expect(k8s.FillConfigs(nil, operConfig, nil)).ShouldNot(HaveOccurred())
+	g.Expect(k8s.ValidateConfig(nil, operConfig, nil)).Should(MatchError(ContainSubstring("approvalTTL must be positive")))
+}
+
+func TestMaintenanceWindows(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	// Saturday 2026-10-17, 01:30 UTC.
+	now := time.Date(2026, time.October, 17, 1, 30, 0, 0, time.UTC)
+	schedule, err := ParseSchedule("0 2 * * SAT", "")
+	g.Expect(err).ShouldNot(HaveOccurred())
+	g.Expect(schedule.Next(now)).Should(BeTemporally("==", time.Date(2026, time.October, 17, 2, 0, 0, 0, time.UTC)))
+	schedule, err = ParseSchedule("*/15 22-23 1,15 JAN-MAR 7", "")
+	g.Expect(err).ShouldNot(HaveOccurred())
+	// Either the day of month or the day of week matches when both are restricted.
+	g.Expect(schedule.Next(now)).Should(BeTemporally("==", time.Date(2027, time.January, 1, 22, 0, 0, 0, time.UTC)))
+	g.Expect(schedule.Next(time.Date(2027, time.January, 1, 23, 50, 0, 0, time.UTC))).Should(BeTemporally("==", time.Date(2027, time.January, 3, 22, 0, 0, 0, time.UTC)))
+	schedule, err = ParseSchedule("0 3 * * *", "Asia/Tokyo")
+	g.Expect(err).ShouldNot(HaveOccurred())
+	g.Expect(schedule.Next(now)).Should(BeTemporally("==", time.Date(2026, time.October, 17, 18, 0, 0, 0, time.UTC)))
+
+	for _, expr := range []string{"0 2 * *", "60 2 * * *", "0 2 * * FUN", "0 5-2 * * *", "*/0 2 * * *"} {
+		_, err := ParseSchedule(expr, "")
+		g.Expect(err).Should(HaveOccurred(), expr)
+	}
+	_, err = ParseSchedule("0 2 * * *", "Mars/Olympus")
+	g.Expect(err).Should(MatchError(ContainSubstring("invalid time zone")))
+
+	windows := []operatorv1.MaintenanceWindow{
+		{Schedule: "0 2 * * SAT", Duration: metav1.Duration{Duration: 2 * time.Hour}},
+		{Schedule: "0 1 * * *", Duration: metav1.Duration{Duration: 15 * time.Minute}, TimeZone: "UTC"},
+	}
+	open, next := MaintenanceWindowOpen(windows, now)
+	g.Expect(open).Should(BeFalse())
+	g.Expect(next).Should(BeTemporally("==", time.Date(2026, time.October, 17, 2, 0, 0, 0, time.UTC)))
+	open, _ = MaintenanceWindowOpen(windows, now.Add(time.Hour))
+	g.Expect(open).Should(BeTrue())
+	open, _ = MaintenanceWindowOpen(windows, now.Add(150*time.Minute))
+	g.Expect(open).Should(BeFalse())
+	open, _ = MaintenanceWindowOpen(windows, now.Add(-20*time.Minute))
+	g.Expect(open).Should(BeTrue())
+
+	operConfig := mockOperConfig.DeepCopy()
+	operConfig.Spec.MaintenanceWindows = []operatorv1.MaintenanceWindow{
+		{Schedule: "0 0 30 2 *", Duration: metav1.Duration{Duration: time.Hour}},
+		{Schedule: "0 2 * * SAT"},
+	}
+	g.Expect(k8s.FillConfigs(nil, operConfig, nil)).ShouldNot(HaveOccurred())
+	err = k8s.ValidateConfig(nil, operConfig, nil)
+	g.Expect(err).Should(MatchError(ContainSubstring("maintenanceWindows[0]: schedule \"0 0 30 2 *\" never matches")))
+	g.Expect(err).Should(MatchError(ContainSubstring("maintenanceWindows[1].duration must be positive")))
+}
+
+func TestAntreaVersion(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	for _, version := range []string{"1.14.1", "v1.14", "v1.014.1", "v1.14.1-rc.1", "latest"} {
+		_, err := antreaversion.Parse(version)
+		g.Expect(err).Should(HaveOccurred(), version)
+	}
+
+	// The manifests are bundled in one directory per version, the latest one being the default.
+	versions, err := antreaversion.Bundled(testManifestDir)
+	g.Expect(err).ShouldNot(HaveOccurred())
+	g.Expect(versions).ShouldNot(BeEmpty())
+	latest := versions[len(versions)-1].String()
+	g.Expect(antreaversion.ManifestDir(testManifestDir, latest)).Should(Equal("../../antrea-manifest/" + latest))
+	_, err = antreaversion.ManifestDir(testManifestDir, "v0.1.0")
+	g.Expect(err).Should(MatchError(ContainSubstring("version v0.1.0 of Antrea is not bundled")))
+
+	// The version replaces the tag of the image, unless it is pinned by digest.
+	operConfig := mockOperConfig.DeepCopy()
+	operConfig.Spec.AntreaVersion = "v1.14.1"
+	g.Expect(k8s.FillConfigs(nil, operConfig, nil)).Should(Succeed())
+	g.Expect(operConfig.Spec.AntreaImage).Should(Equal("antrea/antrea-ubi:v1.14.1"))
+	g.Expect(k8s.ValidateConfig(nil, operConfig, nil)).Should(Succeed())
+	operConfig.Spec.AntreaImage = "registry.example.com:5000/antrea/antrea-ubi:v1.13.0"
+	g.Expect(AntreaImage(&operConfig.Spec, testManifestDir)).Should(Equal("registry.example.com:5000/antrea/antrea-ubi:v1.14.1"))
+	operConfig.Spec.AntreaImage = "registry.example.com:5000/antrea/antrea-ubi"
+	g.Expect(AntreaImage(&operConfig.Spec, testManifestDir)).Should(Equal("registry.example.com:5000/antrea/antrea-ubi:v1.14.1"))
+	operConfig.Spec.AntreaImage = "antrea/antrea-ubi@sha256:" + fmt.Sprintf("%064x", 1)
+	g.Expect(AntreaImage(&operConfig.Spec, testManifestDir)).Should(Equal(operConfig.Spec.AntreaImage))
+
+	// The upgrade paths are checked against the applied version, or the tag of the applied image.
+	preConfig := mockOperConfig.DeepCopy()
+	preConfig.Spec.AntreaImage = "antrea/antrea-ubi:v1.13.2"
+	curConfig := mockOperConfig.DeepCopy()
+	g.Expect(ValidateVersionChange(nil, curConfig)).Should(Succeed())
+	g.Expect(ValidateVersionChange(preConfig, curConfig)).Should(Succeed())
+	for _, version := range []string{"v1.13.2", "v1.13.0", "v1.13.5", "v1.14.1"} {
+		curConfig.Spec.AntreaVersion = version
+		g.Expect(ValidateVersionChange(preConfig, curConfig)).Should(Succeed(), version)
+	}
+	curConfig.Spec.AntreaVersion = "v1.15.0"
+	g.Expect(ValidateVersionChange(preConfig, curConfig)).Should(MatchError(ContainSubstring("skips minor versions, upgrade to v1.14 first")))
+	curConfig.Spec.AntreaVersion = "v2.0.0"
+	g.Expect(ValidateVersionChange(preConfig, curConfig)).Should(MatchError(ContainSubstring("the major version cannot be changed")))
+	curConfig.Spec.AntreaVersion = "v1.12.3"
+	g.Expect(ValidateVersionChange(preConfig, curConfig)).Should(MatchError(ContainSubstring("set spec.allowDowngrade to proceed")))
+	curConfig.Spec.AllowDowngrade = true
+	g.Expect(ValidateVersionChange(preConfig, curConfig)).Should(Succeed())
+	curConfig.Spec.AntreaVersion = "v1.11.0"
+	g.Expect(ValidateVersionChange(preConfig, curConfig)).Should(MatchError(ContainSubstring("only the previous minor version can be rolled back to")))
+	preConfig.Spec.AntreaVersion = "v1.11.1"
+	g.Expect(ValidateVersionChange(preConfig, curConfig)).Should(Succeed())
+	preConfig = mockOperConfig.DeepCopy()
+	g.Expect(ValidateVersionChange(preConfig, curConfig)).Should(Succeed())
+
+	// Without a version, the default image is tagged with the latest bundled version, which the
+	// upgrade paths are checked against.
+	operConfig = mockOperConfig.DeepCopy()
+	g.Expect(AntreaImage(&operConfig.Spec, testManifestDir)).Should(Equal("antrea/antrea-ubi:" + latest))
+	g.Expect(AntreaImage(&operConfig.Spec, "")).Should(Equal(operatortypes.DefaultAntreaImage))
+	operConfig.Spec.AntreaImage = "antrea/antrea-ubi:v1.11.0"
+	g.Expect(AntreaImage(&operConfig.Spec, testManifestDir)).Should(Equal(operConfig.Spec.AntreaImage))
+	curConfig = mockOperConfig.DeepCopy()
+	g.Expect(k8s.FillConfigs(nil, curConfig, nil)).Should(Succeed())
+	preConfig.Spec.AntreaVersion = ""
+	preConfig.Spec.AntreaImage = "antrea/antrea-ubi:" + latest
+	g.Expect(ValidateVersionChange(preConfig, curConfig)).Should(Succeed())
+	preConfig.Spec.AntreaImage = "antrea/antrea-ubi:v0.1.0"
+	g.Expect(ValidateVersionChange(preConfig, curConfig)).Should(MatchError(ContainSubstring("the major version cannot be changed")))
+}
+
+// TestRenderBundledVersions renders the manifests of each bundled Antrea version, with the image
+// of the version.
+func TestRenderBundledVersions(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	versions, err := antreaversion.Bundled(testManifestDir)
+	g.Expect(err).ShouldNot(HaveOccurred())
+	g.Expect(versions).ShouldNot(BeEmpty())
+	for _, version := range versions {
+		operConfig := mockOperConfig.DeepCopy()
+		operConfig.Spec.AntreaVersion = version.String()
+		errs, _ := ValidateSchema(operConfig, testManifestDir)
+		g.Expect(errs).Should(BeEmpty(), version.String())
+		g.Expect(k8s.FillConfigs(nil, operConfig, nil)).Should(Succeed(), version.String())
+		g.Expect(k8s.ValidateConfig(nil, operConfig, nil)).Should(Succeed(), version.String())
+		renderData, err := k8s.GenerateRenderData(nil, operConfig)
+		g.Expect(err).ShouldNot(HaveOccurred(), version.String())
+		manifestDir, err := antreaversion.ManifestDir(testManifestDir, version.String())
+		g.Expect(err).ShouldNot(HaveOccurred(), version.String())
+		objs, err := render.RenderDir(manifestDir, renderData)
+		g.Expect(err).ShouldNot(HaveOccurred(), version.String())
+
+		var images []string
+		for _, obj := range objs {
+			if obj.GetKind() != "Deployment" && obj.GetKind() != "DaemonSet" {
+				continue
+			}
+			containers, _, err := uns.NestedSlice(obj.Object, "spec", "template", "spec", "containers")
+			g.Expect(err).ShouldNot(HaveOccurred(), version.String())
+			for _, container := range containers {
+				images = append(images, container.(map[string]interface{})["image"].(string))
+			}
+		}
+		g.Expect(images).ShouldNot(BeEmpty(), version.String())
+		g.Expect(images).Should(HaveEach("antrea/antrea-ubi:"+version.String()), version.String())
+	}
+}
+
+// TestRenderVersions renders the manifests of two Antrea versions of a manifest directory, and
+// checks the upgrade and the rollback between them. The older version holds a copy of the bundled
+// templates.
+func TestRenderVersions(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	manifestDir := t.TempDir()
+	bundled, err := antreaversion.ManifestDir(testManifestDir, "")
+	g.Expect(err).ShouldNot(HaveOccurred())
+	entries, err := os.ReadDir(bundled)
+	g.Expect(err).ShouldNot(HaveOccurred())
+	versions := []string{"v1.13.4", filepath.Base(bundled)}
+	for _, version := range versions {
+		g.Expect(os.MkdirAll(filepath.Join(manifestDir, version), 0755)).Should(Succeed())
+		for _, entry := range entries {
+			data, err := os.ReadFile(filepath.Join(bundled, entry.Name()))
+			g.Expect(err).ShouldNot(HaveOccurred())
+			g.Expect(os.WriteFile(filepath.Join(manifestDir, version, entry.Name()), data, 0644)).Should(Succeed())
+		}
+	}
+
+	configs := map[string]*operatorv1.AntreaInstall{}
+	for _, version := range versions {
+		operConfig := mockOperConfig.DeepCopy()
+		operConfig.Spec.AntreaVersion = version
+		operConfig.Spec.AntreaImage = AntreaImage(&operConfig.Spec, manifestDir)
+		errs, _ := ValidateSchema(operConfig, manifestDir)
+		g.Expect(errs).Should(BeEmpty(), version)
+		g.Expect(k8s.FillConfigs(nil, operConfig, nil)).Should(Succeed(), version)
+		g.Expect(k8s.ValidateConfig(nil, operConfig, nil)).Should(Succeed(), version)
+		renderData, err := k8s.GenerateRenderData(nil, operConfig)
+		g.Expect(err).ShouldNot(HaveOccurred(), version)
+		versionDir, err := antreaversion.ManifestDir(manifestDir, version)
+		g.Expect(err).ShouldNot(HaveOccurred(), version)
+		g.Expect(versionDir).Should(Equal(filepath.Join(manifestDir, version)))
+		objs, err := render.RenderDir(versionDir, renderData)
+		g.Expect(err).ShouldNot(HaveOccurred(), version)
+		g.Expect(objs).ShouldNot(BeEmpty(), version)
+		for _, obj := range objs {
+			if obj.GetKind() != "DaemonSet" {
+				continue
+			}
+			containers, _, err := uns.NestedSlice(obj.Object, "spec", "template", "spec", "containers")
+			g.Expect(err).ShouldNot(HaveOccurred(), version)
+			g.Expect(containers[0].(map[string]interface{})["image"]).Should(Equal("antrea/antrea-ubi:"+version), version)
+		}
+		configs[version] = operConfig
+	}
+	// Without spec.antreaVersion, the latest version of the directory is selected.
+	latestDir, err := antreaversion.ManifestDir(manifestDir, "")
+	g.Expect(err).ShouldNot(HaveOccurred())
+	g.Expect(latestDir).Should(Equal(filepath.Join(manifestDir, versions[1])))
+
+	// The upgrade renders the manifests of the new version and rolls out the new image.
+	from, to := configs[versions[0]], configs[versions[1]]
+	g.Expect(ValidateVersionChange(from, to)).Should(Succeed())
+	g.Expect(NeedManifestChange(from, to)).Should(BeTrue())
+	_, _, imageChange := NeedApplyChange(from, to)
+	g.Expect(imageChange).Should(BeTrue())
+
+	// The rollback to the previous minor version requires allowDowngrade.
+	g.Expect(ValidateVersionChange(to, from)).Should(MatchError(ContainSubstring("allowDowngrade")))
+	from = from.DeepCopy()
+	from.Spec.AllowDowngrade = true
+	g.Expect(ValidateVersionChange(to, from)).Should(Succeed())
+}
+
+func TestNeedManifestChange(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	operConfig := mockOperConfig.DeepCopy()
+	g.Expect(NeedManifestChange(nil, operConfig)).Should(BeFalse())
+	g.Expect(NeedManifestChange(operConfig.DeepCopy(), operConfig)).Should(BeFalse())
+	curConfig := operConfig.DeepCopy()
+	curConfig.Spec.AntreaVersion = "v1.14.1"
+	g.Expect(NeedManifestChange(operConfig, curConfig)).Should(BeTrue())
+	curConfig = operConfig.DeepCopy()
+	curConfig.Spec.ManifestSource = &operatorv1.ManifestSource{ConfigMap: "antrea-manifests"}
+	g.Expect(NeedManifestChange(operConfig, curConfig)).Should(BeTrue())
+
+	// The applied configurations restored after a restart of the operator select the same manifests.
+	curConfig.Spec.AntreaVersion = "v1.14.1"
+	appliedConfig := &operatorv1.AntreaInstall{}
+	g.Expect(RestoreAppliedSpec(BuildEffectiveConfigMap(curConfig), &appliedConfig.Spec)).Should(Succeed())
+	g.Expect(NeedManifestChange(appliedConfig, curConfig)).Should(BeFalse())
+}
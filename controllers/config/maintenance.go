@@ -0,0 +1,204 @@
+/* Copyright © 2026 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package config
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	operatorv1 "github.com/vmware/antrea-operator-for-kubernetes/api/v1"
+)
+
+// maxScheduleSearch bounds the search of the next start of a schedule, so that a schedule which
+// never matches, such as "0 0 30 2 *", is detected.
+const maxScheduleSearch = 5 * 366 * 24 * time.Hour
+
+var (
+	monthNames = map[string]int{
+		"JAN": 1, "FEB": 2, "MAR": 3, "APR": 4, "MAY": 5, "JUN": 6,
+		"JUL": 7, "AUG": 8, "SEP": 9, "OCT": 10, "NOV": 11, "DEC": 12,
+	}
+	dayNames = map[string]int{
+		"SUN": 0, "MON": 1, "TUE": 2, "WED": 3, "THU": 4, "FRI": 5, "SAT": 6,
+	}
+)
+
+// cronField is the set of the values matched by a field of a schedule.
+type cronField struct {
+	values map[int]bool
+	// any tells that the field is "*", which matters for the days of month and of week.
+	any bool
+}
+
+func (f *cronField) match(value int) bool {
+	return f.values[value]
+}
+
+// Schedule is a parsed cron expression: minute, hour, day of month, month and day of week.
+type Schedule struct {
+	minute, hour, dayOfMonth, month, dayOfWeek *cronField
+	location                                   *time.Location
+}
+
+// ParseSchedule parses a cron expression of five fields in the time zone, UTC when it is empty.
+// The fields accept "*", values, ranges, lists and steps, and the months and days of week
+// accept their three-letter names. As in cron, a day matches when both the day of month and the
+// day of week match, or either of them when both are restricted.
+func ParseSchedule(expr, timeZone string) (*Schedule, error) {
+	location, err := time.LoadLocation(timeZone)
+	if err != nil {
+		return nil, fmt.Errorf("invalid time zone %q: %v", timeZone, err)
+	}
+	fields := strings.Fields(expr)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("invalid schedule %q: expected 5 fields, got %d", expr, len(fields))
+	}
+	s := &Schedule{location: location}
+	for i, spec := range []struct {
+		field    **cronField
+		name     string
+		min, max int
+		names    map[string]int
+	}{
+		{&s.minute, "minute", 0, 59, nil},
+		{&s.hour, "hour", 0, 23, nil},
+		{&s.dayOfMonth, "day of month", 1, 31, nil},
+		{&s.month, "month", 1, 12, monthNames},
+		{&s.dayOfWeek, "day of week", 0, 7, dayNames},
+	} {
+		field, err := parseCronField(fields[i], spec.min, spec.max, spec.names)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s in schedule %q: %v", spec.name, expr, err)
+		}
+		*spec.field = field
+	}
+	// 7 is Sunday too.
+	if s.dayOfWeek.values[7] {
+		s.dayOfWeek.values[0] = true
+	}
+	return s, nil
+}
+
+func parseCronField(field string, min, max int, names map[string]int) (*cronField, error) {
+	f := &cronField{values: map[int]bool{}, any: field == "*"}
+	for _, item := range strings.Split(field, ",") {
+		rangeExpr, step := item, 1
+		if i := strings.Index(item, "/"); i >= 0 {
+			var err error
+			rangeExpr = item[:i]
+			if step, err = strconv.Atoi(item[i+1:]); err != nil || step <= 0 {
+				return nil, fmt.Errorf("invalid step %q", item[i+1:])
+			}
+		}
+		low, high := min, max
+		if rangeExpr != "*" {
+			bounds := strings.SplitN(rangeExpr, "-", 2)
+			var err error
+			if low, err = parseCronValue(bounds[0], min, max, names); err != nil {
+				return nil, err
+			}
+			high = low
+			if len(bounds) == 2 {
+				if high, err = parseCronValue(bounds[1], min, max, names); err != nil {
+					return nil, err
+				}
+			} else if step > 1 {
+				// "5/15" is "5-max/15".
+				high = max
+			}
+			if low > high {
+				return nil, fmt.Errorf("invalid range %q", rangeExpr)
+			}
+		}
+		for value := low; value <= high; value += step {
+			f.values[value] = true
+		}
+	}
+	return f, nil
+}
+
+func parseCronValue(value string, min, max int, names map[string]int) (int, error) {
+	if n, ok := names[strings.ToUpper(value)]; ok {
+		return n, nil
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid value %q", value)
+	}
+	if n < min || n > max {
+		return 0, fmt.Errorf("value %d out of range [%d, %d]", n, min, max)
+	}
+	return n, nil
+}
+
+func (s *Schedule) matchDay(t time.Time) bool {
+	dayOfMonth, dayOfWeek := s.dayOfMonth.match(t.Day()), s.dayOfWeek.match(int(t.Weekday()))
+	if s.dayOfMonth.any || s.dayOfWeek.any {
+		return dayOfMonth && dayOfWeek
+	}
+	return dayOfMonth || dayOfWeek
+}
+
+// Next returns the first start of the schedule after the given time, or the zero time when there
+// is none in the next five years.
+func (s *Schedule) Next(after time.Time) time.Time {
+	t := after.In(s.location).Truncate(time.Minute).Add(time.Minute)
+	limit := t.Add(maxScheduleSearch)
+	for t.Before(limit) {
+		switch {
+		case !s.month.match(int(t.Month())):
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, s.location)
+		case !s.matchDay(t):
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, s.location)
+		case !s.hour.match(t.Hour()):
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, s.location)
+		case !s.minute.match(t.Minute()):
+			t = t.Truncate(time.Minute).Add(time.Minute)
+		default:
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+// MaintenanceWindowOpen tells whether one of the maintenance windows is open at now, and
+// otherwise returns the earliest next start of a window. The windows must be valid.
+func MaintenanceWindowOpen(windows []operatorv1.MaintenanceWindow, now time.Time) (bool, time.Time) {
+	var next time.Time
+	for _, window := range windows {
+		schedule, err := ParseSchedule(window.Schedule, window.TimeZone)
+		if err != nil {
+			continue
+		}
+		// The window is open when it started less than its duration ago.
+		if start := schedule.Next(now.Add(-window.Duration.Duration)); !start.IsZero() && !start.After(now) &&
+			now.Before(start.Add(window.Duration.Duration)) {
+			return true, time.Time{}
+		}
+		if start := schedule.Next(now); !start.IsZero() && (next.IsZero() || start.Before(next)) {
+			next = start
+		}
+	}
+	return false, next
+}
+
+func validateMaintenanceWindows(windows []operatorv1.MaintenanceWindow) []error {
+	var errs []error
+	for i, window := range windows {
+		if window.Duration.Duration <= 0 {
+			errs = append(errs, fmt.Errorf("maintenanceWindows[%d].duration must be positive", i))
+		}
+		schedule, err := ParseSchedule(window.Schedule, window.TimeZone)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("maintenanceWindows[%d]: %v", i, err))
+			continue
+		}
+		if schedule.Next(time.Now()).IsZero() {
+			errs = append(errs, fmt.Errorf("maintenanceWindows[%d]: schedule %q never matches", i, window.Schedule))
+		}
+	}
+	return errs
+}
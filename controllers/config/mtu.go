@@ -0,0 +1,210 @@
+/* Copyright © 2026 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package config
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	"github.com/vmware/antrea-operator-for-kubernetes/controllers/types"
+)
+
+const (
+	// MTUAuto derives the MTU from the uplink MTU of the Nodes.
+	MTUAuto = "auto"
+
+	// MinMTU and MaxMTU bound the MTU of the Pod network. An MTU of 0 is also accepted in
+	// AntreaAgentConfig, it lets antrea-agent compute the MTU of each Node.
+	MinMTU = 576
+	MaxMTU = 9216
+
+	// DefaultUplinkMTU is the uplink MTU assumed when no Node is annotated with its uplink MTU.
+	DefaultUplinkMTU = 1500
+
+	tunnelTypeOption            = "tunnelType"
+	trafficEncryptionModeOption = "trafficEncryptionMode"
+	enableIPSecTunnelOption     = "enableIPSecTunnel"
+
+	trafficEncapModeHybrid = "hybrid"
+
+	tunnelTypeGeneve = "geneve"
+	tunnelTypeVXLAN  = "vxlan"
+	tunnelTypeGRE    = "gre"
+	tunnelTypeSTT    = "stt"
+
+	trafficEncryptionModeIPSec     = "ipsec"
+	trafficEncryptionModeWireGuard = "wireguard"
+
+	// The overheads antrea-agent subtracts from the transport interface MTU.
+	geneveOverhead    = 50
+	vxlanOverhead     = 50
+	greOverhead       = 38
+	sttOverhead       = 50
+	ipv6ExtraOverhead = 20
+	ipsecESPOverhead  = 38
+	wireGuardOverhead = 80
+)
+
+// ParseMTU parses an MTU decoded from YAML, which may be an integer, an integral float or a
+// numeric string, and checks that it is 0 or in the [MinMTU, MaxMTU] range.
+func ParseMTU(value interface{}) (int, error) {
+	var mtu int
+	switch v := value.(type) {
+	case int:
+		mtu = v
+	case int64:
+		mtu = int(v)
+	case float64:
+		if v != math.Trunc(v) {
+			return 0, fmt.Errorf("invalid MTU %v: it must be an integer", v)
+		}
+		mtu = int(v)
+	case string:
+		parsed, err := strconv.Atoi(strings.TrimSpace(v))
+		if err != nil {
+			return 0, fmt.Errorf("invalid MTU %q: it must be an integer", v)
+		}
+		mtu = parsed
+	default:
+		return 0, fmt.Errorf("invalid MTU %v: it must be an integer", value)
+	}
+	if mtu != 0 {
+		if err := validateMTU(mtu); err != nil {
+			return 0, err
+		}
+	}
+	return mtu, nil
+}
+
+func validateMTU(mtu int) error {
+	if mtu < MinMTU || mtu > MaxMTU {
+		return fmt.Errorf("invalid MTU %d: it must be between %d and %d", mtu, MinMTU, MaxMTU)
+	}
+	return nil
+}
+
+// mtuOverhead returns the bytes the encapsulation and encryption modes of the agent
+// configuration add to the packets of the Pod network. WireGuard replaces the tunnel.
+func mtuOverhead(antreaAgentConfig map[string]interface{}) int {
+	encapMode, _ := antreaAgentConfig[trafficEncapModeOption].(string)
+	tunnelType, _ := antreaAgentConfig[tunnelTypeOption].(string)
+	encryptionMode, _ := antreaAgentConfig[trafficEncryptionModeOption].(string)
+	if antreaAgentConfig[enableIPSecTunnelOption] == true {
+		encryptionMode = trafficEncryptionModeIPSec
+	}
+	_, ipv6 := antreaAgentConfig[types.ServiceCIDRv6Option]
+
+	overhead := 0
+	switch strings.ToLower(encryptionMode) {
+	case trafficEncryptionModeWireGuard:
+		overhead = wireGuardOverhead
+		if ipv6 {
+			overhead += ipv6ExtraOverhead
+		}
+		return overhead
+	case trafficEncryptionModeIPSec:
+		overhead = ipsecESPOverhead
+	}
+	if encapMode != "" && encapMode != TrafficEncapModeEncap && encapMode != trafficEncapModeHybrid {
+		return overhead
+	}
+	switch strings.ToLower(tunnelType) {
+	case tunnelTypeVXLAN:
+		overhead += vxlanOverhead
+	case tunnelTypeGRE:
+		overhead += greOverhead
+	case tunnelTypeSTT:
+		overhead += sttOverhead
+	default:
+		overhead += geneveOverhead
+	}
+	if ipv6 {
+		overhead += ipv6ExtraOverhead
+	}
+	return overhead
+}
+
+// uplinkMTU returns the smallest uplink MTU annotated on the Nodes, or DefaultUplinkMTU.
+func (s *ClusterState) uplinkMTU() int {
+	if s == nil || len(s.NodeUplinkMTUs) == 0 {
+		return DefaultUplinkMTU
+	}
+	mtu := 0
+	for _, nodeMTU := range s.NodeUplinkMTUs {
+		if mtu == 0 || nodeMTU < mtu {
+			mtu = nodeMTU
+		}
+	}
+	return mtu
+}
+
+// UplinkMTUFallback tells whether the MTU derived from defaultMTU assumes DefaultUplinkMTU, as it is
+// auto and no Node is annotated with its uplink MTU.
+func UplinkMTUFallback(defaultMTU *intstr.IntOrString, state *ClusterState) bool {
+	if defaultMTU == nil || defaultMTU.Type != intstr.String || !strings.EqualFold(defaultMTU.StrVal, MTUAuto) {
+		return false
+	}
+	return state == nil || len(state.NodeUplinkMTUs) == 0
+}
+
+// fillDefaultMTU sets the defaultMTU option of the agent configuration from spec.defaultMTU, or
+// to profileMTU when neither sets it, and normalizes it to an integer.
+func fillDefaultMTU(antreaAgentConfig map[string]interface{}, defaultMTU *intstr.IntOrString, profileMTU int, state *ClusterState) error {
+	var mtu int
+	switch {
+	case defaultMTU == nil:
+		value, ok := antreaAgentConfig[types.DefaultMTUOption]
+		if !ok {
+			antreaAgentConfig[types.DefaultMTUOption] = profileMTU
+			return nil
+		}
+		parsed, err := ParseMTU(value)
+		if err != nil {
+			return fmt.Errorf("invalid %s option: %v", types.DefaultMTUOption, err)
+		}
+		mtu = parsed
+	case defaultMTU.Type == intstr.String && strings.EqualFold(defaultMTU.StrVal, MTUAuto):
+		uplinkMTU := state.uplinkMTU()
+		mtu = uplinkMTU - mtuOverhead(antreaAgentConfig)
+		if err := validateMTU(mtu); err != nil {
+			return fmt.Errorf("failed to derive the MTU from the uplink MTU %d: %v", uplinkMTU, err)
+		}
+		log.Info("derived the MTU from the uplink MTU", "uplinkMTU", uplinkMTU, "mtu", mtu)
+	default:
+		var value interface{} = defaultMTU.StrVal
+		if defaultMTU.Type == intstr.Int {
+			value = defaultMTU.IntValue()
+		}
+		parsed, err := ParseMTU(value)
+		if err != nil {
+			return fmt.Errorf("invalid spec.defaultMTU: %v", err)
+		}
+		mtu = parsed
+	}
+	if value, ok := antreaAgentConfig[types.DefaultMTUOption]; ok && defaultMTU != nil {
+		if parsed, err := ParseMTU(value); err != nil || parsed != mtu {
+			log.Info(fmt.Sprintf("WARNING: %s option is overwritten by spec.defaultMTU", types.DefaultMTUOption))
+		}
+	}
+	antreaAgentConfig[types.DefaultMTUOption] = mtu
+	return nil
+}
+
+// agentDefaultMTU returns the defaultMTU option of an agent configuration.
+func agentDefaultMTU(agentConfig string) (int, error) {
+	antreaAgentConfig := make(map[string]interface{})
+	if err := yaml.Unmarshal([]byte(agentConfig), &antreaAgentConfig); err != nil {
+		return 0, err
+	}
+	value, ok := antreaAgentConfig[types.DefaultMTUOption]
+	if !ok {
+		return 0, fmt.Errorf("%s option can not be empty", types.DefaultMTUOption)
+	}
+	return ParseMTU(value)
+}
@@ -0,0 +1,132 @@
+/* Copyright © 2026 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package config
+
+import (
+	"fmt"
+	gonet "net"
+
+	configv1 "github.com/openshift/api/config/v1"
+	"k8s.io/utils/net"
+)
+
+// networkFamilies is a cluster network split by IP family, in the order of the cluster config.
+type networkFamilies struct {
+	serviceIPv4 []string
+	serviceIPv6 []string
+	clusterIPv4 []configv1.ClusterNetworkEntry
+	clusterIPv6 []configv1.ClusterNetworkEntry
+}
+
+func splitNetworkFamilies(clusterConfig *configv1.Network) (*networkFamilies, []error) {
+	var errs []error
+	families := &networkFamilies{}
+	for _, cidr := range clusterConfig.Spec.ServiceNetwork {
+		switch {
+		case net.IsIPv4CIDRString(cidr):
+			families.serviceIPv4 = append(families.serviceIPv4, cidr)
+		case net.IsIPv6CIDRString(cidr):
+			families.serviceIPv6 = append(families.serviceIPv6, cidr)
+		default:
+			errs = append(errs, fmt.Errorf("invalid service network CIDR %q", cidr))
+		}
+	}
+	for _, entry := range clusterConfig.Spec.ClusterNetwork {
+		switch {
+		case net.IsIPv4CIDRString(entry.CIDR):
+			families.clusterIPv4 = append(families.clusterIPv4, entry)
+		case net.IsIPv6CIDRString(entry.CIDR):
+			families.clusterIPv6 = append(families.clusterIPv6, entry)
+		default:
+			errs = append(errs, fmt.Errorf("invalid cluster network CIDR %q", entry.CIDR))
+		}
+	}
+	return families, errs
+}
+
+// familyName describes the IP families present in a network.
+func familyName(ipv4, ipv6 bool) string {
+	switch {
+	case ipv4 && ipv6:
+		return "dual-stack"
+	case ipv6:
+		return "IPv6"
+	default:
+		return "IPv4"
+	}
+}
+
+// validateClusterNetwork checks that the cluster network can be used by Antrea: the host
+// prefixes must fit in their CIDRs and be the same for all the entries of a family, since
+// antrea-controller NodeIPAM has one Node CIDR mask size per family, and the service and cluster
+// networks must have the same IP families.
+func validateClusterNetwork(clusterConfig *configv1.Network) []error {
+	families, errs := splitNetworkFamilies(clusterConfig)
+	errs = append(errs, validateHostPrefixes("IPv4", families.clusterIPv4, 32)...)
+	errs = append(errs, validateHostPrefixes("IPv6", families.clusterIPv6, 128)...)
+
+	hasServiceIPv4, hasServiceIPv6 := len(families.serviceIPv4) > 0, len(families.serviceIPv6) > 0
+	hasClusterIPv4, hasClusterIPv6 := len(families.clusterIPv4) > 0, len(families.clusterIPv6) > 0
+	if (hasServiceIPv4 || hasServiceIPv6) && (hasClusterIPv4 || hasClusterIPv6) &&
+		(hasServiceIPv4 != hasClusterIPv4 || hasServiceIPv6 != hasClusterIPv6) {
+		errs = append(errs, fmt.Errorf("mixed IP families are not supported: the service network is %s and the cluster network is %s",
+			familyName(hasServiceIPv4, hasServiceIPv6), familyName(hasClusterIPv4, hasClusterIPv6)))
+	}
+	return errs
+}
+
+func validateHostPrefixes(family string, entries []configv1.ClusterNetworkEntry, bits int) []error {
+	var errs []error
+	for i, entry := range entries {
+		_, ipNet, _ := gonet.ParseCIDR(entry.CIDR)
+		prefixLength, _ := ipNet.Mask.Size()
+		if int(entry.HostPrefix) < prefixLength {
+			errs = append(errs, fmt.Errorf("host prefix %d is too small for cluster network %s, it must be at least %d", entry.HostPrefix, entry.CIDR, prefixLength))
+		} else if int(entry.HostPrefix) > bits {
+			errs = append(errs, fmt.Errorf("host prefix %d of cluster network %s is larger than %d", entry.HostPrefix, entry.CIDR, bits))
+		}
+		if i > 0 && entry.HostPrefix != entries[0].HostPrefix {
+			errs = append(errs, fmt.Errorf("inconsistent %s host prefixes: %s has %d but %s has %d", family, entry.CIDR, entry.HostPrefix, entries[0].CIDR, entries[0].HostPrefix))
+		}
+	}
+	return errs
+}
+
+// fillServiceCIDR sets a service CIDR option of the agent configuration to the first CIDR of its
+// family, unless it already holds one of them.
+func fillServiceCIDR(antreaAgentConfig map[string]interface{}, option string, cidrs []string) {
+	serviceCIDR, ok := antreaAgentConfig[option].(string)
+	if len(cidrs) == 0 {
+		if ok {
+			log.Info(fmt.Sprintf("WARNING: %s option is removed since the cluster has no service network of its IP family", option))
+			delete(antreaAgentConfig, option)
+		}
+		return
+	}
+	if !ok {
+		antreaAgentConfig[option] = cidrs[0]
+	} else if !inSlice(serviceCIDR, cidrs) {
+		log.Info(fmt.Sprintf("WARNING: %s option is overwritten by cluster config", option))
+		antreaAgentConfig[option] = cidrs[0]
+	}
+}
+
+// validateServiceCIDR checks that a service CIDR option of the agent configuration is one of the
+// service network CIDRs of its family.
+func validateServiceCIDR(antreaAgentConfig map[string]interface{}, option string, cidrs, serviceNetwork []string) error {
+	serviceCIDR, ok := antreaAgentConfig[option].(string)
+	if !ok {
+		if len(cidrs) > 0 {
+			return fmt.Errorf("%s option can not be empty", option)
+		}
+		return nil
+	}
+	if inSlice(serviceCIDR, cidrs) {
+		return nil
+	}
+	if len(cidrs) == 0 {
+		cidrs = serviceNetwork
+	}
+	return fmt.Errorf("invalid %s option: %s, available values are: %s", option, serviceCIDR, cidrs)
+}
@@ -0,0 +1,204 @@
+/* Copyright © 2026 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package config
+
+import (
+	"fmt"
+	"sort"
+
+	"antrea.io/antrea/pkg/features"
+	gocni "github.com/containerd/go-cni"
+	"gopkg.in/yaml.v2"
+
+	operatorv1 "github.com/vmware/antrea-operator-for-kubernetes/api/v1"
+	"github.com/vmware/antrea-operator-for-kubernetes/controllers/types"
+)
+
+const (
+	// ProfileAuto selects the profile of the detected distribution.
+	ProfileAuto = "auto"
+
+	ProfileGeneric   = "generic"
+	ProfileK3s       = "k3s"
+	ProfileRKE2      = "rke2"
+	ProfileMicroK8s  = "microk8s"
+	ProfileEKS       = "eks"
+	ProfileAKS       = "aks"
+	ProfileGKE       = "gke"
+	ProfileOpenShift = "openshift"
+
+	trafficEncapModeOption = "trafficEncapMode"
+	featureGatesOption     = "featureGates"
+
+	TrafficEncapModeEncap             = "encap"
+	TrafficEncapModeNoEncap           = "noEncap"
+	TrafficEncapModeNetworkPolicyOnly = "networkPolicyOnly"
+)
+
+// Profile holds the defaults of a Kubernetes distribution.
+type Profile struct {
+	Name string
+	// CNIConfDir and CNIBinDir are the directories where the container runtime looks for the CNI
+	// configuration and binaries. They are empty on OpenShift, where they depend on the cluster
+	// network operator configuration.
+	CNIConfDir string
+	CNIBinDir  string
+	// TrafficEncapMode and DefaultMTU are the antrea-agent defaults, used when the options are
+	// not set in AntreaAgentConfig. As they select the datapath, they are only used on a fresh
+	// installation, or when the profile is selected explicitly, see selectDatapathDefaults.
+	TrafficEncapMode string
+	DefaultMTU       int
+	// CNIChaining tells whether Antrea is chained to the primary CNI of the distribution by
+	// default, in networkPolicyOnly mode.
+	CNIChaining bool
+	// AgentFeatureGates are the antrea-agent feature gates which are required by the datapath of
+	// the distribution. They are set when antrea-agent runs in the TrafficEncapMode of the profile.
+	AgentFeatureGates map[string]bool
+	// ControllerFeatureGates are the antrea-controller feature gates which are required on the
+	// distribution, and are always set.
+	ControllerFeatureGates map[string]bool
+}
+
+// networkPolicyOnlyFeatureGates turns off the features which are not supported when Antrea is
+// chained to a cloud CNI.
+var networkPolicyOnlyFeatureGates = map[string]bool{
+	string(features.Egress):    false,
+	string(features.Multicast): false,
+}
+
+// Profiles holds the profiles which can be selected with spec.profile.
+var Profiles = map[string]*Profile{
+	ProfileGeneric: {
+		Name:             ProfileGeneric,
+		CNIConfDir:       gocni.DefaultNetDir,
+		CNIBinDir:        gocni.DefaultCNIDir,
+		TrafficEncapMode: TrafficEncapModeEncap,
+		DefaultMTU:       types.DefaultMTU,
+	},
+	ProfileK3s: {
+		Name:             ProfileK3s,
+		CNIConfDir:       "/var/lib/rancher/k3s/agent/etc/cni/net.d",
+		CNIBinDir:        "/var/lib/rancher/k3s/data/current/bin",
+		TrafficEncapMode: TrafficEncapModeEncap,
+		DefaultMTU:       types.DefaultMTU,
+	},
+	ProfileRKE2: {
+		Name:             ProfileRKE2,
+		CNIConfDir:       "/var/lib/rancher/rke2/agent/etc/cni/net.d",
+		CNIBinDir:        gocni.DefaultCNIDir,
+		TrafficEncapMode: TrafficEncapModeEncap,
+		DefaultMTU:       types.DefaultMTU,
+	},
+	ProfileMicroK8s: {
+		Name:             ProfileMicroK8s,
+		CNIConfDir:       "/var/snap/microk8s/current/args/cni-network",
+		CNIBinDir:        "/var/snap/microk8s/current/opt/cni/bin",
+		TrafficEncapMode: TrafficEncapModeEncap,
+		DefaultMTU:       types.DefaultMTU,
+	},
+	// On EKS and AKS, Pod networking is provided by the cloud CNI and Antrea only enforces
+	// NetworkPolicies. Egress and Multicast need Antrea to forward the Pod traffic, which it does
+	// not do in networkPolicyOnly mode.
+	ProfileEKS: {
+		Name:              ProfileEKS,
+		CNIConfDir:        gocni.DefaultNetDir,
+		CNIBinDir:         gocni.DefaultCNIDir,
+		TrafficEncapMode:  TrafficEncapModeNetworkPolicyOnly,
+		DefaultMTU:        9001,
+		CNIChaining:       true,
+		AgentFeatureGates: networkPolicyOnlyFeatureGates,
+	},
+	ProfileAKS: {
+		Name:              ProfileAKS,
+		CNIConfDir:        gocni.DefaultNetDir,
+		CNIBinDir:         gocni.DefaultCNIDir,
+		TrafficEncapMode:  TrafficEncapModeNetworkPolicyOnly,
+		DefaultMTU:        1500,
+		CNIChaining:       true,
+		AgentFeatureGates: networkPolicyOnlyFeatureGates,
+	},
+	// On GKE, Pod traffic is routed by the VPC, whose default MTU is 1460.
+	ProfileGKE: {
+		Name:             ProfileGKE,
+		CNIConfDir:       gocni.DefaultNetDir,
+		CNIBinDir:        "/home/kubernetes/bin",
+		TrafficEncapMode: TrafficEncapModeNoEncap,
+		DefaultMTU:       1460,
+	},
+	ProfileOpenShift: {
+		Name:             ProfileOpenShift,
+		TrafficEncapMode: TrafficEncapModeEncap,
+		DefaultMTU:       types.DefaultMTU,
+		ControllerFeatureGates: map[string]bool{
+			string(features.NodeIPAM): true,
+		},
+	},
+}
+
+// ProfileNames returns the names accepted by spec.profile.
+func ProfileNames() []string {
+	names := make([]string, 0, len(Profiles)+1)
+	for name := range Profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return append(names, ProfileAuto)
+}
+
+// SelectProfile returns the profile selected by name for platform openShift or not. distribution
+// is the detected distribution, which is used when name is auto or empty.
+func SelectProfile(name, distribution string, openShift bool) (*Profile, error) {
+	if name == "" || name == ProfileAuto {
+		name = distribution
+		if openShift {
+			name = ProfileOpenShift
+		} else if _, ok := Profiles[name]; !ok || name == ProfileOpenShift {
+			name = ProfileGeneric
+		}
+	}
+	profile, ok := Profiles[name]
+	if !ok {
+		return nil, fmt.Errorf("invalid profile %q, available profiles are: %v", name, ProfileNames())
+	}
+	if openShift && name != ProfileOpenShift {
+		return nil, fmt.Errorf("profile %s cannot be used on OpenShift", name)
+	}
+	if !openShift && name == ProfileOpenShift {
+		return nil, fmt.Errorf("profile %s can only be used on OpenShift", name)
+	}
+	return profile, nil
+}
+
+// datapathDefaults holds the defaults of the agent options which select the datapath.
+type datapathDefaults struct {
+	trafficEncapMode string
+	defaultMTU       int
+	cniChaining      bool
+}
+
+// selectDatapathDefaults returns the datapath defaults of operConfig. Changing them restarts a
+// running installation on another datapath, so those of a detected profile are only used on a
+// fresh installation: a running installation keeps the traffic mode and MTU of the applied agent
+// configuration, or the generic defaults, unless spec.profile selects the profile explicitly.
+func selectDatapathDefaults(operConfig *operatorv1.AntreaInstall, profile *Profile, state *ClusterState) datapathDefaults {
+	explicit := operConfig.Spec.Profile != "" && operConfig.Spec.Profile != ProfileAuto
+	if explicit || state == nil || state.AppliedConfig == nil {
+		return datapathDefaults{trafficEncapMode: profile.TrafficEncapMode, defaultMTU: profile.DefaultMTU, cniChaining: profile.CNIChaining}
+	}
+	generic := Profiles[ProfileGeneric]
+	defaults := datapathDefaults{trafficEncapMode: generic.TrafficEncapMode, defaultMTU: generic.DefaultMTU}
+	appliedAgentConfig := make(map[string]interface{})
+	if err := yaml.Unmarshal([]byte(state.AppliedConfig.Spec.AntreaAgentConfig), &appliedAgentConfig); err != nil {
+		return defaults
+	}
+	if encapMode, ok := appliedAgentConfig[trafficEncapModeOption].(string); ok && encapMode != "" {
+		defaults.trafficEncapMode = encapMode
+	}
+	if mtu, err := ParseMTU(appliedAgentConfig[types.DefaultMTUOption]); err == nil {
+		defaults.defaultMTU = mtu
+	}
+	// networkPolicyOnly mode is only supported with chaining.
+	defaults.cniChaining = defaults.trafficEncapMode == TrafficEncapModeNetworkPolicyOnly
+	return defaults
+}
@@ -0,0 +1,229 @@
+/* Copyright © 2026 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/containernetworking/cni/libcni"
+	cniversion "github.com/containernetworking/cni/pkg/version"
+	"gopkg.in/yaml.v2"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	operatorv1 "github.com/vmware/antrea-operator-for-kubernetes/api/v1"
+	"github.com/vmware/antrea-operator-for-kubernetes/controllers/antreaversion"
+)
+
+const (
+	antreaCNIPluginType = "antrea"
+
+	// The default configurations of Antrea, bundled with the manifests of each version.
+	agentDefaultsFile      = "antrea-agent.conf"
+	controllerDefaultsFile = "antrea-controller.conf"
+)
+
+var (
+	// antreaIPAMTypes are the IPAM types the antrea CNI plugin accepts.
+	antreaIPAMTypes = []string{"host-local", "antrea"}
+	// knownCNIPluginTypes are the plugins Antrea chains after the antrea plugin.
+	knownCNIPluginTypes = map[string]bool{antreaCNIPluginType: true, "portmap": true, "bandwidth": true}
+)
+
+// SchemaWarnings are the findings of a schema validation which do not prevent the configurations
+// from being applied, such as the fields unknown to Antrea, which it ignores.
+type SchemaWarnings []string
+
+// ValidateSchema decodes the agent and controller configurations of operConfig strictly against
+// the default configurations of the Antrea version of spec.antreaVersion, bundled in manifestDir.
+// The options whose default value is empty, such as the lists which are not set by default, accept
+// any value. It parses the CNI conflist as libcni does. Fields of the wrong type and invalid CNI
+// configurations are returned as errors, with the paths of the offending fields, and unknown fields
+// as warnings. The agent and controller configurations are only checked to be valid YAML without
+// manifestDir, and for the manifests of spec.manifestSource, whose version is unknown.
+func ValidateSchema(operConfig *operatorv1.AntreaInstall, manifestDir string) (field.ErrorList, SchemaWarnings) {
+	var errs field.ErrorList
+	var warnings SchemaWarnings
+	specPath := field.NewPath("spec")
+
+	for _, config := range []struct {
+		path         *field.Path
+		value        string
+		defaultsFile string
+	}{
+		{specPath.Child("antreaAgentConfig"), operConfig.Spec.AntreaAgentConfig, agentDefaultsFile},
+		{specPath.Child("antreaControllerConfig"), operConfig.Spec.AntreaControllerConfig, controllerDefaultsFile},
+	} {
+		var value interface{}
+		if err := yaml.Unmarshal([]byte(config.value), &value); err != nil {
+			errs = append(errs, field.Invalid(config.path, "", fmt.Sprintf("failed to parse YAML: %v", err)))
+			continue
+		}
+		if value == nil || manifestDir == "" || operConfig.Spec.ManifestSource != nil {
+			continue
+		}
+		defaults, err := loadDefaults(manifestDir, operConfig.Spec.AntreaVersion, config.defaultsFile)
+		if err != nil {
+			errs = append(errs, field.InternalError(config.path, err))
+			continue
+		}
+		if defaults == nil {
+			continue
+		}
+		schemaErrs, schemaWarnings := validateValue(config.path, value, defaults)
+		errs = append(errs, schemaErrs...)
+		warnings = append(warnings, schemaWarnings...)
+	}
+
+	cniErrs, cniWarnings := validateCNIConfig(specPath.Child("antreaCNIConfig"), operConfig.Spec.AntreaCNIConfig)
+	errs = append(errs, cniErrs...)
+	warnings = append(warnings, cniWarnings...)
+	return errs, warnings
+}
+
+// loadDefaults reads the default configuration file name bundled with the manifests of an Antrea
+// version in manifestDir, which every bundled version must provide. It returns nil when the version
+// is not bundled.
+func loadDefaults(manifestDir string, version string, name string) (interface{}, error) {
+	versionDir, err := antreaversion.ManifestDir(manifestDir, version)
+	if err != nil {
+		// An Antrea version which is not bundled is refused when the manifests are rendered.
+		return nil, nil
+	}
+	data, err := os.ReadFile(filepath.Join(versionDir, name))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the default configuration: %v", err)
+	}
+	var defaults interface{}
+	if err := yaml.Unmarshal(data, &defaults); err != nil {
+		return nil, fmt.Errorf("failed to parse the default configuration %s: %v", filepath.Join(versionDir, name), err)
+	}
+	return defaults, nil
+}
+
+// ToValidationErrors converts the errors of ValidateSchema into ValidationErrors, one per line.
+func ToValidationErrors(errs field.ErrorList) ValidationErrors {
+	validationErrs := make(ValidationErrors, 0, len(errs))
+	for _, err := range errs {
+		validationErrs = append(validationErrs, err)
+	}
+	return validationErrs
+}
+
+// validateValue checks a value decoded by yaml.v2 against its value in the default configuration,
+// which tells the type of the options of the rendered Antrea version.
+func validateValue(path *field.Path, value interface{}, defaultValue interface{}) (field.ErrorList, SchemaWarnings) {
+	var errs field.ErrorList
+	var warnings SchemaWarnings
+	if value == nil {
+		return nil, nil
+	}
+
+	switch defaults := defaultValue.(type) {
+	case map[interface{}]interface{}:
+		if len(defaults) == 0 {
+			return nil, nil
+		}
+		values, ok := value.(map[interface{}]interface{})
+		if !ok {
+			return field.ErrorList{field.TypeInvalid(path, value, "must be an object")}, nil
+		}
+		defaultValues, _ := stringKeys(defaults)
+		fieldValues, keys := stringKeys(values)
+		for _, key := range keys {
+			fieldDefault, known := defaultValues[key]
+			if !known {
+				warnings = append(warnings, fmt.Sprintf("%s: unknown field", path.Child(key)))
+				continue
+			}
+			fieldErrs, fieldWarnings := validateValue(path.Child(key), fieldValues[key], fieldDefault)
+			errs = append(errs, fieldErrs...)
+			warnings = append(warnings, fieldWarnings...)
+		}
+	case []interface{}:
+		if len(defaults) == 0 {
+			return nil, nil
+		}
+		values, ok := value.([]interface{})
+		if !ok {
+			return field.ErrorList{field.TypeInvalid(path, value, "must be a list")}, nil
+		}
+		for i, item := range values {
+			itemErrs, itemWarnings := validateValue(path.Index(i), item, defaults[0])
+			errs = append(errs, itemErrs...)
+			warnings = append(warnings, itemWarnings...)
+		}
+	case string:
+		if _, ok := value.(string); !ok {
+			errs = append(errs, field.TypeInvalid(path, value, "must be a string"))
+		}
+	case bool:
+		if _, ok := value.(bool); !ok {
+			errs = append(errs, field.TypeInvalid(path, value, "must be a boolean"))
+		}
+	case int:
+		if _, ok := value.(int); !ok {
+			errs = append(errs, field.TypeInvalid(path, value, "must be an integer"))
+		}
+	case float64:
+		switch value.(type) {
+		case int, float64:
+		default:
+			errs = append(errs, field.TypeInvalid(path, value, "must be a number"))
+		}
+	}
+	return errs, warnings
+}
+
+// stringKeys returns a map decoded by yaml.v2 with its keys, which may be numbers, as strings, and
+// the sorted keys.
+func stringKeys(values map[interface{}]interface{}) (map[string]interface{}, []string) {
+	result := make(map[string]interface{}, len(values))
+	keys := make([]string, 0, len(values))
+	for key, value := range values {
+		result[fmt.Sprint(key)] = value
+		keys = append(keys, fmt.Sprint(key))
+	}
+	sort.Strings(keys)
+	return result, keys
+}
+
+// validateCNIConfig parses the CNI conflist as libcni does and checks that the CNI version is
+// supported, that the antrea plugin comes first with a valid IPAM, and that the chained plugins
+// are known.
+func validateCNIConfig(path *field.Path, config string) (field.ErrorList, SchemaWarnings) {
+	var errs field.ErrorList
+	var warnings SchemaWarnings
+	confList, err := libcni.ConfListFromBytes([]byte(config))
+	if err != nil {
+		return field.ErrorList{field.Invalid(path, "", err.Error())}, nil
+	}
+
+	supportedVersions := cniversion.All.SupportedVersions()
+	if !inSlice(confList.CNIVersion, supportedVersions) {
+		errs = append(errs, field.NotSupported(path.Child("cniVersion"), confList.CNIVersion, supportedVersions))
+	}
+
+	pluginsPath := path.Child("plugins")
+	if len(confList.Plugins) == 0 || confList.Plugins[0].Network.Type != antreaCNIPluginType {
+		errs = append(errs, field.Invalid(pluginsPath.Index(0).Child("type"), "", fmt.Sprintf("the first plugin must be %s", antreaCNIPluginType)))
+	}
+	for i, plugin := range confList.Plugins {
+		pluginPath := pluginsPath.Index(i)
+		if !knownCNIPluginTypes[plugin.Network.Type] {
+			warnings = append(warnings, fmt.Sprintf("%s: unknown plugin type %q", pluginPath.Child("type"), plugin.Network.Type))
+		}
+		if plugin.Network.Type != antreaCNIPluginType {
+			continue
+		}
+		if plugin.Network.IPAM.Type == "" {
+			errs = append(errs, field.Required(pluginPath.Child("ipam", "type"), "the antrea plugin requires an IPAM"))
+		} else if !inSlice(plugin.Network.IPAM.Type, antreaIPAMTypes) {
+			errs = append(errs, field.NotSupported(pluginPath.Child("ipam", "type"), plugin.Network.IPAM.Type, antreaIPAMTypes))
+		}
+	}
+	return errs, warnings
+}
@@ -0,0 +1,221 @@
+/* Copyright © 2026 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package config
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	configv1 "github.com/openshift/api/config/v1"
+	"gopkg.in/yaml.v2"
+
+	operatorv1 "github.com/vmware/antrea-operator-for-kubernetes/api/v1"
+	"github.com/vmware/antrea-operator-for-kubernetes/controllers/types"
+)
+
+const (
+	noSNATOption             = "noSNAT"
+	transportInterfaceOption = "transportInterface"
+
+	// maxInterfaceNameLength is IFNAMSIZ minus the terminating NUL.
+	maxInterfaceNameLength = 15
+)
+
+// fillTraffic sets the traffic options of the agent configuration from spec.traffic.
+func fillTraffic(antreaAgentConfig map[string]interface{}, traffic *operatorv1.TrafficSpec) {
+	if traffic == nil {
+		return
+	}
+	setOption := func(option string, value interface{}) {
+		if current, ok := antreaAgentConfig[option]; ok && current != value {
+			log.Info(fmt.Sprintf("WARNING: %s option is overwritten by spec.traffic", option))
+		}
+		antreaAgentConfig[option] = value
+	}
+	if traffic.EncapMode != "" {
+		setOption(trafficEncapModeOption, traffic.EncapMode)
+	}
+	if traffic.TunnelType != "" {
+		setOption(tunnelTypeOption, traffic.TunnelType)
+	}
+	if traffic.NoSNAT != nil {
+		setOption(noSNATOption, *traffic.NoSNAT)
+	}
+	if traffic.TransportInterface != "" {
+		setOption(transportInterfaceOption, traffic.TransportInterface)
+	}
+}
+
+// cniChaining tells whether Antrea is chained to the primary CNI, from spec.cniChaining, and
+// returns the configured primary conflist.
+func cniChaining(operConfig *operatorv1.AntreaInstall) (bool, string) {
+	if operConfig.Spec.CNIChaining == nil {
+		return false, ""
+	}
+	return operConfig.Spec.CNIChaining.Enabled, operConfig.Spec.CNIChaining.PrimaryConfList
+}
+
+// fillCNIChaining sets spec.cniChaining to its default when it is unset, and the networkPolicyOnly
+// mode when Antrea is chained to the primary CNI. A traffic mode set in AntreaAgentConfig or
+// spec.traffic is never overwritten: Antrea is only chained by default when no other mode is set,
+// and chaining it with another mode is refused by the validation.
+func fillCNIChaining(antreaAgentConfig map[string]interface{}, operConfig *operatorv1.AntreaInstall, defaultEnabled bool) {
+	encapMode, encapModeSet := antreaAgentConfig[trafficEncapModeOption]
+	if operConfig.Spec.CNIChaining == nil && defaultEnabled && (!encapModeSet || encapMode == TrafficEncapModeNetworkPolicyOnly) {
+		operConfig.Spec.CNIChaining = &operatorv1.CNIChainingSpec{Enabled: true}
+	}
+	if enabled, _ := cniChaining(operConfig); enabled && !encapModeSet {
+		antreaAgentConfig[trafficEncapModeOption] = TrafficEncapModeNetworkPolicyOnly
+	}
+}
+
+// validateCNIChaining checks that Antrea is chained to the primary CNI exactly in
+// networkPolicyOnly mode, and that the primary conflist is a file name.
+func validateCNIChaining(operConfig *operatorv1.AntreaInstall, antreaAgentConfig map[string]interface{}, profile *Profile) []error {
+	var errs []error
+	encapMode, _ := antreaAgentConfig[trafficEncapModeOption].(string)
+	enabled, primaryConfList := cniChaining(operConfig)
+	switch {
+	case enabled && profile.Name == ProfileOpenShift:
+		errs = append(errs, fmt.Errorf("CNI chaining is not supported on OpenShift"))
+	case enabled && encapMode != TrafficEncapModeNetworkPolicyOnly:
+		errs = append(errs, fmt.Errorf("CNI chaining requires %s mode, but %s is %s", TrafficEncapModeNetworkPolicyOnly, trafficEncapModeOption, encapMode))
+	case !enabled && encapMode == TrafficEncapModeNetworkPolicyOnly:
+		errs = append(errs, fmt.Errorf("%s mode requires Antrea to be chained to the primary CNI of the cluster, set spec.cniChaining.enabled",
+			TrafficEncapModeNetworkPolicyOnly))
+	}
+	if primaryConfList != "" && (strings.Contains(primaryConfList, "/") || !strings.HasSuffix(primaryConfList, ".conflist")) {
+		errs = append(errs, fmt.Errorf("invalid spec.cniChaining.primaryConfList: %q is not the file name of a conflist", primaryConfList))
+	}
+	return errs
+}
+
+// encapsulated tells whether an encapsulation mode uses tunnels, at least between subnets.
+func encapsulated(encapMode string) bool {
+	return encapMode == "" || encapMode == TrafficEncapModeEncap || encapMode == trafficEncapModeHybrid
+}
+
+// validateTraffic checks the combinations of the traffic options of a filled agent configuration:
+// noEncap needs routable Pod CIDRs, networkPolicyOnly needs a primary CNI to chain to, noSNAT is
+// only supported in noEncap mode, and the MTU plus the overhead of the tunnel must fit in the
+// uplink MTU of the Nodes.
+func validateTraffic(clusterConfig *configv1.Network, operConfig *operatorv1.AntreaInstall, antreaAgentConfig map[string]interface{}, profile *Profile, state *ClusterState) []error {
+	var errs []error
+	encapMode, _ := antreaAgentConfig[trafficEncapModeOption].(string)
+
+	switch encapMode {
+	case "", TrafficEncapModeEncap, trafficEncapModeHybrid, TrafficEncapModeNoEncap, TrafficEncapModeNetworkPolicyOnly:
+	default:
+		errs = append(errs, fmt.Errorf("invalid %s option: %s, available values are: %s", trafficEncapModeOption, encapMode,
+			[]string{TrafficEncapModeEncap, TrafficEncapModeNoEncap, trafficEncapModeHybrid, TrafficEncapModeNetworkPolicyOnly}))
+	}
+	if tunnelType, ok := antreaAgentConfig[tunnelTypeOption].(string); ok {
+		if !inSlice(strings.ToLower(tunnelType), []string{tunnelTypeGeneve, tunnelTypeVXLAN, tunnelTypeGRE, tunnelTypeSTT}) {
+			errs = append(errs, fmt.Errorf("invalid %s option: %s, available values are: %s", tunnelTypeOption, tunnelType,
+				[]string{tunnelTypeGeneve, tunnelTypeVXLAN, tunnelTypeGRE, tunnelTypeSTT}))
+		} else if !encapsulated(encapMode) {
+			log.Info(fmt.Sprintf("WARNING: %s option is ignored in %s mode", tunnelTypeOption, encapMode))
+		}
+	}
+
+	errs = append(errs, validateCNIChaining(operConfig, antreaAgentConfig, profile)...)
+	if encapMode == TrafficEncapModeNoEncap {
+		if clusterConfig == nil || len(clusterConfig.Spec.ClusterNetwork) == 0 {
+			if nodes := nodesWithoutPodCIDRs(state); len(nodes) > 0 {
+				errs = append(errs, fmt.Errorf("%s mode requires routable Pod CIDRs, but the cluster network is unknown and Nodes %s have no Pod CIDR",
+					TrafficEncapModeNoEncap, strings.Join(nodes, ", ")))
+			}
+		}
+	}
+
+	if noSNAT, ok := antreaAgentConfig[noSNATOption].(bool); ok && noSNAT {
+		switch encapMode {
+		case TrafficEncapModeNoEncap:
+		case TrafficEncapModeNetworkPolicyOnly:
+			log.Info(fmt.Sprintf("WARNING: %s option is ignored in %s mode", noSNATOption, encapMode))
+		default:
+			errs = append(errs, fmt.Errorf("%s option is only supported in %s mode", noSNATOption, TrafficEncapModeNoEncap))
+		}
+	}
+
+	if transportInterface, ok := antreaAgentConfig[transportInterfaceOption].(string); ok && transportInterface != "" {
+		if len(transportInterface) > maxInterfaceNameLength || strings.ContainsAny(transportInterface, "/ \t\n") {
+			errs = append(errs, fmt.Errorf("invalid %s option: %q is not a valid interface name", transportInterfaceOption, transportInterface))
+		}
+		if _, ok := antreaAgentConfig[transportInterfaceCIDRsOption]; ok {
+			log.Info(fmt.Sprintf("WARNING: %s option takes precedence over %s", transportInterfaceOption, transportInterfaceCIDRsOption))
+		}
+	}
+
+	if state != nil && len(state.NodeUplinkMTUs) > 0 {
+		if mtu, err := ParseMTU(antreaAgentConfig[types.DefaultMTUOption]); err == nil && mtu > 0 {
+			uplinkMTU, overhead := state.uplinkMTU(), mtuOverhead(antreaAgentConfig)
+			if mtu+overhead > uplinkMTU {
+				errs = append(errs, fmt.Errorf("defaultMTU %d plus the %d bytes of encapsulation and encryption overhead exceeds the uplink MTU %d of the Nodes",
+					mtu, overhead, uplinkMTU))
+			}
+		}
+	}
+	return errs
+}
+
+// nodesWithoutPodCIDRs returns the sorted names of the Nodes which have no Pod CIDR.
+func nodesWithoutPodCIDRs(state *ClusterState) []string {
+	if state == nil {
+		return nil
+	}
+	var nodes []string
+	for node := range state.NodeInternalIPs {
+		if len(state.NodePodCIDRs[node]) == 0 {
+			nodes = append(nodes, node)
+		}
+	}
+	sort.Strings(nodes)
+	return nodes
+}
+
+// trafficMode returns the encapsulation mode and the tunnel type of an agent configuration, with
+// the antrea-agent defaults.
+func trafficMode(agentConfig string) (string, string, error) {
+	antreaAgentConfig := make(map[string]interface{})
+	if err := yaml.Unmarshal([]byte(agentConfig), &antreaAgentConfig); err != nil {
+		return "", "", fmt.Errorf("failed to parse AntreaAgentConfig: %v", err)
+	}
+	encapMode, _ := antreaAgentConfig[trafficEncapModeOption].(string)
+	if encapMode == "" {
+		encapMode = TrafficEncapModeEncap
+	}
+	tunnelType, _ := antreaAgentConfig[tunnelTypeOption].(string)
+	if tunnelType == "" {
+		tunnelType = tunnelTypeGeneve
+	}
+	return encapMode, strings.ToLower(tunnelType), nil
+}
+
+// ValidateTrafficModeChange refuses to change the encapsulation mode, or the tunnel type of an
+// encapsulated mode, of a running installation unless spec.traffic.allowMigration is set, since
+// the Pod traffic between the Nodes is disrupted until all the antrea-agents have restarted.
+func ValidateTrafficModeChange(preConfig, curConfig *operatorv1.AntreaInstall) error {
+	if preConfig == nil || (curConfig.Spec.Traffic != nil && curConfig.Spec.Traffic.AllowMigration) {
+		return nil
+	}
+	preEncapMode, preTunnelType, err := trafficMode(preConfig.Spec.AntreaAgentConfig)
+	if err != nil {
+		return err
+	}
+	curEncapMode, curTunnelType, err := trafficMode(curConfig.Spec.AntreaAgentConfig)
+	if err != nil {
+		return err
+	}
+	if preEncapMode != curEncapMode {
+		return fmt.Errorf("changing %s from %s to %s requires draining the Nodes, set spec.traffic.allowMigration to proceed",
+			trafficEncapModeOption, preEncapMode, curEncapMode)
+	}
+	if encapsulated(curEncapMode) && preTunnelType != curTunnelType {
+		return fmt.Errorf("changing %s from %s to %s requires draining the Nodes, set spec.traffic.allowMigration to proceed",
+			tunnelTypeOption, preTunnelType, curTunnelType)
+	}
+	return nil
+}
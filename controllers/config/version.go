@@ -0,0 +1,68 @@
+/* Copyright © 2026 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package config
+
+import (
+	operatorv1 "github.com/vmware/antrea-operator-for-kubernetes/api/v1"
+	"github.com/vmware/antrea-operator-for-kubernetes/controllers/antreaversion"
+	"github.com/vmware/antrea-operator-for-kubernetes/controllers/types"
+)
+
+// AntreaImage returns the image of spec: spec.antreaImage, or the default image when it is unset,
+// whose tag is replaced by spec.antreaVersion when it is set. Without a version, the default image
+// is tagged with the latest Antrea version bundled in manifestDir, as its manifests are rendered.
+func AntreaImage(spec *operatorv1.AntreaInstallSpec, manifestDir string) string {
+	if spec.AntreaVersion != "" {
+		if version, err := antreaversion.Parse(spec.AntreaVersion); err == nil {
+			return antreaversion.Image(spec.AntreaImage, version)
+		}
+	}
+	if spec.AntreaImage != "" {
+		return spec.AntreaImage
+	}
+	if versions, err := antreaversion.Bundled(manifestDir); err == nil && len(versions) > 0 {
+		return antreaversion.Image("", versions[len(versions)-1])
+	}
+	return types.DefaultAntreaImage
+}
+
+// validateAntreaVersion checks that spec.antreaVersion, when it is set, is a release version.
+func validateAntreaVersion(spec *operatorv1.AntreaInstallSpec) error {
+	if spec.AntreaVersion == "" {
+		return nil
+	}
+	_, err := antreaversion.Parse(spec.AntreaVersion)
+	return err
+}
+
+// ValidateVersionChange refuses the changes of the Antrea version of a running installation which
+// are not supported upgrade or downgrade paths. The version of a configuration is its antreaVersion,
+// or the tag of its image when it is a release version, such as the latest bundled version the
+// filled default image is tagged with; the change is not checked when a version is unknown.
+func ValidateVersionChange(preConfig, curConfig *operatorv1.AntreaInstall) error {
+	if preConfig == nil {
+		return nil
+	}
+	if err := validateAntreaVersion(&curConfig.Spec); err != nil {
+		return err
+	}
+	to, ok := configVersion(&curConfig.Spec)
+	if !ok {
+		return nil
+	}
+	from, ok := configVersion(&preConfig.Spec)
+	if !ok {
+		return nil
+	}
+	return antreaversion.ValidateChange(from, to, curConfig.Spec.AllowDowngrade)
+}
+
+// configVersion returns the Antrea version of spec: spec.antreaVersion, or the tag of
+// spec.antreaImage when it is a release version.
+func configVersion(spec *operatorv1.AntreaInstallSpec) (antreaversion.Version, bool) {
+	if version, err := antreaversion.Parse(spec.AntreaVersion); err == nil {
+		return version, true
+	}
+	return antreaversion.ImageVersion(spec.AntreaImage)
+}
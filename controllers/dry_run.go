@@ -0,0 +1,131 @@
+/* Copyright © 2026 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	configv1 "github.com/openshift/api/config/v1"
+	"github.com/openshift/cluster-network-operator/pkg/render"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	uns "k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	operatorv1 "github.com/vmware/antrea-operator-for-kubernetes/api/v1"
+	configutil "github.com/vmware/antrea-operator-for-kubernetes/controllers/config"
+	"github.com/vmware/antrea-operator-for-kubernetes/controllers/preview"
+	"github.com/vmware/antrea-operator-for-kubernetes/controllers/statusmanager"
+	operatortypes "github.com/vmware/antrea-operator-for-kubernetes/controllers/types"
+)
+
+// dryRun renders the filled configurations of operConfig and publishes the changes applying them
+// would make in AntreaInstall.Status, instead of applying them.
+func dryRun(r *AntreaInstallReconciler, renderData *render.RenderData, appliedConfig, operConfig *operatorv1.AntreaInstall) (reconcile.Result, error) {
+	previewStatus, err := publishPreview(r, renderData, appliedConfig, operConfig, "")
+	if err != nil {
+		return reconcile.Result{Requeue: true}, err
+	}
+	r.Status.Normal(statusmanager.EventReasonDryRun, fmt.Sprintf("Dry run: %d objects would be created, %d updated and %d deleted, see status.preview",
+		len(previewStatus.Created), len(previewStatus.Updated), len(previewStatus.Deleted)))
+	return reconcile.Result{}, nil
+}
+
+// publishPreview renders the filled configurations of operConfig, and publishes the changes
+// applying them would make, with their revision if any, in AntreaInstall.Status.
+func publishPreview(r *AntreaInstallReconciler, renderData *render.RenderData, appliedConfig, operConfig *operatorv1.AntreaInstall, revision string) (*operatorv1.PreviewStatus, error) {
+	objs, err := renderManifests(r, renderData, operConfig)
+	if err != nil {
+		return nil, err
+	}
+	previewStatus, err := previewChanges(r, objs, appliedConfig, operConfig)
+	if err != nil {
+		log.Error(err, "failed to preview changes")
+		r.Status.SetDegraded(statusmanager.OperatorConfig, "InternalError", fmt.Sprintf("Failed to preview configuration changes: %v", err))
+		return nil, err
+	}
+	previewStatus.Revision = revision
+	if err := r.Status.SetPreview(previewStatus); err != nil {
+		return nil, err
+	}
+	return previewStatus, nil
+}
+
+// previewChanges compares the rendered objects with the live ones, and predicts the Pod restarts
+// and the traffic mode change refusal of applyConfig.
+func previewChanges(r *AntreaInstallReconciler, objs []*uns.Unstructured, appliedConfig, operConfig *operatorv1.AntreaInstall) (*operatorv1.PreviewStatus, error) {
+	previewStatus := &operatorv1.PreviewStatus{ObservedGeneration: operConfig.Generation}
+	for _, obj := range objs {
+		live := &uns.Unstructured{}
+		live.SetGroupVersionKind(obj.GroupVersionKind())
+		if err := r.APIReader.Get(context.TODO(), client.ObjectKeyFromObject(obj), live); err != nil {
+			if !apierrors.IsNotFound(err) && !meta.IsNoMatchError(err) {
+				return nil, fmt.Errorf("failed to get %s %s: %v", obj.GetKind(), obj.GetName(), err)
+			}
+			live = nil
+		}
+		diff := preview.DiffObject(obj, live)
+		switch {
+		case diff.Created:
+			previewStatus.Created = append(previewStatus.Created, diff.String())
+		case diff.Changed():
+			previewStatus.Updated = append(previewStatus.Updated, diff.String())
+		}
+	}
+	if OwnedByClusterNetwork(r.Status.AdaptorName) {
+		deleted, err := DeletedObjects(context.TODO(), r.APIReader, r.Mapper, objs)
+		if err != nil {
+			return nil, err
+		}
+		previewStatus.Deleted = deleted
+	}
+
+	if err := configutil.ValidateTrafficModeChange(appliedConfig, operConfig); err != nil {
+		previewStatus.Refused = err.Error()
+	}
+	if err := configutil.ValidateVersionChange(appliedConfig, operConfig); err != nil && previewStatus.Refused == "" {
+		previewStatus.Refused = err.Error()
+	}
+	restarts := preview.PredictRestarts(appliedConfig, operConfig)
+	previewStatus.AgentRestart = restarts.AgentRestart
+	previewStatus.ControllerRestart = restarts.ControllerRestart
+	previewStatus.ImageChange = restarts.ImageChange
+	return previewStatus, nil
+}
+
+// DeletedObjects returns the names of the related objects of the ClusterOperator which are not
+// in objs anymore, and are deleted when objs are applied on OpenShift, except the Namespaces.
+func DeletedObjects(ctx context.Context, reader client.Reader, mapper meta.RESTMapper, objs []*uns.Unstructured) ([]string, error) {
+	rendered := map[configv1.ObjectReference]bool{}
+	for _, obj := range objs {
+		if restMapping, err := mapper.RESTMapping(obj.GroupVersionKind().GroupKind()); err == nil {
+			rendered[configv1.ObjectReference{
+				Group:     obj.GroupVersionKind().Group,
+				Resource:  restMapping.Resource.Resource,
+				Name:      obj.GetName(),
+				Namespace: obj.GetNamespace(),
+			}] = true
+		}
+	}
+	co := &configv1.ClusterOperator{}
+	err := reader.Get(ctx, client.ObjectKey{Name: operatortypes.AntreaClusterOperatorName}, co)
+	if err != nil && !apierrors.IsNotFound(err) {
+		return nil, fmt.Errorf("failed to get ClusterOperator %s: %v", operatortypes.AntreaClusterOperatorName, err)
+	}
+	var deleted []string
+	for _, ref := range co.Status.RelatedObjects {
+		if rendered[ref] {
+			continue
+		}
+		gvk, err := mapper.KindFor(schema.GroupVersionResource{Group: ref.Group, Resource: ref.Resource})
+		if err != nil || (gvk.Kind == "Namespace" && gvk.Group == "") {
+			continue
+		}
+		deleted = append(deleted, preview.ObjectName(gvk.Kind, ref.Namespace, ref.Name))
+	}
+	return deleted, nil
+}
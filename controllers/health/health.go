@@ -0,0 +1,159 @@
+/* Copyright © 2026 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package health
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+
+	operatorv1 "github.com/vmware/antrea-operator-for-kubernetes/api/v1"
+	"github.com/vmware/antrea-operator-for-kubernetes/controllers/metrics"
+	operatortypes "github.com/vmware/antrea-operator-for-kubernetes/controllers/types"
+)
+
+const (
+	cacheSyncTimeout = time.Second
+
+	leaderStateLeader  = "leader"
+	leaderStateStandby = "standby"
+)
+
+var log = logf.Log.WithName("health")
+
+// Checker tracks the operator state exposed by the /healthz and /readyz endpoints.
+type Checker struct {
+	mutex sync.Mutex
+
+	cache  cache.Cache
+	reader client.Reader
+
+	reconcileDeadline time.Duration
+	// servesWebhooks tells that the webhooks of the antrea-install CR are served, which must be
+	// ready before the CR can be created.
+	servesWebhooks bool
+	started        bool
+	reconciles     map[string]time.Time
+
+	// elected is closed when this replica becomes the leader, or at once without leader election.
+	elected <-chan struct{}
+}
+
+// NewChecker creates a Checker and registers it with mgr, so that it learns when the manager, and
+// hence leader election, has started. servesWebhooks tells whether the manager serves the webhooks
+// of the antrea-install CR.
+func NewChecker(mgr manager.Manager, reconcileDeadline time.Duration, servesWebhooks bool) (*Checker, error) {
+	checker := &Checker{
+		cache:             mgr.GetCache(),
+		reader:            mgr.GetClient(),
+		reconcileDeadline: reconcileDeadline,
+		servesWebhooks:    servesWebhooks,
+		reconciles:        map[string]time.Time{},
+		elected:           mgr.Elected(),
+	}
+	if err := mgr.Add(checker); err != nil {
+		return nil, err
+	}
+	return checker, nil
+}
+
+// Start implements manager.Runnable. As the Checker does not need leader election, it is started
+// as soon as the manager starts and leader election, if enabled, is in progress. It publishes the
+// leader election state in the leader metric, and logs when this replica becomes the leader.
+func (c *Checker) Start(ctx context.Context) error {
+	c.mutex.Lock()
+	c.started = true
+	c.mutex.Unlock()
+	metrics.SetLeader(false)
+	select {
+	case <-c.elected:
+		log.Info("elected as leader")
+		metrics.SetLeader(true)
+	case <-ctx.Done():
+		return nil
+	}
+	<-ctx.Done()
+	return nil
+}
+
+// NeedLeaderElection implements manager.LeaderElectionRunnable.
+func (c *Checker) NeedLeaderElection() bool {
+	return false
+}
+
+// TrackReconcile records the start of a reconciliation of the named controller. The returned
+// function must be called when the reconciliation is done.
+func (c *Checker) TrackReconcile(controller string) func() {
+	if c == nil {
+		return func() {}
+	}
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.reconciles[controller] = time.Now()
+	return func() {
+		c.mutex.Lock()
+		defer c.mutex.Unlock()
+		delete(c.reconciles, controller)
+	}
+}
+
+// Healthz fails when a reconciliation has been running for longer than the reconcile deadline.
+func (c *Checker) Healthz(_ *http.Request) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	for controller, start := range c.reconciles {
+		if elapsed := time.Since(start); elapsed > c.reconcileDeadline {
+			return fmt.Errorf("reconciliation of controller %s has been running for %s, exceeding the deadline of %s", controller, elapsed.Round(time.Second), c.reconcileDeadline)
+		}
+	}
+	return nil
+}
+
+// LeaderState returns leader once this replica has been elected, or when leader election is
+// disabled, and standby otherwise.
+func (c *Checker) LeaderState() string {
+	select {
+	case <-c.elected:
+		return leaderStateLeader
+	default:
+		return leaderStateStandby
+	}
+}
+
+// Readyz fails until the leader election state is known, the informer caches are synced and the
+// antrea-install CR has been loaded. The state is known once the manager has started the leader
+// election: a standby replica is ready, as it serves the probes and the webhooks, and the state is
+// reported by the leader metric. The CR is not waited for when the webhooks are served, as the
+// API server calls them to create it.
+func (c *Checker) Readyz(req *http.Request) error {
+	c.mutex.Lock()
+	started := c.started
+	c.mutex.Unlock()
+	if !started {
+		return fmt.Errorf("leader election state is not known yet")
+	}
+
+	ctx, cancel := context.WithTimeout(req.Context(), cacheSyncTimeout)
+	defer cancel()
+	if !c.cache.WaitForCacheSync(ctx) {
+		return fmt.Errorf("informer caches are not synced")
+	}
+	if c.servesWebhooks {
+		return nil
+	}
+
+	antreaInstall := &operatorv1.AntreaInstall{}
+	if err := c.reader.Get(ctx, types.NamespacedName{Namespace: operatortypes.OperatorNameSpace, Name: operatortypes.OperatorConfigName}, antreaInstall); err != nil {
+		return fmt.Errorf("%s CR is not loaded: %v", operatortypes.OperatorConfigName, err)
+	}
+	return nil
+}
@@ -0,0 +1,130 @@
+/* Copyright © 2026 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package health
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	operatorv1 "github.com/vmware/antrea-operator-for-kubernetes/api/v1"
+	"github.com/vmware/antrea-operator-for-kubernetes/controllers/metrics"
+	operatortypes "github.com/vmware/antrea-operator-for-kubernetes/controllers/types"
+)
+
+// fakeCache only implements WaitForCacheSync.
+type fakeCache struct {
+	cache.Cache
+	synced bool
+}
+
+func (c *fakeCache) WaitForCacheSync(_ context.Context) bool {
+	return c.synced
+}
+
+func newTestChecker(g *WithT, elected <-chan struct{}, servesWebhooks bool, objs ...client.Object) (*Checker, *fakeCache) {
+	scheme := runtime.NewScheme()
+	g.Expect(operatorv1.AddToScheme(scheme)).Should(Succeed())
+	informerCache := &fakeCache{}
+	return &Checker{
+		cache:             informerCache,
+		reader:            fake.NewClientBuilder().WithScheme(scheme).WithObjects(objs...).Build(),
+		reconcileDeadline: time.Minute,
+		servesWebhooks:    servesWebhooks,
+		reconciles:        map[string]time.Time{},
+		elected:           elected,
+	}, informerCache
+}
+
+func TestStart(t *testing.T) {
+	g := NewGomegaWithT(t)
+	elected := make(chan struct{})
+	checker, _ := newTestChecker(g, elected, false)
+	metrics.SetLeader(true)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() {
+		done <- checker.Start(ctx)
+	}()
+	// A standby replica publishes its state as soon as it starts.
+	g.Eventually(func() float64 { return testutil.ToFloat64(metrics.Leader) }).Should(Equal(float64(0)))
+	g.Expect(checker.LeaderState()).Should(Equal(leaderStateStandby))
+
+	close(elected)
+	g.Eventually(func() float64 { return testutil.ToFloat64(metrics.Leader) }).Should(Equal(float64(1)))
+	g.Expect(checker.LeaderState()).Should(Equal(leaderStateLeader))
+
+	cancel()
+	g.Eventually(done).Should(Receive(BeNil()))
+}
+
+func TestReadyz(t *testing.T) {
+	g := NewGomegaWithT(t)
+	req := httptest.NewRequest("GET", "/readyz", nil)
+	antreaInstall := &operatorv1.AntreaInstall{ObjectMeta: metav1.ObjectMeta{Namespace: operatortypes.OperatorNameSpace, Name: operatortypes.OperatorConfigName}}
+
+	for _, tc := range []struct {
+		name           string
+		started        bool
+		synced         bool
+		servesWebhooks bool
+		antreaInstall  bool
+		expectedErr    string
+	}{
+		{name: "not started", synced: true, antreaInstall: true, expectedErr: "leader election state is not known yet"},
+		{name: "caches not synced", started: true, antreaInstall: true, expectedErr: "informer caches are not synced"},
+		{name: "CR not loaded", started: true, synced: true, expectedErr: "antrea-install CR is not loaded"},
+		{name: "CR not loaded with webhooks", started: true, synced: true, servesWebhooks: true},
+		{name: "ready", started: true, synced: true, antreaInstall: true},
+	} {
+		var objs []client.Object
+		if tc.antreaInstall {
+			objs = append(objs, antreaInstall.DeepCopy())
+		}
+		checker, informerCache := newTestChecker(g, make(chan struct{}), tc.servesWebhooks, objs...)
+		checker.started = tc.started
+		informerCache.synced = tc.synced
+		// The probe has no side effect on the leader metric.
+		metrics.SetLeader(true)
+		err := checker.Readyz(req)
+		g.Expect(testutil.ToFloat64(metrics.Leader)).Should(Equal(float64(1)), tc.name)
+		if tc.expectedErr != "" {
+			g.Expect(err).Should(MatchError(ContainSubstring(tc.expectedErr)), tc.name)
+			continue
+		}
+		g.Expect(err).ShouldNot(HaveOccurred(), tc.name)
+	}
+}
+
+func TestTrackReconcile(t *testing.T) {
+	g := NewGomegaWithT(t)
+	checker, _ := newTestChecker(g, make(chan struct{}), false)
+	req := httptest.NewRequest("GET", "/healthz", nil)
+	g.Expect(checker.Healthz(req)).Should(Succeed())
+
+	done := checker.TrackReconcile("antreainstall")
+	g.Expect(checker.Healthz(req)).Should(Succeed())
+	// The reconciliation exceeds the deadline.
+	checker.reconciles["antreainstall"] = time.Now().Add(-2 * checker.reconcileDeadline)
+	g.Expect(checker.Healthz(req)).Should(MatchError(ContainSubstring("reconciliation of controller antreainstall has been running for 2m0s")))
+	// Another controller does not clear the stale reconciliation.
+	checker.TrackReconcile("pod")()
+	g.Expect(checker.Healthz(req)).Should(HaveOccurred())
+	done()
+	g.Expect(checker.Healthz(req)).Should(Succeed())
+
+	// Reconcilers without Checker do not track their reconciliations.
+	var nilChecker *Checker
+	nilChecker.TrackReconcile("antreainstall")()
+}
@@ -0,0 +1,85 @@
+/* Copyright © 2026 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package controllers
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	uns "k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	operatorv1 "github.com/vmware/antrea-operator-for-kubernetes/api/v1"
+	configutil "github.com/vmware/antrea-operator-for-kubernetes/controllers/config"
+	"github.com/vmware/antrea-operator-for-kubernetes/controllers/statusmanager"
+	operatortypes "github.com/vmware/antrea-operator-for-kubernetes/controllers/types"
+)
+
+// deferDisruptiveChange tells whether a change of a running installation which restarts
+// antrea-agent or changes the Antrea image must wait for the next maintenance window, and
+// returns the start of that window and why the change is disruptive.
+func deferDisruptiveChange(appliedConfig, operConfig *operatorv1.AntreaInstall, agentNeedChange, imageChange bool) (bool, time.Time, string) {
+	if appliedConfig == nil || len(operConfig.Spec.MaintenanceWindows) == 0 || (!agentNeedChange && !imageChange) {
+		return false, time.Time{}, ""
+	}
+	if _, ok := operConfig.Annotations[operatortypes.ApplyNowAnnotation]; ok {
+		log.Info("applying disruptive change now", "annotation", operatortypes.ApplyNowAnnotation)
+		return false, time.Time{}, ""
+	}
+	open, next := configutil.MaintenanceWindowOpen(operConfig.Spec.MaintenanceWindows, time.Now())
+	if open {
+		return false, time.Time{}, ""
+	}
+	reason := fmt.Sprintf("The %s Pods would be restarted to pick up the configuration changes", operatortypes.AntreaAgentDaemonSetName)
+	if imageChange {
+		reason = fmt.Sprintf("The %s and %s Pods would be rolled out with the new image", operatortypes.AntreaAgentDaemonSetName, operatortypes.AntreaControllerDeploymentName)
+	}
+	return true, next, reason
+}
+
+// isDisruptiveObject tells whether applying obj restarts the Antrea Pods, directly or through
+// the Pod deletions which pick up the Antrea configurations.
+func isDisruptiveObject(obj *uns.Unstructured) bool {
+	if obj.GetNamespace() != operatortypes.AntreaNamespace {
+		return false
+	}
+	switch obj.GetKind() {
+	case "DaemonSet":
+		return obj.GetName() == operatortypes.AntreaAgentDaemonSetName
+	case "Deployment":
+		return obj.GetName() == operatortypes.AntreaControllerDeploymentName
+	case "ConfigMap":
+		return strings.HasPrefix(obj.GetName(), operatortypes.AntreaConfigMapName)
+	}
+	return false
+}
+
+// scheduleChange publishes the disruptive change held until the maintenance window starting at
+// next in AntreaInstall.Status.
+func scheduleChange(r *AntreaInstallReconciler, operConfig *operatorv1.AntreaInstall, next time.Time, reason string) error {
+	scheduledChange := &operatorv1.ScheduledChangeStatus{
+		Revision:      configutil.ConfigRevision(operConfig),
+		ScheduledTime: metav1.NewTime(next),
+		Reason:        reason,
+	}
+	if err := r.Status.SetScheduledChange(scheduledChange); err != nil {
+		return err
+	}
+	r.Status.Normal(statusmanager.EventReasonChangeScheduled, fmt.Sprintf("Revision %s is deferred to the maintenance window starting at %s, set the %s annotation to apply it now",
+		scheduledChange.Revision, next.Format(time.RFC3339), operatortypes.ApplyNowAnnotation))
+	return nil
+}
+
+// completeScheduledChange clears the scheduled change once it is applied, and removes the
+// apply-now annotation, so that it does not apply a later change outside the maintenance windows.
+func completeScheduledChange(r *AntreaInstallReconciler, operConfig *operatorv1.AntreaInstall) error {
+	if err := removeAnnotation(r, operConfig, operatortypes.ApplyNowAnnotation); err != nil {
+		return err
+	}
+	if operConfig.Status.ScheduledChange == nil {
+		return nil
+	}
+	return r.Status.SetScheduledChange(nil)
+}
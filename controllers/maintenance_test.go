@@ -0,0 +1,57 @@
+/* Copyright © 2026 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package controllers
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/api/meta"
+	uns "k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/vmware/antrea-operator-for-kubernetes/controllers/sharedinfo"
+	operatortypes "github.com/vmware/antrea-operator-for-kubernetes/controllers/types"
+)
+
+func newAntreaObject(kind, name, version string) *uns.Unstructured {
+	obj := &uns.Unstructured{}
+	obj.SetAPIVersion("apps/v1")
+	obj.SetKind(kind)
+	obj.SetNamespace(operatortypes.AntreaNamespace)
+	obj.SetName(name)
+	obj.SetAnnotations(map[string]string{"version": version})
+	return obj
+}
+
+// TestUpdateSharedInfoDeferred keeps the antrea-agent DaemonSet and antrea-controller Deployment
+// of SharedInfo while a change is deferred, so that the pod controller does not apply them before
+// the maintenance window.
+func TestUpdateSharedInfoDeferred(t *testing.T) {
+	g := NewGomegaWithT(t)
+	r, _, _ := newTestReconciler(g)
+	r.Mapper = meta.NewDefaultRESTMapper(nil)
+	r.SharedInfo = sharedinfo.New()
+	objs := func(version string) []*uns.Unstructured {
+		return []*uns.Unstructured{
+			newAntreaObject("DaemonSet", operatortypes.AntreaAgentDaemonSetName, version),
+			newAntreaObject("Deployment", operatortypes.AntreaControllerDeploymentName, version),
+		}
+	}
+
+	g.Expect(updateStatusManagerAndSharedInfo(r, objs("v1"), nil, false)).Should(Succeed())
+	g.Expect(r.SharedInfo.AntreaAgentDaemonSetSpec.GetAnnotations()["version"]).Should(Equal("v1"))
+	g.Expect(r.SharedInfo.AntreaControllerDeploymentSpec.GetAnnotations()["version"]).Should(Equal("v1"))
+
+	g.Expect(updateStatusManagerAndSharedInfo(r, objs("v2"), nil, true)).Should(Succeed())
+	g.Expect(r.SharedInfo.AntreaAgentDaemonSetSpec.GetAnnotations()["version"]).Should(Equal("v1"))
+	g.Expect(r.SharedInfo.AntreaControllerDeploymentSpec.GetAnnotations()["version"]).Should(Equal("v1"))
+
+	// The deferred change is applied in the maintenance window.
+	g.Expect(updateStatusManagerAndSharedInfo(r, objs("v2"), nil, false)).Should(Succeed())
+	g.Expect(r.SharedInfo.AntreaAgentDaemonSetSpec.GetAnnotations()["version"]).Should(Equal("v2"))
+	g.Expect(r.SharedInfo.AntreaControllerDeploymentSpec.GetAnnotations()["version"]).Should(Equal("v2"))
+
+	// The objects are still required when the change is deferred.
+	g.Expect(updateStatusManagerAndSharedInfo(r, objs("v3")[:1], nil, true)).Should(MatchError(ContainSubstring("is missing")))
+}
@@ -0,0 +1,229 @@
+/* Copyright © 2026 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+// Package manifestsource loads the Antrea manifest templates of spec.manifestSource, from a
+// ConfigMap, a mounted directory or an OCI artifact, and verifies their digest.
+package manifestsource
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	operatorv1 "github.com/vmware/antrea-operator-for-kubernetes/api/v1"
+)
+
+const digestPrefix = "sha256:"
+
+// CacheDir is the directory in which the verified templates are written, in a subdirectory
+// named after their digest. It is set from the manifestCacheDir of the operator configuration.
+var CacheDir = filepath.Join(os.TempDir(), "antrea-manifests")
+
+// Files maps the slash-separated paths of the templates, relative to the root of the source, to
+// their content.
+type Files map[string][]byte
+
+// isTemplate tells whether the file at name is rendered, as render.RenderDir only reads the YAML
+// and JSON files.
+func isTemplate(name string) bool {
+	switch path.Ext(name) {
+	case ".yml", ".yaml", ".json":
+		return true
+	}
+	return false
+}
+
+// Digest returns the digest of the templates: the SHA-256 of the sha256sum output of the files,
+// sorted by path. It can be computed in the root directory of the templates with:
+//
+//	find -L . -type f \( -name '*.yml' -o -name '*.yaml' -o -name '*.json' \) -not -path '*/.*' | sed 's|^\./||' | LC_ALL=C sort | xargs sha256sum | sha256sum
+func (f Files) Digest() string {
+	names := make([]string, 0, len(f))
+	for name := range f {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	hash := sha256.New()
+	for _, name := range names {
+		fileHash := sha256.Sum256(f[name])
+		fmt.Fprintf(hash, "%s  %s\n", hex.EncodeToString(fileHash[:]), name)
+	}
+	return digestPrefix + hex.EncodeToString(hash.Sum(nil))
+}
+
+// add adds the file at name when it is a template, and not hidden. name must be a relative path
+// within the root of the source.
+func (f Files) add(name string, data []byte) error {
+	name = path.Clean(name)
+	if path.IsAbs(name) || name == ".." || strings.HasPrefix(name, "../") {
+		return fmt.Errorf("invalid template path %q", name)
+	}
+	for _, element := range strings.Split(name, "/") {
+		if strings.HasPrefix(element, ".") {
+			return nil
+		}
+	}
+	if isTemplate(name) {
+		f[name] = data
+	}
+	return nil
+}
+
+// ReadDir reads the templates of dir, recursively. The hidden files and directories are skipped,
+// such as the ..data directory of the ConfigMap volumes, and the symbolic links are followed.
+func ReadDir(dir string) (Files, error) {
+	files := Files{}
+	err := filepath.WalkDir(dir, func(p string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if p != dir && strings.HasPrefix(entry.Name(), ".") {
+			if entry.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		info, err := os.Stat(p)
+		if err != nil || info.IsDir() {
+			return err
+		}
+		rel, err := filepath.Rel(dir, p)
+		if err != nil {
+			return err
+		}
+		data, err := os.ReadFile(p)
+		if err != nil {
+			return err
+		}
+		return files.add(filepath.ToSlash(rel), data)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return files, nil
+}
+
+func readConfigMap(ctx context.Context, reader client.Reader, namespace, name string) (Files, error) {
+	configMap := &corev1.ConfigMap{}
+	if err := reader.Get(ctx, client.ObjectKey{Namespace: namespace, Name: name}, configMap); err != nil {
+		return nil, fmt.Errorf("failed to get ConfigMap %s/%s: %v", namespace, name, err)
+	}
+	files := Files{}
+	for name, data := range configMap.Data {
+		if err := files.add(name, []byte(data)); err != nil {
+			return nil, err
+		}
+	}
+	for name, data := range configMap.BinaryData {
+		if err := files.add(name, data); err != nil {
+			return nil, err
+		}
+	}
+	return files, nil
+}
+
+// Validate checks that exactly one source is set.
+func Validate(source *operatorv1.ManifestSource) error {
+	sources := 0
+	if source.ConfigMap != "" {
+		sources++
+	}
+	if source.Directory != "" {
+		sources++
+	}
+	if source.OCI != nil {
+		sources++
+	}
+	if sources != 1 {
+		return fmt.Errorf("exactly one of configMap, directory and oci must be set in manifestSource")
+	}
+	if !validDigest(source.Digest) {
+		return fmt.Errorf("invalid manifestSource digest %q, expected sha256: followed by 64 hexadecimal digits", source.Digest)
+	}
+	return nil
+}
+
+// validDigest tells whether digest is "sha256:" followed by 64 lowercase hexadecimal digits.
+func validDigest(digest string) bool {
+	encoded := strings.TrimPrefix(digest, digestPrefix)
+	if encoded == digest || len(encoded) != sha256.Size*2 || strings.ToLower(encoded) != encoded {
+		return false
+	}
+	_, err := hex.DecodeString(encoded)
+	return err == nil
+}
+
+// Resolve loads the templates of source, ConfigMaps being read in namespace, verifies their
+// digest, and returns the directory of CacheDir they are written in. An OCI artifact is only
+// pulled when its templates are not cached yet.
+func Resolve(ctx context.Context, reader client.Reader, namespace string, source *operatorv1.ManifestSource) (string, error) {
+	if err := Validate(source); err != nil {
+		return "", err
+	}
+	dir := filepath.Join(CacheDir, strings.TrimPrefix(source.Digest, digestPrefix))
+	var files Files
+	var err error
+	switch {
+	case source.ConfigMap != "":
+		files, err = readConfigMap(ctx, reader, namespace, source.ConfigMap)
+	case source.Directory != "":
+		files, err = ReadDir(source.Directory)
+	default:
+		if cached, err := ReadDir(dir); err == nil && len(cached) > 0 && cached.Digest() == source.Digest {
+			return dir, nil
+		}
+		files, err = pullOCI(ctx, source.OCI)
+	}
+	if err != nil {
+		return "", err
+	}
+	if len(files) == 0 {
+		return "", fmt.Errorf("the manifest source holds no template")
+	}
+	if digest := files.Digest(); digest != source.Digest {
+		return "", fmt.Errorf("the digest of the templates is %s, expected %s", digest, source.Digest)
+	}
+	if err := files.write(dir); err != nil {
+		return "", fmt.Errorf("failed to write templates: %v", err)
+	}
+	return dir, nil
+}
+
+// write writes the verified templates in dir, unless it already holds them, through a temporary
+// directory so that dir never holds a part of them.
+func (f Files) write(dir string) error {
+	if cached, err := ReadDir(dir); err == nil && cached.Digest() == f.Digest() {
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(dir), 0o755); err != nil {
+		return err
+	}
+	tmpDir, err := os.MkdirTemp(filepath.Dir(dir), ".tmp-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(tmpDir)
+	for name, data := range f {
+		p := filepath.Join(tmpDir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+			return err
+		}
+		if err := os.WriteFile(p, data, 0o644); err != nil {
+			return err
+		}
+	}
+	if err := os.RemoveAll(dir); err != nil {
+		return err
+	}
+	return os.Rename(tmpDir, dir)
+}
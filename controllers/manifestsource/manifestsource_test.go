@@ -0,0 +1,217 @@
+/* Copyright © 2026 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package manifestsource
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	operatorv1 "github.com/vmware/antrea-operator-for-kubernetes/api/v1"
+)
+
+const testNamespace = "antrea-operator"
+
+var testFiles = Files{
+	"antrea.yml":          []byte("apiVersion: v1\nkind: Namespace\nmetadata:\n  name: kube-system\n"),
+	"crds/crd.yaml":       []byte("apiVersion: v1\nkind: ServiceAccount\nmetadata:\n  name: antrea-agent\n"),
+	"extra/settings.json": []byte("{}"),
+}
+
+// testDigest is the digest of testFiles, as computed by the shell pipeline of Digest.
+const testDigest = "sha256:0d0613b665af34257a95f85372f0c13af2c68d2fa877278e8f1ed878efc7b3d1"
+
+func TestDigest(t *testing.T) {
+	g := NewGomegaWithT(t)
+	g.Expect(validDigest(testFiles.Digest())).Should(BeTrue())
+	g.Expect(testFiles.Digest()).Should(Equal(testDigest))
+
+	// The digest covers the paths and the contents of the files.
+	renamed := Files{"antrea.yaml": testFiles["antrea.yml"], "crds/crd.yaml": testFiles["crds/crd.yaml"], "extra/settings.json": testFiles["extra/settings.json"]}
+	g.Expect(renamed.Digest()).ShouldNot(Equal(testDigest))
+	changed := Files{"antrea.yml": []byte("{}"), "crds/crd.yaml": testFiles["crds/crd.yaml"], "extra/settings.json": testFiles["extra/settings.json"]}
+	g.Expect(changed.Digest()).ShouldNot(Equal(testDigest))
+}
+
+func TestAdd(t *testing.T) {
+	g := NewGomegaWithT(t)
+	for _, tc := range []struct {
+		name         string
+		path         string
+		expectedPath string
+		expectedErr  string
+	}{
+		{name: "template", path: "antrea.yml", expectedPath: "antrea.yml"},
+		{name: "nested template", path: "./crds//crd.yaml", expectedPath: "crds/crd.yaml"},
+		{name: "json template", path: "settings.json", expectedPath: "settings.json"},
+		{name: "not a template", path: "README.md"},
+		{name: "hidden file", path: ".antrea.yml"},
+		{name: "hidden directory", path: "..data/antrea.yml"},
+		{name: "parent directory", path: "../antrea.yml", expectedErr: `invalid template path "../antrea.yml"`},
+		{name: "escaping path", path: "crds/../../antrea.yml", expectedErr: `invalid template path "../antrea.yml"`},
+		{name: "absolute path", path: "/etc/antrea.yml", expectedErr: `invalid template path "/etc/antrea.yml"`},
+	} {
+		files := Files{}
+		err := files.add(tc.path, []byte("{}"))
+		if tc.expectedErr != "" {
+			g.Expect(err).Should(MatchError(tc.expectedErr), tc.name)
+			continue
+		}
+		g.Expect(err).ShouldNot(HaveOccurred(), tc.name)
+		if tc.expectedPath == "" {
+			g.Expect(files).Should(BeEmpty(), tc.name)
+			continue
+		}
+		g.Expect(files).Should(HaveKey(tc.expectedPath), tc.name)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	g := NewGomegaWithT(t)
+	for _, tc := range []struct {
+		name        string
+		source      operatorv1.ManifestSource
+		expectedErr string
+	}{
+		{name: "configMap", source: operatorv1.ManifestSource{ConfigMap: "antrea-manifests", Digest: testDigest}},
+		{name: "directory", source: operatorv1.ManifestSource{Directory: "/manifests", Digest: testDigest}},
+		{name: "oci", source: operatorv1.ManifestSource{OCI: &operatorv1.OCIManifestSource{Reference: "registry.local/antrea:v1"}, Digest: testDigest}},
+		{name: "no source", source: operatorv1.ManifestSource{Digest: testDigest}, expectedErr: "exactly one of"},
+		{
+			name:        "two sources",
+			source:      operatorv1.ManifestSource{ConfigMap: "antrea-manifests", Directory: "/manifests", Digest: testDigest},
+			expectedErr: "exactly one of",
+		},
+		{name: "missing digest", source: operatorv1.ManifestSource{ConfigMap: "antrea-manifests"}, expectedErr: "invalid manifestSource digest"},
+		{
+			name:        "unsupported algorithm",
+			source:      operatorv1.ManifestSource{ConfigMap: "antrea-manifests", Digest: "sha512:" + strings.Repeat("0", 64)},
+			expectedErr: "invalid manifestSource digest",
+		},
+		{
+			name:        "short digest",
+			source:      operatorv1.ManifestSource{ConfigMap: "antrea-manifests", Digest: "sha256:" + strings.Repeat("0", 63)},
+			expectedErr: "invalid manifestSource digest",
+		},
+		{
+			name:        "uppercase digest",
+			source:      operatorv1.ManifestSource{ConfigMap: "antrea-manifests", Digest: "sha256:" + strings.Repeat("A", 64)},
+			expectedErr: "invalid manifestSource digest",
+		},
+		{
+			name:        "not hexadecimal",
+			source:      operatorv1.ManifestSource{ConfigMap: "antrea-manifests", Digest: "sha256:" + strings.Repeat("g", 64)},
+			expectedErr: "invalid manifestSource digest",
+		},
+	} {
+		err := Validate(&tc.source)
+		if tc.expectedErr != "" {
+			g.Expect(err).Should(MatchError(ContainSubstring(tc.expectedErr)), tc.name)
+			continue
+		}
+		g.Expect(err).ShouldNot(HaveOccurred(), tc.name)
+	}
+}
+
+// writeFiles writes files in dir, along with the hidden files a ConfigMap volume holds.
+func writeFiles(g *WithT, dir string, files Files) {
+	for name, data := range files {
+		p := filepath.Join(dir, filepath.FromSlash(name))
+		g.Expect(os.MkdirAll(filepath.Dir(p), 0o755)).Should(Succeed())
+		g.Expect(os.WriteFile(p, data, 0o644)).Should(Succeed())
+	}
+	g.Expect(os.MkdirAll(filepath.Join(dir, "..data"), 0o755)).Should(Succeed())
+	g.Expect(os.WriteFile(filepath.Join(dir, "..data", "antrea.yml"), []byte("{}"), 0o644)).Should(Succeed())
+	g.Expect(os.WriteFile(filepath.Join(dir, "README.md"), []byte("not a template"), 0o644)).Should(Succeed())
+}
+
+func TestResolve(t *testing.T) {
+	g := NewGomegaWithT(t)
+	CacheDir = t.TempDir()
+	dir := t.TempDir()
+	writeFiles(g, dir, testFiles)
+
+	scheme := runtime.NewScheme()
+	g.Expect(clientgoscheme.AddToScheme(scheme)).Should(Succeed())
+	configMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Namespace: testNamespace, Name: "antrea-manifests"},
+		Data:       map[string]string{"antrea.yml": string(testFiles["antrea.yml"]), "README.md": "not a template"},
+		BinaryData: map[string][]byte{"settings.json": []byte("{}")},
+	}
+	invalidConfigMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Namespace: testNamespace, Name: "invalid-manifests"},
+		Data:       map[string]string{"../antrea.yml": "{}"},
+	}
+	emptyConfigMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Namespace: testNamespace, Name: "empty-manifests"},
+		Data:       map[string]string{"README.md": "not a template"},
+	}
+	reader := fake.NewClientBuilder().WithScheme(scheme).WithObjects(configMap, invalidConfigMap, emptyConfigMap).Build()
+	configMapDigest := Files{"antrea.yml": testFiles["antrea.yml"], "settings.json": []byte("{}")}.Digest()
+
+	for _, tc := range []struct {
+		name          string
+		source        operatorv1.ManifestSource
+		expectedFiles Files
+		expectedErr   string
+	}{
+		{name: "directory", source: operatorv1.ManifestSource{Directory: dir, Digest: testDigest}, expectedFiles: testFiles},
+		{
+			name:          "configMap",
+			source:        operatorv1.ManifestSource{ConfigMap: configMap.Name, Digest: configMapDigest},
+			expectedFiles: Files{"antrea.yml": testFiles["antrea.yml"], "settings.json": []byte("{}")},
+		},
+		{
+			name:        "digest mismatch",
+			source:      operatorv1.ManifestSource{Directory: dir, Digest: configMapDigest},
+			expectedErr: "the digest of the templates is " + testDigest,
+		},
+		{name: "invalid source", source: operatorv1.ManifestSource{Directory: dir}, expectedErr: "invalid manifestSource digest"},
+		{
+			name:        "missing configMap",
+			source:      operatorv1.ManifestSource{ConfigMap: "missing", Digest: testDigest},
+			expectedErr: "failed to get ConfigMap antrea-operator/missing",
+		},
+		{
+			name:        "path traversal in configMap",
+			source:      operatorv1.ManifestSource{ConfigMap: invalidConfigMap.Name, Digest: testDigest},
+			expectedErr: `invalid template path "../antrea.yml"`,
+		},
+		{
+			name:        "no template",
+			source:      operatorv1.ManifestSource{ConfigMap: emptyConfigMap.Name, Digest: testDigest},
+			expectedErr: "the manifest source holds no template",
+		},
+	} {
+		resolved, err := Resolve(context.TODO(), reader, testNamespace, &tc.source)
+		if tc.expectedErr != "" {
+			g.Expect(err).Should(MatchError(ContainSubstring(tc.expectedErr)), tc.name)
+			continue
+		}
+		g.Expect(err).ShouldNot(HaveOccurred(), tc.name)
+		g.Expect(resolved).Should(Equal(filepath.Join(CacheDir, strings.TrimPrefix(tc.source.Digest, digestPrefix))), tc.name)
+		files, err := ReadDir(resolved)
+		g.Expect(err).ShouldNot(HaveOccurred(), tc.name)
+		g.Expect(files).Should(Equal(tc.expectedFiles), tc.name)
+	}
+
+	// A directory holding templates of another digest is replaced.
+	stale := filepath.Join(CacheDir, strings.TrimPrefix(testDigest, digestPrefix))
+	g.Expect(os.RemoveAll(stale)).Should(Succeed())
+	writeFiles(g, stale, Files{"stale.yml": []byte("{}")})
+	resolved, err := Resolve(context.TODO(), reader, testNamespace, &operatorv1.ManifestSource{Directory: dir, Digest: testDigest})
+	g.Expect(err).ShouldNot(HaveOccurred())
+	files, err := ReadDir(resolved)
+	g.Expect(err).ShouldNot(HaveOccurred())
+	g.Expect(files).Should(Equal(testFiles))
+}
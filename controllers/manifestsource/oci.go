@@ -0,0 +1,188 @@
+/* Copyright © 2026 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package manifestsource
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	operatorv1 "github.com/vmware/antrea-operator-for-kubernetes/api/v1"
+)
+
+const (
+	mediaTypeOCIManifest    = "application/vnd.oci.image.manifest.v1+json"
+	mediaTypeDockerManifest = "application/vnd.docker.distribution.manifest.v2+json"
+	// titleAnnotation names the file of a layer, as pushed by oras.
+	titleAnnotation = "org.opencontainers.image.title"
+
+	// maxBlobSize bounds the size of the manifest and of each layer of an artifact.
+	maxBlobSize = 64 << 20
+	pullTimeout = 2 * time.Minute
+)
+
+var httpClient = &http.Client{Timeout: pullTimeout}
+
+type descriptor struct {
+	MediaType   string            `json:"mediaType"`
+	Digest      string            `json:"digest"`
+	Size        int64             `json:"size"`
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+type imageManifest struct {
+	MediaType string       `json:"mediaType"`
+	Layers    []descriptor `json:"layers"`
+}
+
+// parseReference splits an artifact reference into the registry host, the repository, and the
+// tag or digest, latest by default.
+func parseReference(reference string) (string, string, string, error) {
+	host, repository, ok := strings.Cut(reference, "/")
+	if !ok || host == "" || repository == "" {
+		return "", "", "", fmt.Errorf("invalid OCI reference %q, expected registry/repository[:tag|@digest]", reference)
+	}
+	if repository, digest, ok := strings.Cut(repository, "@"); ok {
+		if !validDigest(digest) {
+			return "", "", "", fmt.Errorf("invalid digest in OCI reference %q", reference)
+		}
+		return host, repository, digest, nil
+	}
+	tag := "latest"
+	if i := strings.LastIndex(repository, ":"); i >= 0 {
+		repository, tag = repository[:i], repository[i+1:]
+	}
+	return host, repository, tag, nil
+}
+
+// pullOCI pulls the templates of an OCI artifact. Each layer is either a tar archive, possibly
+// compressed with gzip, or a single file named by its org.opencontainers.image.title annotation,
+// as pushed by "oras push". The manifest, when pulled by digest, and the layers are verified
+// against their digests.
+func pullOCI(ctx context.Context, source *operatorv1.OCIManifestSource) (Files, error) {
+	host, repository, ref, err := parseReference(source.Reference)
+	if err != nil {
+		return nil, err
+	}
+	scheme := "https"
+	if source.PlainHTTP {
+		scheme = "http"
+	}
+	baseURL := fmt.Sprintf("%s://%s/v2/%s", scheme, host, repository)
+
+	expectedDigest := ""
+	if strings.HasPrefix(ref, digestPrefix) {
+		expectedDigest = ref
+	}
+	data, err := fetch(ctx, baseURL+"/manifests/"+ref, expectedDigest, mediaTypeOCIManifest+", "+mediaTypeDockerManifest)
+	if err != nil {
+		return nil, err
+	}
+	manifest := &imageManifest{}
+	if err := json.Unmarshal(data, manifest); err != nil {
+		return nil, fmt.Errorf("failed to parse manifest of %s: %v", source.Reference, err)
+	}
+
+	files := Files{}
+	for _, layer := range manifest.Layers {
+		if !validDigest(layer.Digest) {
+			return nil, fmt.Errorf("unsupported layer digest %q in %s", layer.Digest, source.Reference)
+		}
+		if layer.Size > maxBlobSize {
+			return nil, fmt.Errorf("layer %s of %s is larger than %d bytes", layer.Digest, source.Reference, maxBlobSize)
+		}
+		blob, err := fetch(ctx, baseURL+"/blobs/"+layer.Digest, layer.Digest, "")
+		if err != nil {
+			return nil, err
+		}
+		switch {
+		case strings.HasSuffix(layer.MediaType, ".tar+gzip") || strings.HasSuffix(layer.MediaType, ".tar.gzip"):
+			gzipReader, err := gzip.NewReader(bytes.NewReader(blob))
+			if err != nil {
+				return nil, fmt.Errorf("failed to read layer %s: %v", layer.Digest, err)
+			}
+			err = files.addTar(gzipReader)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read layer %s: %v", layer.Digest, err)
+			}
+		case strings.HasSuffix(layer.MediaType, ".tar"):
+			if err := files.addTar(bytes.NewReader(blob)); err != nil {
+				return nil, fmt.Errorf("failed to read layer %s: %v", layer.Digest, err)
+			}
+		case layer.Annotations[titleAnnotation] != "":
+			if err := files.add(layer.Annotations[titleAnnotation], blob); err != nil {
+				return nil, err
+			}
+		default:
+			return nil, fmt.Errorf("layer %s of %s is neither a tar archive nor a titled file", layer.Digest, source.Reference)
+		}
+	}
+	return files, nil
+}
+
+// addTar adds the templates of a tar archive.
+func (f Files) addTar(reader io.Reader) error {
+	tarReader := tar.NewReader(reader)
+	for {
+		header, err := tarReader.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+		data, err := io.ReadAll(io.LimitReader(tarReader, maxBlobSize))
+		if err != nil {
+			return err
+		}
+		if err := f.add(header.Name, data); err != nil {
+			return err
+		}
+	}
+}
+
+// fetch gets a manifest or a blob from the registry, and verifies its digest when it is known.
+func fetch(ctx context.Context, url, expectedDigest, accept string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	if accept != "" {
+		req.Header.Set("Accept", accept)
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get %s: %v", url, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to get %s: %s", url, resp.Status)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxBlobSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %v", url, err)
+	}
+	if len(data) > maxBlobSize {
+		return nil, fmt.Errorf("%s is larger than %d bytes", url, maxBlobSize)
+	}
+	if expectedDigest != "" {
+		hash := sha256.Sum256(data)
+		if digest := digestPrefix + hex.EncodeToString(hash[:]); digest != expectedDigest {
+			return nil, fmt.Errorf("the digest of %s is %s, expected %s", url, digest, expectedDigest)
+		}
+	}
+	return data, nil
+}
@@ -0,0 +1,177 @@
+/* Copyright © 2026 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package manifestsource
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	operatorv1 "github.com/vmware/antrea-operator-for-kubernetes/api/v1"
+)
+
+// fakeRegistry serves the manifests and the blobs of the OCI distribution API from memory, and
+// counts the requests.
+type fakeRegistry struct {
+	mutex    sync.Mutex
+	objects  map[string][]byte
+	requests int
+}
+
+func (r *fakeRegistry) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.requests++
+	data, ok := r.objects[req.URL.Path]
+	if !ok {
+		http.NotFound(w, req)
+		return
+	}
+	w.Write(data)
+}
+
+func sha256Digest(data []byte) string {
+	hash := sha256.Sum256(data)
+	return digestPrefix + hex.EncodeToString(hash[:])
+}
+
+// addBlob serves data as a blob of repository, and returns its descriptor.
+func (r *fakeRegistry) addBlob(repository, mediaType string, data []byte) descriptor {
+	digest := sha256Digest(data)
+	r.objects["/v2/"+repository+"/blobs/"+digest] = data
+	return descriptor{MediaType: mediaType, Digest: digest, Size: int64(len(data))}
+}
+
+// addManifest serves a manifest of layers in repository under tag and its digest, and returns the
+// digest.
+func (r *fakeRegistry) addManifest(g *WithT, repository, tag string, layers ...descriptor) string {
+	data, err := json.Marshal(imageManifest{MediaType: mediaTypeOCIManifest, Layers: layers})
+	g.Expect(err).ShouldNot(HaveOccurred())
+	digest := sha256Digest(data)
+	r.objects["/v2/"+repository+"/manifests/"+tag] = data
+	r.objects["/v2/"+repository+"/manifests/"+digest] = data
+	return digest
+}
+
+func tarArchive(g *WithT, files map[string]string) []byte {
+	var buf bytes.Buffer
+	writer := tar.NewWriter(&buf)
+	g.Expect(writer.WriteHeader(&tar.Header{Name: "crds/", Typeflag: tar.TypeDir, Mode: 0o755})).Should(Succeed())
+	for name, data := range files {
+		g.Expect(writer.WriteHeader(&tar.Header{Name: name, Typeflag: tar.TypeReg, Mode: 0o644, Size: int64(len(data))})).Should(Succeed())
+		_, err := writer.Write([]byte(data))
+		g.Expect(err).ShouldNot(HaveOccurred())
+	}
+	g.Expect(writer.Close()).Should(Succeed())
+	return buf.Bytes()
+}
+
+func gzipArchive(g *WithT, data []byte) []byte {
+	var buf bytes.Buffer
+	writer := gzip.NewWriter(&buf)
+	_, err := writer.Write(data)
+	g.Expect(err).ShouldNot(HaveOccurred())
+	g.Expect(writer.Close()).Should(Succeed())
+	return buf.Bytes()
+}
+
+func TestPullOCI(t *testing.T) {
+	g := NewGomegaWithT(t)
+	registry := &fakeRegistry{objects: map[string][]byte{}}
+	server := httptest.NewServer(registry)
+	defer server.Close()
+	host := strings.TrimPrefix(server.URL, "http://")
+
+	tarGzipLayer := registry.addBlob("antrea/manifests", "application/vnd.oci.image.layer.v1.tar+gzip", gzipArchive(g, tarArchive(g, map[string]string{
+		"antrea.yml":    string(testFiles["antrea.yml"]),
+		"crds/crd.yaml": string(testFiles["crds/crd.yaml"]),
+		"README.md":     "not a template",
+	})))
+	titledLayer := registry.addBlob("antrea/manifests", "application/json", testFiles["extra/settings.json"])
+	titledLayer.Annotations = map[string]string{titleAnnotation: "extra/settings.json"}
+	manifestDigest := registry.addManifest(g, "antrea/manifests", "v1", tarGzipLayer, titledLayer)
+
+	tarLayer := registry.addBlob("antrea/tar", "application/vnd.oci.image.layer.v1.tar", tarArchive(g, map[string]string{"antrea.yml": "{}"}))
+	registry.addManifest(g, "antrea/tar", "latest", tarLayer)
+
+	traversalLayer := registry.addBlob("antrea/traversal", "application/vnd.oci.image.layer.v1.tar", tarArchive(g, map[string]string{"../../etc/antrea.yml": "{}"}))
+	registry.addManifest(g, "antrea/traversal", "v1", traversalLayer)
+
+	titleTraversalLayer := registry.addBlob("antrea/title-traversal", "application/json", []byte("{}"))
+	titleTraversalLayer.Annotations = map[string]string{titleAnnotation: "../antrea.json"}
+	registry.addManifest(g, "antrea/title-traversal", "v1", titleTraversalLayer)
+
+	// The manifest references a layer whose served content does not match its digest.
+	tamperedLayer := registry.addBlob("antrea/tampered", "application/json", []byte("{}"))
+	tamperedLayer.Annotations = map[string]string{titleAnnotation: "antrea.json"}
+	registry.objects["/v2/antrea/tampered/blobs/"+tamperedLayer.Digest] = []byte(`{"tampered": true}`)
+	registry.addManifest(g, "antrea/tampered", "v1", tamperedLayer)
+
+	// The registry serves a manifest under another digest.
+	registry.objects["/v2/antrea/manifests/manifests/"+tarLayer.Digest] = registry.objects["/v2/antrea/manifests/manifests/v1"]
+
+	unsupportedLayer := registry.addBlob("antrea/unsupported", "application/octet-stream", []byte("{}"))
+	registry.addManifest(g, "antrea/unsupported", "v1", unsupportedLayer)
+
+	for _, tc := range []struct {
+		name          string
+		reference     string
+		expectedFiles Files
+		expectedErr   string
+	}{
+		{name: "tag", reference: host + "/antrea/manifests:v1", expectedFiles: testFiles},
+		{name: "digest", reference: host + "/antrea/manifests@" + manifestDigest, expectedFiles: testFiles},
+		{name: "default tag and tar layer", reference: host + "/antrea/tar", expectedFiles: Files{"antrea.yml": []byte("{}")}},
+		{
+			name:        "manifest digest mismatch",
+			reference:   host + "/antrea/manifests@" + tarLayer.Digest,
+			expectedErr: "/manifests/" + tarLayer.Digest + " is " + manifestDigest,
+		},
+		{name: "layer digest mismatch", reference: host + "/antrea/tampered:v1", expectedErr: "expected " + tamperedLayer.Digest},
+		{name: "path traversal in tar", reference: host + "/antrea/traversal:v1", expectedErr: `invalid template path "../../etc/antrea.yml"`},
+		{name: "path traversal in title", reference: host + "/antrea/title-traversal:v1", expectedErr: `invalid template path "../antrea.json"`},
+		{name: "unsupported layer", reference: host + "/antrea/unsupported:v1", expectedErr: "neither a tar archive nor a titled file"},
+		{name: "missing manifest", reference: host + "/antrea/missing:v1", expectedErr: "404 Not Found"},
+		{name: "invalid reference", reference: "antrea", expectedErr: `invalid OCI reference "antrea"`},
+		{name: "invalid reference digest", reference: host + "/antrea/manifests@sha256:1234", expectedErr: "invalid digest in OCI reference"},
+	} {
+		files, err := pullOCI(context.TODO(), &operatorv1.OCIManifestSource{Reference: tc.reference, PlainHTTP: true})
+		if tc.expectedErr != "" {
+			g.Expect(err).Should(MatchError(ContainSubstring(tc.expectedErr)), tc.name)
+			continue
+		}
+		g.Expect(err).ShouldNot(HaveOccurred(), tc.name)
+		g.Expect(files).Should(Equal(tc.expectedFiles), tc.name)
+	}
+
+	// The verified templates are cached, and the artifact is not pulled again.
+	CacheDir = t.TempDir()
+	source := &operatorv1.ManifestSource{OCI: &operatorv1.OCIManifestSource{Reference: host + "/antrea/manifests:v1", PlainHTTP: true}, Digest: testDigest}
+	_, err := Resolve(context.TODO(), nil, testNamespace, source)
+	g.Expect(err).ShouldNot(HaveOccurred())
+	requests := registry.requests
+	dir, err := Resolve(context.TODO(), nil, testNamespace, source)
+	g.Expect(err).ShouldNot(HaveOccurred())
+	g.Expect(registry.requests).Should(Equal(requests))
+	files, err := ReadDir(dir)
+	g.Expect(err).ShouldNot(HaveOccurred())
+	g.Expect(files).Should(Equal(testFiles))
+
+	// The templates of an artifact are verified against the digest of the source.
+	CacheDir = t.TempDir()
+	source = &operatorv1.ManifestSource{OCI: &operatorv1.OCIManifestSource{Reference: host + "/antrea/tar", PlainHTTP: true}, Digest: testDigest}
+	_, err = Resolve(context.TODO(), nil, testNamespace, source)
+	g.Expect(err).Should(MatchError(ContainSubstring("the digest of the templates is")))
+}
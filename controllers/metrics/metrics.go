@@ -0,0 +1,176 @@
+/* Copyright © 2026 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package metrics
+
+import (
+	"math"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+const (
+	metricsNamespace = "antrea_operator"
+
+	ReconcileResultSuccess = "success"
+	ReconcileResultError   = "error"
+
+	RolloutStateUpdated   = "updated"
+	RolloutStateDesired   = "desired"
+	RolloutStateAvailable = "available"
+)
+
+var (
+	ReconcileTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Name:      "reconcile_total",
+			Help:      "Total number of reconciliations per controller and result.",
+		},
+		[]string{"controller", "result"},
+	)
+
+	ReconcileDuration = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: metricsNamespace,
+			Name:      "reconcile_duration_seconds",
+			Help:      "Duration of reconciliations per controller.",
+			Buckets:   prometheus.ExponentialBuckets(0.01, 2, 12),
+		},
+		[]string{"controller"},
+	)
+
+	DegradedTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Name:      "degraded_total",
+			Help:      "Total number of times the operator reported a Degraded condition, per status level and reason.",
+		},
+		[]string{"level", "reason"},
+	)
+
+	StatusDegraded = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: metricsNamespace,
+			Name:      "status_degraded",
+			Help:      "Whether the operator is degraded at a status level (1) or not (0).",
+		},
+		[]string{"level"},
+	)
+
+	RolloutPods = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: metricsNamespace,
+			Name:      "rollout_pods",
+			Help:      "Number of updated, desired and available Pods of the antrea-agent DaemonSet and the antrea-controller Deployment.",
+		},
+		[]string{"component", "state"},
+	)
+
+	Leader = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Namespace: metricsNamespace,
+			Name:      "leader",
+			Help:      "Whether this replica is the leader (1) or a standby (0).",
+		},
+	)
+
+	AppliedConfigGeneration = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Namespace: metricsNamespace,
+			Name:      "applied_config_generation",
+			Help:      "Generation of the AntreaInstall CR which was last applied successfully.",
+		},
+	)
+
+	lastSuccessfulApplyAge = prometheus.NewGaugeFunc(
+		prometheus.GaugeOpts{
+			Namespace: metricsNamespace,
+			Name:      "last_successful_apply_age_seconds",
+			Help:      "Seconds since the configurations were last applied successfully, NaN if they have not been applied yet.",
+		},
+		func() float64 {
+			lastApplyMutex.Lock()
+			defer lastApplyMutex.Unlock()
+			if lastApplyTime.IsZero() {
+				return math.NaN()
+			}
+			return time.Since(lastApplyTime).Seconds()
+		},
+	)
+
+	lastApplyMutex sync.Mutex
+	lastApplyTime  time.Time
+
+	// degradedReasons holds the reason of each degraded status level.
+	degradedMutex   sync.Mutex
+	degradedReasons = map[string]string{}
+)
+
+func init() {
+	metrics.Registry.MustRegister(
+		ReconcileTotal,
+		ReconcileDuration,
+		DegradedTotal,
+		StatusDegraded,
+		RolloutPods,
+		Leader,
+		AppliedConfigGeneration,
+		lastSuccessfulApplyAge,
+	)
+}
+
+// ObserveReconcile records the result and the duration of a reconciliation which started at start.
+func ObserveReconcile(controller string, start time.Time, err error) {
+	result := ReconcileResultSuccess
+	if err != nil {
+		result = ReconcileResultError
+	}
+	ReconcileTotal.WithLabelValues(controller, result).Inc()
+	ReconcileDuration.WithLabelValues(controller).Observe(time.Since(start).Seconds())
+}
+
+// SetDegraded records whether the operator is degraded at a status level, and counts the reason
+// when the level becomes degraded or its reason changes, so that the retries of a failing
+// reconciliation are not counted.
+func SetDegraded(level string, degraded bool, reason string) {
+	degradedMutex.Lock()
+	defer degradedMutex.Unlock()
+	if degraded {
+		if lastReason, ok := degradedReasons[level]; !ok || lastReason != reason {
+			DegradedTotal.WithLabelValues(level, reason).Inc()
+		}
+		degradedReasons[level] = reason
+		StatusDegraded.WithLabelValues(level).Set(1)
+	} else {
+		delete(degradedReasons, level)
+		StatusDegraded.WithLabelValues(level).Set(0)
+	}
+}
+
+// SetRolloutProgress records the rollout progress of an Antrea component.
+func SetRolloutProgress(component string, updated, desired, available int32) {
+	RolloutPods.WithLabelValues(component, RolloutStateUpdated).Set(float64(updated))
+	RolloutPods.WithLabelValues(component, RolloutStateDesired).Set(float64(desired))
+	RolloutPods.WithLabelValues(component, RolloutStateAvailable).Set(float64(available))
+}
+
+// SetLeader records whether this replica is the leader.
+func SetLeader(leader bool) {
+	if leader {
+		Leader.Set(1)
+	} else {
+		Leader.Set(0)
+	}
+}
+
+// SetAppliedConfig records a successful apply of the given AntreaInstall generation.
+func SetAppliedConfig(generation int64) {
+	lastApplyMutex.Lock()
+	defer lastApplyMutex.Unlock()
+	lastApplyTime = time.Now()
+	AppliedConfigGeneration.Set(float64(generation))
+}
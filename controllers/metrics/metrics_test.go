@@ -0,0 +1,103 @@
+/* Copyright © 2026 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package metrics
+
+import (
+	"bytes"
+	"errors"
+	"math"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/ghodss/yaml"
+	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestObserveReconcile(t *testing.T) {
+	g := NewGomegaWithT(t)
+	ObserveReconcile("test", time.Now(), nil)
+	ObserveReconcile("test", time.Now(), nil)
+	ObserveReconcile("test", time.Now(), errors.New("failed"))
+	g.Expect(testutil.ToFloat64(ReconcileTotal.WithLabelValues("test", ReconcileResultSuccess))).Should(Equal(float64(2)))
+	g.Expect(testutil.ToFloat64(ReconcileTotal.WithLabelValues("test", ReconcileResultError))).Should(Equal(float64(1)))
+	g.Expect(testutil.CollectAndCount(ReconcileDuration, "antrea_operator_reconcile_duration_seconds")).Should(BeNumerically(">=", 1))
+}
+
+func TestSetDegraded(t *testing.T) {
+	g := NewGomegaWithT(t)
+	level := "TestLevel"
+	for _, tc := range []struct {
+		name             string
+		degraded         bool
+		reason           string
+		expectedStatus   float64
+		expectedCounters map[string]float64
+	}{
+		{name: "degraded", degraded: true, reason: "A", expectedStatus: 1, expectedCounters: map[string]float64{"A": 1}},
+		{name: "retry with the same reason", degraded: true, reason: "A", expectedStatus: 1, expectedCounters: map[string]float64{"A": 1}},
+		{name: "reason changed", degraded: true, reason: "B", expectedStatus: 1, expectedCounters: map[string]float64{"A": 1, "B": 1}},
+		{name: "recovered", expectedStatus: 0, expectedCounters: map[string]float64{"A": 1, "B": 1}},
+		{name: "degraded again", degraded: true, reason: "A", expectedStatus: 1, expectedCounters: map[string]float64{"A": 2, "B": 1}},
+	} {
+		SetDegraded(level, tc.degraded, tc.reason)
+		g.Expect(testutil.ToFloat64(StatusDegraded.WithLabelValues(level))).Should(Equal(tc.expectedStatus), tc.name)
+		for reason, count := range tc.expectedCounters {
+			g.Expect(testutil.ToFloat64(DegradedTotal.WithLabelValues(level, reason))).Should(Equal(count), "%s: %s", tc.name, reason)
+		}
+	}
+}
+
+func TestSetAppliedConfig(t *testing.T) {
+	g := NewGomegaWithT(t)
+	g.Expect(math.IsNaN(testutil.ToFloat64(lastSuccessfulApplyAge))).Should(BeTrue())
+
+	SetAppliedConfig(3)
+	g.Expect(testutil.ToFloat64(AppliedConfigGeneration)).Should(Equal(float64(3)))
+	age := testutil.ToFloat64(lastSuccessfulApplyAge)
+	g.Expect(age).Should(BeNumerically(">=", 0))
+	g.Expect(age).Should(BeNumerically("<", 10))
+}
+
+func TestSetLeader(t *testing.T) {
+	g := NewGomegaWithT(t)
+	SetLeader(true)
+	g.Expect(testutil.ToFloat64(Leader)).Should(Equal(float64(1)))
+	SetLeader(false)
+	g.Expect(testutil.ToFloat64(Leader)).Should(Equal(float64(0)))
+}
+
+// TestServiceSelectors checks that the Services of the metrics and the webhooks, and the
+// ServiceMonitor, select the labels of the operator Pods.
+func TestServiceSelectors(t *testing.T) {
+	g := NewGomegaWithT(t)
+	read := func(path string) map[string]interface{} {
+		data, err := os.ReadFile(path)
+		g.Expect(err).ShouldNot(HaveOccurred(), path)
+		// The manager manifest starts with the Namespace.
+		for _, doc := range bytes.Split(data, []byte("\n---\n")) {
+			obj := map[string]interface{}{}
+			g.Expect(yaml.Unmarshal(doc, &obj)).Should(Succeed(), path)
+			if obj["kind"] != "Namespace" {
+				return obj
+			}
+		}
+		return nil
+	}
+	field := func(obj map[string]interface{}, fields ...string) interface{} {
+		var value interface{} = obj
+		for _, f := range fields {
+			value = value.(map[string]interface{})[f]
+		}
+		return value
+	}
+
+	podLabels := field(read("../../config/manager/manager.yaml"), "spec", "template", "metadata", "labels")
+	g.Expect(podLabels).Should(HaveKeyWithValue("name", "antrea-operator"))
+	g.Expect(field(read("../../config/rbac/auth_proxy_service.yaml"), "spec", "selector")).Should(Equal(podLabels))
+	g.Expect(field(read("../../config/webhook/service.yaml"), "spec", "selector")).Should(Equal(podLabels))
+	metricsServiceLabels := field(read("../../config/rbac/auth_proxy_service.yaml"), "metadata", "labels")
+	g.Expect(field(read("../../config/prometheus/monitor.yaml"), "spec", "selector", "matchLabels")).Should(Equal(metricsServiceLabels))
+}
@@ -0,0 +1,134 @@
+/* Copyright © 2026 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package platform
+
+import (
+	"context"
+	"strings"
+
+	configv1 "github.com/openshift/api/config/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/client-go/discovery"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+var log = logf.Log.WithName("platform")
+
+const (
+	// Auto asks the operator to detect the platform.
+	Auto = "auto"
+
+	OpenShift  = "openshift"
+	Kubernetes = "kubernetes"
+
+	DistributionOpenShift = "openshift"
+	DistributionK3s       = "k3s"
+	DistributionRKE2      = "rke2"
+	DistributionMicroK8s  = "microk8s"
+	DistributionEKS       = "eks"
+	DistributionAKS       = "aks"
+	DistributionGKE       = "gke"
+	DistributionGeneric   = "generic"
+
+	// nodeSampleSize is the number of Nodes whose markers are checked.
+	nodeSampleSize = 10
+)
+
+// Result is the outcome of a platform detection.
+type Result struct {
+	// Platform is openshift or kubernetes.
+	Platform string
+	// Distribution is the recognized Kubernetes distribution, or generic.
+	Distribution string
+}
+
+// Detector detects the platform from the APIs served by the cluster and the Node markers set by
+// the Kubernetes distributions. The distributions match the profiles of the config package.
+type Detector struct {
+	discovery discovery.DiscoveryInterface
+	reader    client.Reader
+}
+
+func NewDetector(discovery discovery.DiscoveryInterface, reader client.Reader) *Detector {
+	return &Detector{discovery: discovery, reader: reader}
+}
+
+// Detect returns openshift when the config.openshift.io/v1 Network and ClusterOperator kinds are
+// served, and kubernetes otherwise, along with the distribution found from the Nodes.
+func (d *Detector) Detect(ctx context.Context) (Result, error) {
+	openShift, err := d.servesOpenShiftConfig()
+	if err != nil {
+		return Result{}, err
+	}
+	if openShift {
+		return Result{Platform: OpenShift, Distribution: DistributionOpenShift}, nil
+	}
+
+	nodes := &corev1.NodeList{}
+	if err := d.reader.List(ctx, nodes, client.Limit(nodeSampleSize)); err != nil {
+		return Result{}, err
+	}
+	distribution := DistributionGeneric
+	for i := range nodes.Items {
+		if distribution = nodeDistribution(&nodes.Items[i]); distribution != DistributionGeneric {
+			break
+		}
+	}
+	log.Info("Detected platform", "platform", Kubernetes, "distribution", distribution)
+	return Result{Platform: Kubernetes, Distribution: distribution}, nil
+}
+
+func (d *Detector) servesOpenShiftConfig() (bool, error) {
+	resources, err := d.discovery.ServerResourcesForGroupVersion(configv1.GroupVersion.String())
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	var network, clusterOperator bool
+	for _, resource := range resources.APIResources {
+		switch resource.Kind {
+		case "Network":
+			network = true
+		case "ClusterOperator":
+			clusterOperator = true
+		}
+	}
+	return network && clusterOperator, nil
+}
+
+// nodeDistribution recognizes a distribution from the kubelet version suffix, the provider ID
+// and the labels it sets on a Node.
+func nodeDistribution(node *corev1.Node) string {
+	kubeletVersion := node.Status.NodeInfo.KubeletVersion
+	providerID := node.Spec.ProviderID
+	labels := node.Labels
+	switch {
+	case strings.Contains(kubeletVersion, "+k3s") || strings.HasPrefix(providerID, "k3s://"):
+		return DistributionK3s
+	case strings.Contains(kubeletVersion, "+rke2"):
+		return DistributionRKE2
+	case labels["microk8s.io/cluster"] == "true":
+		return DistributionMicroK8s
+	case strings.Contains(kubeletVersion, "-eks-") || hasLabelPrefix(labels, "eks.amazonaws.com/"):
+		return DistributionEKS
+	case strings.HasPrefix(providerID, "azure://") && hasLabelPrefix(labels, "kubernetes.azure.com/"):
+		return DistributionAKS
+	case strings.HasPrefix(providerID, "gce://") && hasLabelPrefix(labels, "cloud.google.com/gke-"):
+		return DistributionGKE
+	}
+	return DistributionGeneric
+}
+
+func hasLabelPrefix(labels map[string]string, prefix string) bool {
+	for key := range labels {
+		if strings.HasPrefix(key, prefix) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,139 @@
+/* Copyright © 2026 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package platform
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	configv1 "github.com/openshift/api/config/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	clienttesting "k8s.io/client-go/testing"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+type nodeMarkers struct {
+	kubeletVersion string
+	providerID     string
+	labels         map[string]string
+}
+
+func newNode(name string, markers nodeMarkers) *corev1.Node {
+	return &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Labels: markers.labels},
+		Spec:       corev1.NodeSpec{ProviderID: markers.providerID},
+		Status:     corev1.NodeStatus{NodeInfo: corev1.NodeSystemInfo{KubeletVersion: markers.kubeletVersion}},
+	}
+}
+
+func TestNodeDistribution(t *testing.T) {
+	g := NewGomegaWithT(t)
+	for _, tc := range []struct {
+		name     string
+		markers  nodeMarkers
+		expected string
+	}{
+		{name: "k3s kubelet", markers: nodeMarkers{kubeletVersion: "v1.26.4+k3s1"}, expected: DistributionK3s},
+		{name: "k3s provider", markers: nodeMarkers{kubeletVersion: "v1.26.4", providerID: "k3s://node1"}, expected: DistributionK3s},
+		{name: "rke2", markers: nodeMarkers{kubeletVersion: "v1.26.4+rke2r1"}, expected: DistributionRKE2},
+		{name: "microk8s", markers: nodeMarkers{kubeletVersion: "v1.26.4", labels: map[string]string{"microk8s.io/cluster": "true"}}, expected: DistributionMicroK8s},
+		{name: "eks kubelet", markers: nodeMarkers{kubeletVersion: "v1.26.4-eks-0a21954"}, expected: DistributionEKS},
+		{name: "eks label", markers: nodeMarkers{kubeletVersion: "v1.26.4", providerID: "aws:///us-west-2a/i-1", labels: map[string]string{"eks.amazonaws.com/nodegroup": "ng"}}, expected: DistributionEKS},
+		{name: "aks", markers: nodeMarkers{kubeletVersion: "v1.26.3", providerID: "azure:///subscriptions/1/vm-1", labels: map[string]string{"kubernetes.azure.com/cluster": "aks"}}, expected: DistributionAKS},
+		{name: "gke", markers: nodeMarkers{kubeletVersion: "v1.26.3-gke.1000", providerID: "gce://project/zone/vm-1", labels: map[string]string{"cloud.google.com/gke-nodepool": "pool"}}, expected: DistributionGKE},
+		// The kubelet version suffix takes precedence over the cloud markers.
+		{name: "k3s on azure", markers: nodeMarkers{kubeletVersion: "v1.26.4+k3s1", providerID: "azure:///subscriptions/1/vm-1", labels: map[string]string{"kubernetes.azure.com/cluster": "aks"}}, expected: DistributionK3s},
+		{name: "rke2 on eks nodes", markers: nodeMarkers{kubeletVersion: "v1.26.4+rke2r1", labels: map[string]string{"eks.amazonaws.com/nodegroup": "ng"}}, expected: DistributionRKE2},
+		// A cloud provider ID alone does not identify its managed distribution.
+		{name: "self-managed on azure", markers: nodeMarkers{kubeletVersion: "v1.26.3", providerID: "azure:///subscriptions/1/vm-1"}, expected: DistributionGeneric},
+		{name: "self-managed on gce", markers: nodeMarkers{kubeletVersion: "v1.26.3", providerID: "gce://project/zone/vm-1"}, expected: DistributionGeneric},
+		{name: "microk8s label not set", markers: nodeMarkers{kubeletVersion: "v1.26.3", labels: map[string]string{"microk8s.io/cluster": "false"}}, expected: DistributionGeneric},
+		{name: "unknown", markers: nodeMarkers{kubeletVersion: "v1.26.3"}, expected: DistributionGeneric},
+	} {
+		g.Expect(nodeDistribution(newNode("node1", tc.markers))).Should(Equal(tc.expected), tc.name)
+	}
+}
+
+func TestDetect(t *testing.T) {
+	g := NewGomegaWithT(t)
+	openShiftResources := &metav1.APIResourceList{
+		GroupVersion: configv1.GroupVersion.String(),
+		APIResources: []metav1.APIResource{{Name: "networks", Kind: "Network"}, {Name: "clusteroperators", Kind: "ClusterOperator"}},
+	}
+	networkOnlyResources := &metav1.APIResourceList{
+		GroupVersion: configv1.GroupVersion.String(),
+		APIResources: []metav1.APIResource{{Name: "networks", Kind: "Network"}},
+	}
+	k3sNode := nodeMarkers{kubeletVersion: "v1.26.4+k3s1"}
+	eksNode := nodeMarkers{kubeletVersion: "v1.26.4-eks-0a21954"}
+	genericNode := nodeMarkers{kubeletVersion: "v1.26.4"}
+
+	for _, tc := range []struct {
+		name      string
+		resources []*metav1.APIResourceList
+		nodes     []nodeMarkers
+		expected  Result
+	}{
+		{
+			name:      "openshift",
+			resources: []*metav1.APIResourceList{openShiftResources},
+			nodes:     []nodeMarkers{k3sNode},
+			expected:  Result{Platform: OpenShift, Distribution: DistributionOpenShift},
+		},
+		{
+			name:      "config.openshift.io without ClusterOperator",
+			resources: []*metav1.APIResourceList{networkOnlyResources},
+			nodes:     []nodeMarkers{genericNode},
+			expected:  Result{Platform: Kubernetes, Distribution: DistributionGeneric},
+		},
+		{
+			name:     "k3s",
+			nodes:    []nodeMarkers{k3sNode, k3sNode},
+			expected: Result{Platform: Kubernetes, Distribution: DistributionK3s},
+		},
+		{
+			// Nodes without markers, such as the ones of a control plane, are skipped.
+			name:     "recognized after an unknown node",
+			nodes:    []nodeMarkers{genericNode, eksNode},
+			expected: Result{Platform: Kubernetes, Distribution: DistributionEKS},
+		},
+		{
+			// The Nodes are listed by name, the first recognized distribution wins.
+			name:     "ambiguous nodes",
+			nodes:    []nodeMarkers{eksNode, k3sNode},
+			expected: Result{Platform: Kubernetes, Distribution: DistributionEKS},
+		},
+		{
+			name:     "unknown nodes",
+			nodes:    []nodeMarkers{genericNode, genericNode},
+			expected: Result{Platform: Kubernetes, Distribution: DistributionGeneric},
+		},
+		{
+			name:     "no nodes",
+			expected: Result{Platform: Kubernetes, Distribution: DistributionGeneric},
+		},
+	} {
+		var objs []client.Object
+		for i, markers := range tc.nodes {
+			objs = append(objs, newNode(fmt.Sprintf("node%d", i), markers))
+		}
+		reader := fake.NewClientBuilder().WithScheme(clientgoscheme.Scheme).WithObjects(objs...).Build()
+		discovery := &fakediscovery.FakeDiscovery{Fake: &clienttesting.Fake{Resources: tc.resources}}
+		result, err := NewDetector(discovery, reader).Detect(context.TODO())
+		g.Expect(err).ShouldNot(HaveOccurred(), tc.name)
+		g.Expect(result).Should(Equal(tc.expected), tc.name)
+	}
+
+	// The failure to list the Nodes is returned.
+	discovery := &fakediscovery.FakeDiscovery{Fake: &clienttesting.Fake{}}
+	_, err := NewDetector(discovery, fake.NewClientBuilder().WithScheme(runtime.NewScheme()).Build()).Detect(context.TODO())
+	g.Expect(err).Should(HaveOccurred())
+}
@@ -0,0 +1,186 @@
+/* Copyright © 2026 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	configv1 "github.com/openshift/api/config/v1"
+	ocoperv1 "github.com/openshift/api/operator/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	operatorv1 "github.com/vmware/antrea-operator-for-kubernetes/api/v1"
+	"github.com/vmware/antrea-operator-for-kubernetes/controllers/clusternetwork"
+	configutil "github.com/vmware/antrea-operator-for-kubernetes/controllers/config"
+	"github.com/vmware/antrea-operator-for-kubernetes/controllers/platform"
+	"github.com/vmware/antrea-operator-for-kubernetes/controllers/statusmanager"
+	operatortypes "github.com/vmware/antrea-operator-for-kubernetes/controllers/types"
+)
+
+// PlatformNetwork holds the cluster network the configurations of a platform are filled against.
+type PlatformNetwork struct {
+	// ClusterConfig is the cluster Network on OpenShift, and the discovered network on Kubernetes.
+	ClusterConfig *configv1.Network
+	// OperatorNetwork is the configuration of the cluster network operator, on OpenShift.
+	OperatorNetwork *ocoperv1.Network
+	// NodeIPAM tells whether antrea-controller allocates the Pod CIDRs of the Nodes, on Kubernetes.
+	NodeIPAM bool
+}
+
+// platformAdaptors builds the reconciler and status manager adaptors of a platform, and the
+// configuration pipeline the webhook and the render and diff subcommands run outside of them.
+type platformAdaptors struct {
+	newAdaptor       func(r *AntreaInstallReconciler) (Adaptor, error)
+	newStatusAdaptor func() statusmanager.Adaptor
+	// newConfig selects the profile of spec.profile and returns the configuration pipeline, with
+	// the bundled manifests of manifestDir.
+	newConfig func(profileName, distribution string, network *PlatformNetwork, manifestDir string) (configutil.Config, error)
+	// loadNetwork reads the cluster network from the cluster.
+	loadNetwork func(ctx context.Context, reader client.Reader) (*PlatformNetwork, error)
+	// ownedByClusterNetwork tells whether the Antrea objects are owned by the cluster Network, and
+	// are related objects of the antrea ClusterOperator, deleted when they are not rendered anymore.
+	ownedByClusterNetwork bool
+}
+
+// platforms holds the platforms which can be selected with spec.antreaPlatform, or detected.
+var platforms = map[string]platformAdaptors{
+	platform.OpenShift: {
+		newAdaptor: func(r *AntreaInstallReconciler) (Adaptor, error) {
+			if err := r.watchClusterNetwork(); err != nil {
+				return nil, err
+			}
+			return &AdaptorOc{Config: &configutil.ConfigOc{ManifestDir: operatortypes.ManifestDir}}, nil
+		},
+		newStatusAdaptor: func() statusmanager.Adaptor { return &statusmanager.StatusOc{} },
+		newConfig: func(profileName, distribution string, network *PlatformNetwork, manifestDir string) (configutil.Config, error) {
+			if _, err := configutil.SelectProfile(profileName, distribution, true); err != nil {
+				return nil, err
+			}
+			if network.ClusterConfig == nil {
+				return nil, fmt.Errorf("the cluster Network is required on %s", platform.OpenShift)
+			}
+			return &configutil.ConfigOc{ManifestDir: manifestDir}, nil
+		},
+		loadNetwork: func(ctx context.Context, reader client.Reader) (*PlatformNetwork, error) {
+			network := &PlatformNetwork{ClusterConfig: &configv1.Network{}, OperatorNetwork: &ocoperv1.Network{}}
+			if err := reader.Get(ctx, client.ObjectKey{Name: operatortypes.ClusterConfigName}, network.ClusterConfig); err != nil {
+				return nil, fmt.Errorf("failed to get cluster Network: %v", err)
+			}
+			if err := reader.Get(ctx, client.ObjectKey{Name: operatortypes.ClusterOperatorNetworkName}, network.OperatorNetwork); err != nil {
+				return nil, fmt.Errorf("failed to get cluster network operator configuration: %v", err)
+			}
+			return network, nil
+		},
+		ownedByClusterNetwork: true,
+	},
+	platform.Kubernetes: {
+		newAdaptor: func(r *AntreaInstallReconciler) (Adaptor, error) {
+			return &AdaptorK8s{Config: &configutil.ConfigK8s{ManifestDir: operatortypes.ManifestDir}}, nil
+		},
+		newStatusAdaptor: func() statusmanager.Adaptor { return &statusmanager.StatusK8s{} },
+		newConfig: func(profileName, distribution string, network *PlatformNetwork, manifestDir string) (configutil.Config, error) {
+			profile, err := configutil.SelectProfile(profileName, distribution, false)
+			if err != nil {
+				return nil, err
+			}
+			return &configutil.ConfigK8s{Profile: profile, NodeIPAM: network.NodeIPAM, ManifestDir: manifestDir}, nil
+		},
+		loadNetwork: func(ctx context.Context, reader client.Reader) (*PlatformNetwork, error) {
+			discovered := clusternetwork.NewDiscoverer(reader).Discover(ctx)
+			return &PlatformNetwork{ClusterConfig: discovered.Network(), NodeIPAM: discovered.AntreaNodeIPAM()}, nil
+		},
+	},
+}
+
+func platformNames() []string {
+	names := make([]string, 0, len(platforms)+1)
+	for name := range platforms {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return append(names, platform.Auto)
+}
+
+func lookupPlatform(name string) (platformAdaptors, error) {
+	adaptors, ok := platforms[name]
+	if !ok {
+		return platformAdaptors{}, fmt.Errorf("invalid platform %q: platform should be one of %s", name, strings.Join(platformNames(), ", "))
+	}
+	return adaptors, nil
+}
+
+// NewPlatformConfig returns the configuration pipeline of the platform name, with the profile
+// selected by spec.profile and the bundled manifests of manifestDir.
+func NewPlatformConfig(name, profileName, distribution string, network *PlatformNetwork, manifestDir string) (configutil.Config, error) {
+	adaptors, err := lookupPlatform(name)
+	if err != nil {
+		return nil, err
+	}
+	return adaptors.newConfig(profileName, distribution, network, manifestDir)
+}
+
+// LoadPlatformNetwork reads the cluster network of the platform name with reader.
+func LoadPlatformNetwork(ctx context.Context, name string, reader client.Reader) (*PlatformNetwork, error) {
+	adaptors, err := lookupPlatform(name)
+	if err != nil {
+		return nil, err
+	}
+	return adaptors.loadNetwork(ctx, reader)
+}
+
+// OwnedByClusterNetwork tells whether the Antrea objects are owned by the cluster Network on the
+// platform name.
+func OwnedByClusterNetwork(name string) bool {
+	return platforms[name].ownedByClusterNetwork
+}
+
+// ResolvePlatform returns the platform selected by spec.antreaPlatform, and the detected one. The
+// detection error is only returned when the platform is auto or empty, detected is nil otherwise.
+func ResolvePlatform(ctx context.Context, detector *platform.Detector, name string) (selected, detected *operatorv1.PlatformStatus, err error) {
+	result, err := detector.Detect(ctx)
+	if err != nil {
+		if name == "" || name == platform.Auto {
+			return nil, nil, err
+		}
+		// The distribution is only informational when the platform is set explicitly.
+		log.Error(err, "failed to detect platform")
+		return &operatorv1.PlatformStatus{Name: name}, nil, nil
+	}
+	detected = &operatorv1.PlatformStatus{Name: result.Platform, Distribution: result.Distribution, Detected: true}
+	return selectPlatform(name, detected), detected, nil
+}
+
+// selectPlatform returns the platform selected by spec.antreaPlatform, the detected one when it is
+// auto or empty. It returns nil when the platform is auto and was not detected.
+func selectPlatform(name string, detected *operatorv1.PlatformStatus) *operatorv1.PlatformStatus {
+	if name == "" || name == platform.Auto {
+		if detected == nil {
+			return nil
+		}
+		return detected.DeepCopy()
+	}
+	platformStatus := &operatorv1.PlatformStatus{Name: name}
+	if detected != nil && detected.Name == name {
+		platformStatus.Distribution = detected.Distribution
+	}
+	return platformStatus
+}
+
+// resolvePlatform returns the platform selected by spec.antreaPlatform, detecting it when the
+// field is auto or empty. The detection result is cached, as the platform of a cluster does not
+// change.
+func (r *AntreaInstallReconciler) resolvePlatform(name string) (*operatorv1.PlatformStatus, error) {
+	if r.detectedPlatform == nil {
+		selected, detected, err := ResolvePlatform(context.TODO(), r.Detector, name)
+		if err != nil {
+			return nil, err
+		}
+		r.detectedPlatform = detected
+		return selected, nil
+	}
+	return selectPlatform(name, r.detectedPlatform), nil
+}
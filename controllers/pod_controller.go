@@ -24,6 +24,9 @@ import (
 	"github.com/openshift/cluster-network-operator/pkg/apply"
 	cnocient "github.com/openshift/cluster-network-operator/pkg/client"
 
+	configv1alpha1 "github.com/vmware/antrea-operator-for-kubernetes/api/config/v1alpha1"
+	"github.com/vmware/antrea-operator-for-kubernetes/controllers/health"
+	"github.com/vmware/antrea-operator-for-kubernetes/controllers/metrics"
 	"github.com/vmware/antrea-operator-for-kubernetes/controllers/sharedinfo"
 	"github.com/vmware/antrea-operator-for-kubernetes/controllers/statusmanager"
 )
@@ -31,7 +34,7 @@ import (
 // The periodic resync interval.
 // We will re-run the reconciliation logic, even if the NCP configuration
 // hasn't changed.
-var ResyncPeriod = 2 * time.Minute
+var ResyncPeriod = configv1alpha1.DefaultResyncPeriod
 
 // PodReconciler reconciles a Pod object
 type PodReconciler struct {
@@ -40,6 +43,7 @@ type PodReconciler struct {
 	Scheme     *runtime.Scheme
 	Status     *statusmanager.StatusManager
 	SharedInfo *sharedinfo.SharedInfo
+	Health     *health.Checker
 }
 
 func (r *PodReconciler) SetupWithManager(mgr ctrl.Manager) error {
@@ -51,6 +55,14 @@ func (r *PodReconciler) SetupWithManager(mgr ctrl.Manager) error {
 
 // Reconcile updates the ClusterOperator.Status to match the current state of the watched Deployments/DaemonSets
 func (r *PodReconciler) Reconcile(ctx context.Context, request reconcile.Request) (reconcile.Result, error) {
+	defer r.Health.TrackReconcile("pod")()
+	start := time.Now()
+	result, err := r.reconcile(request)
+	metrics.ObserveReconcile("pod", start, err)
+	return result, err
+}
+
+func (r *PodReconciler) reconcile(request reconcile.Request) (reconcile.Result, error) {
 	reqLogger := r.Log.WithValues("Request.Namespace", request.Namespace, "Request.Name", request.Name)
 	reqLogger.Info("Reconciling pod update")
 
@@ -115,5 +127,6 @@ func (r *PodReconciler) recreateResourceIfNotExist(request *reconcile.Request) e
 		return err
 	}
 	r.Log.Info(fmt.Sprintf("Recreated K8s resource: %s", request.Name))
+	r.Status.Normal(statusmanager.EventReasonResourceRecreated, fmt.Sprintf("Recreated %s %s/%s", objectSpec.GetKind(), objectSpec.GetNamespace(), objectSpec.GetName()))
 	return nil
 }
@@ -0,0 +1,308 @@
+/* Copyright © 2026 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+// Package preview compares the objects rendered for an AntreaInstall with the live objects, and
+// predicts the Pod restarts applying them causes.
+package preview
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"strings"
+
+	uns "k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	operatorv1 "github.com/vmware/antrea-operator-for-kubernetes/api/v1"
+	configutil "github.com/vmware/antrea-operator-for-kubernetes/controllers/config"
+	"github.com/vmware/antrea-operator-for-kubernetes/controllers/types"
+)
+
+const (
+	OpAdd    = "+"
+	OpRemove = "-"
+	OpChange = "~"
+)
+
+// Change is a difference between a rendered object and the live one, at a field path.
+type Change struct {
+	Path string
+	Op   string
+	Old  interface{}
+	New  interface{}
+}
+
+// ObjectDiff holds the differences between a rendered object and the live one.
+type ObjectDiff struct {
+	Kind      string
+	Namespace string
+	Name      string
+	// Created tells that the object does not exist in the cluster.
+	Created bool
+	Changes []Change
+}
+
+// Changed tells whether applying the rendered object changes the cluster.
+func (d *ObjectDiff) Changed() bool {
+	return d.Created || len(d.Changes) > 0
+}
+
+func (d *ObjectDiff) String() string {
+	return ObjectName(d.Kind, d.Namespace, d.Name)
+}
+
+// ObjectName names an object as Kind namespace/name, or Kind name when it is cluster-scoped.
+func ObjectName(kind, namespace, name string) string {
+	if namespace == "" {
+		return fmt.Sprintf("%s %s", kind, name)
+	}
+	return fmt.Sprintf("%s %s/%s", kind, namespace, name)
+}
+
+// DiffObject compares the fields set in the rendered object with the live object, which is nil
+// when it does not exist. The fields only set in the live object, such as the status and the
+// defaults filled by the API server, are ignored, as applying the rendered object keeps them.
+func DiffObject(rendered, live *uns.Unstructured) *ObjectDiff {
+	diff := &ObjectDiff{Kind: rendered.GetKind(), Namespace: rendered.GetNamespace(), Name: rendered.GetName()}
+	if live == nil {
+		diff.Created = true
+		return diff
+	}
+	diffValues("", rendered.Object, live.Object, &diff.Changes)
+	return diff
+}
+
+func diffValues(path string, rendered, live interface{}, changes *[]Change) {
+	if rendered == nil {
+		return
+	}
+	switch renderedValue := rendered.(type) {
+	case map[string]interface{}:
+		liveValue, ok := live.(map[string]interface{})
+		if !ok {
+			break
+		}
+		keys := make([]string, 0, len(renderedValue))
+		for key := range renderedValue {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			fieldPath := key
+			if path != "" {
+				fieldPath = path + "." + key
+			}
+			if _, ok := liveValue[key]; !ok {
+				if !isEmpty(renderedValue[key]) {
+					*changes = append(*changes, Change{Path: fieldPath, Op: OpAdd, New: renderedValue[key]})
+				}
+				continue
+			}
+			diffValues(fieldPath, renderedValue[key], liveValue[key], changes)
+		}
+		return
+	case []interface{}:
+		liveValue, ok := live.([]interface{})
+		if !ok {
+			break
+		}
+		for i, item := range renderedValue {
+			itemPath := fmt.Sprintf("%s[%d]", path, i)
+			if i >= len(liveValue) {
+				*changes = append(*changes, Change{Path: itemPath, Op: OpAdd, New: item})
+				continue
+			}
+			diffValues(itemPath, item, liveValue[i], changes)
+		}
+		// The lists are replaced when the object is applied.
+		for i := len(renderedValue); i < len(liveValue); i++ {
+			*changes = append(*changes, Change{Path: fmt.Sprintf("%s[%d]", path, i), Op: OpRemove, Old: liveValue[i]})
+		}
+		return
+	default:
+		if valuesEqual(rendered, live) {
+			return
+		}
+	}
+	*changes = append(*changes, Change{Path: path, Op: OpChange, Old: live, New: rendered})
+}
+
+// isEmpty tells whether a rendered value is dropped by the API server when it is empty, as the
+// omitempty fields are.
+func isEmpty(value interface{}) bool {
+	if value == nil {
+		return true
+	}
+	switch v := value.(type) {
+	case string:
+		return v == ""
+	case bool:
+		return !v
+	case map[string]interface{}:
+		return len(v) == 0
+	case []interface{}:
+		return len(v) == 0
+	}
+	f, ok := toFloat(value)
+	return ok && f == 0
+}
+
+// valuesEqual compares scalars, numbers by value since they may be decoded as integers or floats.
+func valuesEqual(a, b interface{}) bool {
+	if x, ok := toFloat(a); ok {
+		y, ok := toFloat(b)
+		return ok && x == y
+	}
+	return reflect.DeepEqual(a, b)
+}
+
+func toFloat(value interface{}) (float64, bool) {
+	switch v := value.(type) {
+	case int:
+		return float64(v), true
+	case int32:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case float64:
+		return v, true
+	}
+	return 0, false
+}
+
+// Restarts tells how applying a configuration affects the Antrea Pods, as the controller does.
+type Restarts struct {
+	// Install tells that Antrea is not installed yet.
+	Install bool
+	// AgentRestart and ControllerRestart tell that the Pods are deleted to pick up a new
+	// configuration.
+	AgentRestart      bool
+	ControllerRestart bool
+	// ImageChange tells that all the Pods are rolled out with a new image.
+	ImageChange bool
+}
+
+// PredictRestarts compares the applied configurations, which are nil when Antrea is not
+// installed, with the filled proposed configurations.
+func PredictRestarts(applied, proposed *operatorv1.AntreaInstall) Restarts {
+	if applied == nil {
+		return Restarts{Install: true}
+	}
+	agentNeedChange, controllerNeedChange, imageChange := configutil.NeedApplyChange(applied, proposed)
+	return Restarts{
+		AgentRestart:      agentNeedChange && !imageChange,
+		ControllerRestart: controllerNeedChange && !imageChange,
+		ImageChange:       imageChange,
+	}
+}
+
+func (r Restarts) String() string {
+	switch {
+	case r.Install:
+		return "Antrea is not installed, it would be installed."
+	case r.ImageChange:
+		return fmt.Sprintf("The %s and %s Pods would be rolled out with the new image.", types.AntreaAgentDaemonSetName, types.AntreaControllerDeploymentName)
+	}
+	var restarted []string
+	if r.AgentRestart {
+		restarted = append(restarted, types.AntreaAgentDaemonSetName)
+	}
+	if r.ControllerRestart {
+		restarted = append(restarted, types.AntreaControllerDeploymentName)
+	}
+	if len(restarted) == 0 {
+		return "No Antrea Pod would be restarted."
+	}
+	return fmt.Sprintf("The %s Pods would be restarted to pick up the configuration changes.", strings.Join(restarted, " and "))
+}
+
+// WriteDiffs writes the changed objects with their changes, then the names of the deleted
+// objects, and counts the unchanged ones.
+func WriteDiffs(w io.Writer, diffs []*ObjectDiff, deleted []string) {
+	unchanged := 0
+	for _, diff := range diffs {
+		switch {
+		case diff.Created:
+			fmt.Fprintf(w, "%s %s (created)\n", OpAdd, diff)
+		case len(diff.Changes) > 0:
+			fmt.Fprintf(w, "%s %s\n", OpChange, diff)
+			for _, change := range diff.Changes {
+				writeChange(w, change)
+			}
+		default:
+			unchanged++
+		}
+	}
+	for _, name := range deleted {
+		fmt.Fprintf(w, "%s %s (deleted)\n", OpRemove, name)
+	}
+	fmt.Fprintf(w, "%d objects unchanged.\n", unchanged)
+}
+
+func writeChange(w io.Writer, change Change) {
+	switch change.Op {
+	case OpAdd:
+		fmt.Fprintf(w, "    %s %s: %s\n", OpAdd, change.Path, formatValue(change.New))
+	case OpRemove:
+		fmt.Fprintf(w, "    %s %s: %s\n", OpRemove, change.Path, formatValue(change.Old))
+	default:
+		oldString, oldOK := change.Old.(string)
+		newString, newOK := change.New.(string)
+		if oldOK && newOK && (strings.Contains(oldString, "\n") || strings.Contains(newString, "\n")) {
+			// Show the changed lines of the configurations.
+			fmt.Fprintf(w, "    %s %s:\n", OpChange, change.Path)
+			for _, line := range lineDiff(oldString, newString) {
+				fmt.Fprintf(w, "        %s\n", line)
+			}
+			return
+		}
+		fmt.Fprintf(w, "    %s %s: %s -> %s\n", OpChange, change.Path, formatValue(change.Old), formatValue(change.New))
+	}
+}
+
+func formatValue(value interface{}) string {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+	return string(data)
+}
+
+// lineDiff returns the removed and added lines between two texts, from their longest common
+// subsequence of lines.
+func lineDiff(oldText, newText string) []string {
+	a, b := strings.Split(oldText, "\n"), strings.Split(newText, "\n")
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else if lcs[i+1][j] >= lcs[i][j+1] {
+				lcs[i][j] = lcs[i+1][j]
+			} else {
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+	var lines []string
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case i < len(a) && j < len(b) && a[i] == b[j]:
+			i++
+			j++
+		case j < len(b) && (i == len(a) || lcs[i][j+1] > lcs[i+1][j]):
+			lines = append(lines, OpAdd+" "+b[j])
+			j++
+		default:
+			lines = append(lines, OpRemove+" "+a[i])
+			i++
+		}
+	}
+	return lines
+}
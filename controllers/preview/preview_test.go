@@ -0,0 +1,170 @@
+/* Copyright © 2026 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package preview
+
+import (
+	"bytes"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	uns "k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	operatorv1 "github.com/vmware/antrea-operator-for-kubernetes/api/v1"
+)
+
+func newConfigMap(data map[string]interface{}) *uns.Unstructured {
+	obj := &uns.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "ConfigMap",
+		"metadata":   map[string]interface{}{"name": "antrea-config", "namespace": "kube-system"},
+	}}
+	if data != nil {
+		obj.Object["data"] = data
+	}
+	return obj
+}
+
+func TestDiffObject(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	diff := DiffObject(newConfigMap(nil), nil)
+	g.Expect(diff.Created).Should(BeTrue())
+	g.Expect(diff.Changed()).Should(BeTrue())
+	g.Expect(diff.String()).Should(Equal("ConfigMap kube-system/antrea-config"))
+
+	for _, tc := range []struct {
+		name            string
+		rendered        map[string]interface{}
+		live            map[string]interface{}
+		expectedChanges []Change
+	}{
+		{
+			name:     "unchanged",
+			rendered: map[string]interface{}{"a": "x", "n": int64(1)},
+			live:     map[string]interface{}{"a": "x", "n": int64(1)},
+		},
+		{
+			name:     "integer and float equal",
+			rendered: map[string]interface{}{"n": int64(1450)},
+			live:     map[string]interface{}{"n": float64(1450)},
+		},
+		{
+			name:            "integer and float differ",
+			rendered:        map[string]interface{}{"n": int64(1450)},
+			live:            map[string]interface{}{"n": float64(1400)},
+			expectedChanges: []Change{{Path: "data.n", Op: OpChange, Old: float64(1400), New: int64(1450)}},
+		},
+		{
+			name:     "live only field ignored",
+			rendered: map[string]interface{}{"a": "x"},
+			live:     map[string]interface{}{"a": "x", "status": "ready"},
+		},
+		{
+			name:            "field added",
+			rendered:        map[string]interface{}{"a": "x", "b": "y"},
+			live:            map[string]interface{}{"a": "x"},
+			expectedChanges: []Change{{Path: "data.b", Op: OpAdd, New: "y"}},
+		},
+		{
+			name:     "empty field dropped by the API server",
+			rendered: map[string]interface{}{"a": "x", "b": "", "c": false, "d": int64(0), "e": []interface{}{}},
+			live:     map[string]interface{}{"a": "x"},
+		},
+		{
+			name:            "list item created",
+			rendered:        map[string]interface{}{"l": []interface{}{"a", "b"}},
+			live:            map[string]interface{}{"l": []interface{}{"a"}},
+			expectedChanges: []Change{{Path: "data.l[1]", Op: OpAdd, New: "b"}},
+		},
+		{
+			name:            "list item changed",
+			rendered:        map[string]interface{}{"l": []interface{}{map[string]interface{}{"name": "a", "port": int64(443)}}},
+			live:            map[string]interface{}{"l": []interface{}{map[string]interface{}{"name": "a", "port": float64(10349)}}},
+			expectedChanges: []Change{{Path: "data.l[0].port", Op: OpChange, Old: float64(10349), New: int64(443)}},
+		},
+		{
+			name:            "list item removed",
+			rendered:        map[string]interface{}{"l": []interface{}{"a"}},
+			live:            map[string]interface{}{"l": []interface{}{"a", "b", "c"}},
+			expectedChanges: []Change{{Path: "data.l[1]", Op: OpRemove, Old: "b"}, {Path: "data.l[2]", Op: OpRemove, Old: "c"}},
+		},
+		{
+			name:            "type changed",
+			rendered:        map[string]interface{}{"a": map[string]interface{}{"b": "c"}},
+			live:            map[string]interface{}{"a": "b"},
+			expectedChanges: []Change{{Path: "data.a", Op: OpChange, Old: "b", New: map[string]interface{}{"b": "c"}}},
+		},
+	} {
+		diff := DiffObject(newConfigMap(tc.rendered), newConfigMap(tc.live))
+		g.Expect(diff.Created).Should(BeFalse(), tc.name)
+		g.Expect(diff.Changes).Should(Equal(tc.expectedChanges), tc.name)
+		g.Expect(diff.Changed()).Should(Equal(len(tc.expectedChanges) > 0), tc.name)
+	}
+}
+
+func TestIsEmpty(t *testing.T) {
+	g := NewGomegaWithT(t)
+	for _, value := range []interface{}{nil, "", false, int64(0), float64(0), map[string]interface{}{}, []interface{}{}} {
+		g.Expect(isEmpty(value)).Should(BeTrue(), "%#v", value)
+	}
+	for _, value := range []interface{}{"x", true, int64(1), float64(0.5), map[string]interface{}{"a": "b"}, []interface{}{"a"}} {
+		g.Expect(isEmpty(value)).Should(BeFalse(), "%#v", value)
+	}
+}
+
+func TestLineDiff(t *testing.T) {
+	g := NewGomegaWithT(t)
+	for _, tc := range []struct {
+		name     string
+		oldText  string
+		newText  string
+		expected []string
+	}{
+		{name: "unchanged", oldText: "a\nb", newText: "a\nb"},
+		{name: "line added", oldText: "a\nc", newText: "a\nb\nc", expected: []string{"+ b"}},
+		{name: "line removed", oldText: "a\nb\nc", newText: "a\nc", expected: []string{"- b"}},
+		{
+			name:     "line changed",
+			oldText:  "featureGates:\n  Egress: true\ntrafficEncapMode: encap",
+			newText:  "featureGates:\n  Egress: false\ntrafficEncapMode: encap",
+			expected: []string{"-   Egress: true", "+   Egress: false"},
+		},
+		{
+			name:     "several changes",
+			oldText:  "a\nb\nc\nd",
+			newText:  "b\nc\ne\nd\nf",
+			expected: []string{"- a", "+ e", "+ f"},
+		},
+	} {
+		g.Expect(lineDiff(tc.oldText, tc.newText)).Should(Equal(tc.expected), tc.name)
+	}
+}
+
+func TestWriteDiffs(t *testing.T) {
+	g := NewGomegaWithT(t)
+	rendered := newConfigMap(map[string]interface{}{"antrea-agent.conf": "trafficEncapMode: encap\ndefaultMTU: 1450\n", "n": int64(1)})
+	live := newConfigMap(map[string]interface{}{"antrea-agent.conf": "trafficEncapMode: encap\ndefaultMTU: 1400\n", "n": float64(2)})
+	unchanged := newConfigMap(nil)
+	unchanged.SetName("antrea-ca")
+
+	var out bytes.Buffer
+	WriteDiffs(&out, []*ObjectDiff{DiffObject(rendered, live), DiffObject(unchanged, unchanged.DeepCopy()), DiffObject(unchanged, nil)}, []string{"ServiceAccount kube-system/antrea-stale"})
+	g.Expect(out.String()).Should(Equal(`~ ConfigMap kube-system/antrea-config
+    ~ data.antrea-agent.conf:
+        - defaultMTU: 1400
+        + defaultMTU: 1450
+    ~ data.n: 2 -> 1
++ ConfigMap kube-system/antrea-ca (created)
+- ServiceAccount kube-system/antrea-stale (deleted)
+1 objects unchanged.
+`))
+}
+
+func TestPredictRestarts(t *testing.T) {
+	g := NewGomegaWithT(t)
+	g.Expect(PredictRestarts(nil, &operatorv1.AntreaInstall{})).Should(Equal(Restarts{Install: true}))
+	g.Expect(Restarts{Install: true}.String()).Should(ContainSubstring("would be installed"))
+	g.Expect(Restarts{}.String()).Should(Equal("No Antrea Pod would be restarted."))
+	g.Expect(Restarts{AgentRestart: true, ControllerRestart: true}.String()).Should(ContainSubstring("antrea-agent and antrea-controller Pods would be restarted"))
+}
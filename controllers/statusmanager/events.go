@@ -0,0 +1,149 @@
+/* Copyright © 2026 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package statusmanager
+
+import (
+	"context"
+
+	configv1 "github.com/openshift/api/config/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+
+	operatorv1 "github.com/vmware/antrea-operator-for-kubernetes/api/v1"
+	operatortypes "github.com/vmware/antrea-operator-for-kubernetes/controllers/types"
+)
+
+// Reasons of the Events emitted by the operator. They are part of the operator interface, so that
+// alerts can be built on them, and must not be changed.
+const (
+	EventReasonConfigFilled          = "ConfigFilled"
+	EventReasonFillFailed            = "FillFailed"
+	EventReasonValidationFailed      = "ValidationFailed"
+	EventReasonUnknownConfigFields   = "UnknownConfigFields"
+	EventReasonRenderFailed          = "RenderFailed"
+	EventReasonApplyFailed           = "ApplyFailed"
+	EventReasonObjectsApplied        = "ObjectsApplied"
+	EventReasonPodsRestarted         = "PodsRestarted"
+	EventReasonResourceRecreated     = "ResourceRecreated"
+	EventReasonRolloutHung           = "RolloutHung"
+	EventReasonPlatformChangeRefused = "PlatformChangeRefused"
+	EventReasonDryRun                = "DryRun"
+	EventReasonChangePending         = "ChangePending"
+	EventReasonChangeApproved        = "ChangeApproved"
+	EventReasonApprovalExpired       = "ApprovalExpired"
+	EventReasonChangeScheduled       = "ChangeScheduled"
+	EventReasonUplinkMTUUnknown      = "UplinkMTUUnknown"
+)
+
+// Event emits an Event on the antrea-install CR and, on OpenShift, on the ClusterOperator.
+func (status *StatusManager) Event(eventType, reason, message string) {
+	if status.recorder == nil {
+		return
+	}
+	for _, obj := range status.eventObjects(status) {
+		status.recorder.Event(obj, eventType, reason, message)
+	}
+}
+
+// Warning emits a Warning Event, see Event.
+func (status *StatusManager) Warning(reason, message string) {
+	status.Event(corev1.EventTypeWarning, reason, message)
+}
+
+// Normal emits a Normal Event, see Event.
+func (status *StatusManager) Normal(reason, message string) {
+	status.Event(corev1.EventTypeNormal, reason, message)
+}
+
+// antreaInstallReference returns the reference of the antrea-install CR the Events are emitted on.
+// It is cached, as several Events are emitted per reconciliation, and refreshed whenever the
+// status of the CR is patched.
+func (status *StatusManager) antreaInstallReference() *corev1.ObjectReference {
+	status.refMutex.Lock()
+	ref := status.antreaInstallRef
+	status.refMutex.Unlock()
+	if ref != nil {
+		return ref
+	}
+	antreaInstall := &operatorv1.AntreaInstall{}
+	if err := status.client.Get(context.TODO(), types.NamespacedName{Namespace: operatortypes.OperatorNameSpace, Name: operatortypes.OperatorConfigName}, antreaInstall); err != nil {
+		log.Error(err, "failed to get AntreaInstall for event")
+		return nil
+	}
+	return status.cacheAntreaInstallReference(antreaInstall)
+}
+
+// clusterOperatorReference returns the reference of the ClusterOperator the Events are emitted on,
+// which is cached as the one of the antrea-install CR.
+func (status *StatusManager) clusterOperatorReference() *corev1.ObjectReference {
+	status.refMutex.Lock()
+	ref := status.clusterOperatorRef
+	status.refMutex.Unlock()
+	if ref != nil {
+		return ref
+	}
+	co := &configv1.ClusterOperator{}
+	if err := status.client.Get(context.TODO(), types.NamespacedName{Name: status.name}, co); err != nil {
+		log.Error(err, "failed to get ClusterOperator for event")
+		return nil
+	}
+	return status.cacheClusterOperatorReference(co)
+}
+
+// cacheAntreaInstallReference caches the reference of antreaInstall, or drops the cached one when
+// the CR is not found, so that the Events of a recreated CR are not emitted on the deleted one.
+func (status *StatusManager) cacheAntreaInstallReference(antreaInstall *operatorv1.AntreaInstall) *corev1.ObjectReference {
+	var ref *corev1.ObjectReference
+	if antreaInstall != nil {
+		ref = objectReference(antreaInstall, operatorv1.GroupVersion.WithKind("AntreaInstall"))
+	}
+	status.refMutex.Lock()
+	defer status.refMutex.Unlock()
+	status.antreaInstallRef = ref
+	return ref
+}
+
+func (status *StatusManager) cacheClusterOperatorReference(co *configv1.ClusterOperator) *corev1.ObjectReference {
+	ref := objectReference(co, configv1.GroupVersion.WithKind("ClusterOperator"))
+	status.refMutex.Lock()
+	defer status.refMutex.Unlock()
+	status.clusterOperatorRef = ref
+	return ref
+}
+
+func objectReference(obj metav1.Object, gvk schema.GroupVersionKind) *corev1.ObjectReference {
+	apiVersion, kind := gvk.ToAPIVersionAndKind()
+	return &corev1.ObjectReference{
+		APIVersion: apiVersion,
+		Kind:       kind,
+		Namespace:  obj.GetNamespace(),
+		Name:       obj.GetName(),
+		UID:        obj.GetUID(),
+	}
+}
+
+func (adaptor *StatusK8s) eventObjects(status *StatusManager) []runtime.Object {
+	if ref := status.antreaInstallReference(); ref != nil {
+		return []runtime.Object{ref}
+	}
+	return nil
+}
+
+func (adaptor *StatusOc) eventObjects(status *StatusManager) []runtime.Object {
+	var objs []runtime.Object
+	if ref := status.antreaInstallReference(); ref != nil {
+		objs = append(objs, ref)
+	}
+	if ref := status.clusterOperatorReference(); ref != nil {
+		objs = append(objs, ref)
+	}
+	return objs
+}
+
+func (adaptor *StatusPending) eventObjects(status *StatusManager) []runtime.Object {
+	return nil
+}
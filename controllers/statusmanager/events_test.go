@@ -0,0 +1,170 @@
+/* Copyright © 2026 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package statusmanager
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	configv1 "github.com/openshift/api/config/v1"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	operatorv1 "github.com/vmware/antrea-operator-for-kubernetes/api/v1"
+	operatortypes "github.com/vmware/antrea-operator-for-kubernetes/controllers/types"
+)
+
+// countingClient counts the Gets of each kind.
+type countingClient struct {
+	client.Client
+	gets map[string]int
+}
+
+func (c *countingClient) Get(ctx context.Context, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+	c.gets[fmt.Sprintf("%T", obj)]++
+	return c.Client.Get(ctx, key, obj, opts...)
+}
+
+func newTestStatusManager(g *WithT, objs ...client.Object) (*StatusManager, *countingClient, *record.FakeRecorder) {
+	scheme := runtime.NewScheme()
+	g.Expect(clientgoscheme.AddToScheme(scheme)).Should(Succeed())
+	g.Expect(configv1.AddToScheme(scheme)).Should(Succeed())
+	g.Expect(operatorv1.AddToScheme(scheme)).Should(Succeed())
+	c := &countingClient{Client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(objs...).Build(), gets: map[string]int{}}
+	recorder := record.NewFakeRecorder(100)
+	recorder.IncludeObject = true
+	return New(c, nil, recorder, operatortypes.AntreaClusterOperatorName, operatortypes.OperatorNameSpace, "test"), c, recorder
+}
+
+func receivedEvents(recorder *record.FakeRecorder) []string {
+	var events []string
+	for {
+		select {
+		case event := <-recorder.Events:
+			events = append(events, event)
+		default:
+			return events
+		}
+	}
+}
+
+var antreaInstall = &operatorv1.AntreaInstall{
+	ObjectMeta: metav1.ObjectMeta{Namespace: operatortypes.OperatorNameSpace, Name: operatortypes.OperatorConfigName, UID: "antrea-install-uid"},
+}
+
+func TestEvent(t *testing.T) {
+	g := NewGomegaWithT(t)
+	antreaInstallObject := " involvedObject{kind=AntreaInstall,apiVersion=operator.antrea.vmware.com/v1}"
+	clusterOperatorObject := " involvedObject{kind=ClusterOperator,apiVersion=config.openshift.io/v1}"
+
+	// No Event is emitted until the platform is known.
+	status, c, recorder := newTestStatusManager(g, antreaInstall.DeepCopy())
+	status.Normal(EventReasonConfigFilled, "Filled default configurations")
+	g.Expect(receivedEvents(recorder)).Should(BeEmpty())
+
+	// The reference of the antrea-install CR is cached.
+	status.Adaptor = &StatusK8s{}
+	status.Normal(EventReasonConfigFilled, "Filled default configurations")
+	status.Warning(EventReasonApplyFailed, "Failed to apply")
+	g.Expect(receivedEvents(recorder)).Should(Equal([]string{
+		"Normal ConfigFilled Filled default configurations" + antreaInstallObject,
+		"Warning ApplyFailed Failed to apply" + antreaInstallObject,
+	}))
+	g.Expect(c.gets["*v1.AntreaInstall"]).Should(Equal(1))
+	g.Expect(status.antreaInstallRef.UID).Should(Equal(types.UID("antrea-install-uid")))
+
+	// A recreated CR is referenced once its status is patched.
+	g.Expect(c.Delete(context.TODO(), antreaInstall.DeepCopy())).Should(Succeed())
+	g.Expect(status.SetPreview(nil)).Should(Succeed())
+	g.Expect(status.antreaInstallRef).Should(BeNil())
+	recreated := antreaInstall.DeepCopy()
+	recreated.UID = "recreated-uid"
+	g.Expect(c.Create(context.TODO(), recreated)).Should(Succeed())
+	g.Expect(status.SetPreview(nil)).Should(Succeed())
+	g.Expect(status.antreaInstallRef.UID).Should(Equal(types.UID("recreated-uid")))
+
+	// On OpenShift, the Events are also emitted on the ClusterOperator.
+	co := &configv1.ClusterOperator{ObjectMeta: metav1.ObjectMeta{Name: operatortypes.AntreaClusterOperatorName}}
+	status, c, recorder = newTestStatusManager(g, antreaInstall.DeepCopy(), co)
+	status.Adaptor = &StatusOc{}
+	status.Normal(EventReasonObjectsApplied, "Applied 3 objects")
+	status.Normal(EventReasonObjectsApplied, "Applied 3 objects")
+	g.Expect(receivedEvents(recorder)).Should(Equal([]string{
+		"Normal ObjectsApplied Applied 3 objects" + antreaInstallObject,
+		"Normal ObjectsApplied Applied 3 objects" + clusterOperatorObject,
+		"Normal ObjectsApplied Applied 3 objects" + antreaInstallObject,
+		"Normal ObjectsApplied Applied 3 objects" + clusterOperatorObject,
+	}))
+	g.Expect(c.gets["*v1.AntreaInstall"]).Should(Equal(1))
+	g.Expect(c.gets["*v1.ClusterOperator"]).Should(Equal(1))
+}
+
+func TestRolloutHungEvent(t *testing.T) {
+	g := NewGomegaWithT(t)
+	dsName := types.NamespacedName{Namespace: operatortypes.AntreaNamespace, Name: operatortypes.AntreaAgentDaemonSetName}
+	ds := &appsv1.DaemonSet{
+		ObjectMeta: metav1.ObjectMeta{Namespace: dsName.Namespace, Name: dsName.Name},
+		Status:     appsv1.DaemonSetStatus{DesiredNumberScheduled: 2, UpdatedNumberScheduled: 1, NumberAvailable: 1},
+	}
+	// The rollout has not changed for longer than ProgressTimeout.
+	lastSeen, err := json.Marshal(podState{DaemonsetStates: []daemonsetState{{
+		NamespacedName: dsName,
+		LastSeenStatus: ds.Status,
+		LastChangeTime: time.Now().Add(-2 * ProgressTimeout),
+	}}})
+	g.Expect(err).ShouldNot(HaveOccurred())
+	co := &configv1.ClusterOperator{ObjectMeta: metav1.ObjectMeta{
+		Name:        operatortypes.AntreaClusterOperatorName,
+		Annotations: map[string]string{lastSeenAnnotation: string(lastSeen)},
+	}}
+	status, c, recorder := newTestStatusManager(g, antreaInstall.DeepCopy(), co, ds)
+	status.Adaptor = &StatusOc{}
+	status.SetDaemonSets([]types.NamespacedName{dsName})
+
+	hungEvents := func() []string {
+		var events []string
+		for _, event := range receivedEvents(recorder) {
+			var eventType, reason string
+			fmt.Sscan(event, &eventType, &reason)
+			g.Expect(reason).Should(Equal(EventReasonRolloutHung))
+			events = append(events, eventType+" "+reason)
+		}
+		return events
+	}
+
+	// The hung rollout is reported once, on both objects, and not on the next checks.
+	status.SetFromPods()
+	g.Expect(hungEvents()).Should(Equal([]string{"Warning RolloutHung", "Warning RolloutHung"}))
+	g.Expect(status.failing[RolloutHung]).ShouldNot(BeNil())
+	status.SetFromPods()
+	g.Expect(hungEvents()).Should(BeEmpty())
+
+	// The completed rollout clears the condition without Event.
+	ds.Status = appsv1.DaemonSetStatus{DesiredNumberScheduled: 2, UpdatedNumberScheduled: 2, NumberAvailable: 2}
+	g.Expect(c.Status().Update(context.TODO(), ds)).Should(Succeed())
+	status.SetFromPods()
+	g.Expect(hungEvents()).Should(BeEmpty())
+	g.Expect(status.failing[RolloutHung]).Should(BeNil())
+}
+
+func TestObjectReference(t *testing.T) {
+	g := NewGomegaWithT(t)
+	ref := objectReference(antreaInstall, schema.GroupVersionKind{Group: "operator.antrea.vmware.com", Version: "v1", Kind: "AntreaInstall"})
+	g.Expect(ref.APIVersion).Should(Equal("operator.antrea.vmware.com/v1"))
+	g.Expect(ref.Kind).Should(Equal("AntreaInstall"))
+	g.Expect(ref.Namespace).Should(Equal(operatortypes.OperatorNameSpace))
+	g.Expect(ref.Name).Should(Equal(operatortypes.OperatorConfigName))
+	g.Expect(ref.UID).Should(Equal(types.UID("antrea-install-uid")))
+}
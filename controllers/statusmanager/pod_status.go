@@ -20,13 +20,16 @@ import (
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/util/retry"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	configv1alpha1 "github.com/vmware/antrea-operator-for-kubernetes/api/config/v1alpha1"
+	"github.com/vmware/antrea-operator-for-kubernetes/controllers/metrics"
 )
 
-const (
-	// if a rollout has not made any progress by this time,
-	// mark ourselves as Degraded
-	ProgressTimeout = 10 * time.Minute
+// if a rollout has not made any progress by this time,
+// mark ourselves as Degraded
+var ProgressTimeout = configv1alpha1.DefaultProgressTimeout
 
+const (
 	// lastSeenAnnotation - the annotation where we stash our state
 	lastSeenAnnotation = "network.operator.openshift.io/last-seen-state"
 )
@@ -76,6 +79,7 @@ func (status *StatusManager) SetFromPods() {
 			reachedAvailableLevel = false
 			continue
 		}
+		metrics.SetRolloutProgress(dsName.Name, ds.Status.UpdatedNumberScheduled, ds.Status.DesiredNumberScheduled, ds.Status.NumberAvailable)
 
 		dsProgressing := false
 
@@ -120,6 +124,11 @@ func (status *StatusManager) SetFromPods() {
 			reachedAvailableLevel = false
 			continue
 		}
+		desiredReplicas := dep.Status.Replicas
+		if dep.Spec.Replicas != nil {
+			desiredReplicas = *dep.Spec.Replicas
+		}
+		metrics.SetRolloutProgress(depName.Name, dep.Status.UpdatedReplicas, desiredReplicas, dep.Status.AvailableReplicas)
 
 		depProgressing := false
 
@@ -161,7 +170,11 @@ func (status *StatusManager) SetFromPods() {
 	status.setConditions(progressing, reachedAvailableLevel)
 
 	if len(hung) > 0 {
-		status.setDegraded(RolloutHung, "RolloutHung", strings.Join(hung, "\n"))
+		msg := strings.Join(hung, "\n")
+		if status.failing[RolloutHung] == nil || status.failing[RolloutHung].Message != msg {
+			status.Warning(EventReasonRolloutHung, msg)
+		}
+		status.setDegraded(RolloutHung, "RolloutHung", msg)
 	} else {
 		status.setNotDegraded(RolloutHung)
 	}
@@ -255,3 +268,11 @@ func (adaptor *StatusOc) setLastPodState(status *StatusManager, dss map[types.Na
 func (adaptor *StatusK8s) setLastPodState(status *StatusManager, dss map[types.NamespacedName]daemonsetState, deps map[types.NamespacedName]deploymentState) error {
 	return nil
 }
+
+func (adaptor *StatusPending) getLastPodState(status *StatusManager) (map[types.NamespacedName]daemonsetState, map[types.NamespacedName]deploymentState) {
+	return map[types.NamespacedName]daemonsetState{}, map[types.NamespacedName]deploymentState{}
+}
+
+func (adaptor *StatusPending) setLastPodState(status *StatusManager, dss map[types.NamespacedName]daemonsetState, deps map[types.NamespacedName]deploymentState) error {
+	return nil
+}
@@ -5,7 +5,6 @@ package statusmanager
 
 import (
 	"context"
-	"errors"
 	"fmt"
 	"reflect"
 	"strings"
@@ -14,18 +13,21 @@ import (
 	configv1 "github.com/openshift/api/config/v1"
 	"github.com/openshift/library-go/pkg/config/clusteroperator/v1helpers"
 	"gopkg.in/yaml.v2"
+	corev1 "k8s.io/api/core/v1"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	uns "k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
 	"k8s.io/client-go/util/retry"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 
 	operatorv1 "github.com/vmware/antrea-operator-for-kubernetes/api/v1"
-	"github.com/vmware/antrea-operator-for-kubernetes/controllers/sharedinfo"
+	"github.com/vmware/antrea-operator-for-kubernetes/controllers/metrics"
 	operatortypes "github.com/vmware/antrea-operator-for-kubernetes/controllers/types"
 	"github.com/vmware/antrea-operator-for-kubernetes/internal/version"
 )
@@ -43,23 +45,60 @@ const (
 	maxStatusLevel
 )
 
+var statusLevelNames = [maxStatusLevel]string{
+	ClusterConfig:  "ClusterConfig",
+	OperatorConfig: "OperatorConfig",
+	PodDeployment:  "PodDeployment",
+	RolloutHung:    "RolloutHung",
+	ClusterNode:    "ClusterNode",
+}
+
+func (level StatusLevel) String() string {
+	if level < 0 || level >= maxStatusLevel {
+		return fmt.Sprintf("StatusLevel(%d)", int(level))
+	}
+	return statusLevelNames[level]
+}
+
+// The conditions of the changes gated by the Manual approval policy.
+const (
+	ConditionChangePending  configv1.ClusterStatusConditionType = "ChangePending"
+	ConditionChangeApproved configv1.ClusterStatusConditionType = "ChangeApproved"
+)
+
+// ConditionUplinkMTUUnknown is true while the MTU derived with defaultMTU auto assumes the default
+// uplink MTU, as no Node is annotated with its uplink MTU.
+const ConditionUplinkMTUUnknown configv1.ClusterStatusConditionType = "UplinkMTUUnknown"
+
 type Adaptor interface {
 	getLastPodState(status *StatusManager) (map[types.NamespacedName]daemonsetState, map[types.NamespacedName]deploymentState)
 	setLastPodState(status *StatusManager, dss map[types.NamespacedName]daemonsetState, deps map[types.NamespacedName]deploymentState) error
 	set(status *StatusManager, reachedAvailableLevel bool, conditions ...configv1.ClusterOperatorStatusCondition)
+	eventObjects(status *StatusManager) []runtime.Object
 }
 
 // Status coordinates changes to AntreaInstall.status and ClusterOperator.Status.
 type StatusManager struct {
 	sync.Mutex
 
-	client client.Client
-	mapper meta.RESTMapper
+	client   client.Client
+	mapper   meta.RESTMapper
+	recorder record.EventRecorder
+	// The references of the objects the Events are emitted on.
+	refMutex           sync.Mutex
+	antreaInstallRef   *corev1.ObjectReference
+	clusterOperatorRef *corev1.ObjectReference
 
 	name    string
 	version string
 
 	failing [maxStatusLevel]*configv1.ClusterOperatorStatusCondition
+	// approval holds the ChangePending and ChangeApproved conditions, which are only reported with
+	// the Manual approval policy.
+	approval []configv1.ClusterOperatorStatusCondition
+	// uplinkMTUUnknown holds the UplinkMTUUnknown condition, which is only reported while the MTU
+	// falls back to the default uplink MTU.
+	uplinkMTUUnknown *configv1.ClusterOperatorStatusCondition
 
 	daemonSets     []types.NamespacedName
 	deployments    []types.NamespacedName
@@ -74,24 +113,34 @@ type StatusK8s struct{}
 
 type StatusOc struct{}
 
-func New(client client.Client, mapper meta.RESTMapper, name, operatorNamespace, version string, sharedInfo *sharedinfo.SharedInfo) (*StatusManager, error) {
-	status := StatusManager{
+// StatusPending is used until the antrea-install CR, which selects the platform, has been found.
+type StatusPending struct{}
+
+// New creates a StatusManager with a StatusPending adaptor, SetPlatform must be called once the
+// platform is known.
+func New(client client.Client, mapper meta.RESTMapper, recorder record.EventRecorder, name, operatorNamespace, version string) *StatusManager {
+	return &StatusManager{
 		client:            client,
 		mapper:            mapper,
+		recorder:          recorder,
 		name:              name,
 		version:           version,
 		OperatorNamespace: operatorNamespace,
-		AdaptorName:       sharedInfo.AntreaPlatform,
-	}
-	switch sharedInfo.AntreaPlatform {
-	case "openshift":
-		status.Adaptor = &StatusOc{}
-	case "kubernetes":
-		status.Adaptor = &StatusK8s{}
-	default:
-		return nil, errors.New("invalid platform: platform should be openshift or kubernetes")
+		Adaptor:           &StatusPending{},
 	}
-	return &status, nil
+}
+
+// SetPlatform switches to the adaptor of the selected platform, publishes the current
+// conditions with it and records the platform in AntreaInstall.Status.
+func (status *StatusManager) SetPlatform(platformStatus *operatorv1.PlatformStatus, adaptor Adaptor) error {
+	status.Lock()
+	defer status.Unlock()
+	status.Adaptor = adaptor
+	status.AdaptorName = platformStatus.Name
+	status.syncDegraded()
+	return status.patchAntreaInstallStatus(func(antreaInstallStatus *operatorv1.AntreaInstallStatus) {
+		antreaInstallStatus.Platform = platformStatus
+	})
 }
 
 // deleteRelatedObjects checks for related objects attached to ClusterOperator and deletes
@@ -181,6 +230,17 @@ func (status *StatusManager) setClusterOperatorConditions(co *configv1.ClusterOp
 		}
 	}
 	status.CombineConditions(&co.Status.Conditions, conditions)
+	if len(status.approval) > 0 {
+		status.CombineConditions(&co.Status.Conditions, &status.approval)
+	} else {
+		v1helpers.RemoveStatusCondition(&co.Status.Conditions, ConditionChangePending)
+		v1helpers.RemoveStatusCondition(&co.Status.Conditions, ConditionChangeApproved)
+	}
+	if status.uplinkMTUUnknown != nil {
+		v1helpers.SetStatusCondition(&co.Status.Conditions, *status.uplinkMTUUnknown)
+	} else {
+		v1helpers.RemoveStatusCondition(&co.Status.Conditions, ConditionUplinkMTUUnknown)
+	}
 	progressingCondition := v1helpers.FindStatusCondition(co.Status.Conditions, configv1.OperatorProgressing)
 	availableCondition := v1helpers.FindStatusCondition(co.Status.Conditions, configv1.OperatorAvailable)
 	if availableCondition == nil && progressingCondition != nil && progressingCondition.Status == configv1.ConditionTrue {
@@ -224,6 +284,24 @@ func (adaptor *StatusK8s) set(status *StatusManager, reachedAvailableLevel bool,
 	}
 }
 
+// Set updates the ClusterOperator.Status with the provided conditions while the platform is not
+// known, if the ClusterOperator kind is served. Otherwise there is no object to report the
+// conditions on, and they are only logged.
+func (adaptor *StatusPending) set(status *StatusManager, reachedAvailableLevel bool, conditions ...configv1.ClusterOperatorStatusCondition) {
+	if status.clusterOperatorServed() {
+		(&StatusOc{}).set(status, reachedAvailableLevel, conditions...)
+		return
+	}
+	for _, condition := range conditions {
+		log.Info("AntreaInstall not found, not reporting condition", "type", condition.Type, "status", condition.Status, "reason", condition.Reason, "message", condition.Message)
+	}
+}
+
+func (status *StatusManager) clusterOperatorServed() bool {
+	_, err := status.mapper.RESTMapping(schema.GroupKind{Group: configv1.GroupName, Kind: "ClusterOperator"})
+	return err == nil
+}
+
 // Set updates the ClusterOperator.Status with the provided conditions for platform openshift.
 func (adaptor *StatusOc) set(status *StatusManager, reachedAvailableLevel bool, conditions ...configv1.ClusterOperatorStatusCondition) {
 	err := retry.RetryOnConflict(retry.DefaultBackoff, func() error {
@@ -233,6 +311,9 @@ func (adaptor *StatusOc) set(status *StatusManager, reachedAvailableLevel bool,
 		if err != nil && !isNotFound {
 			return err
 		}
+		if err == nil {
+			status.cacheClusterOperatorReference(co)
+		}
 		oldStatus := co.Status.DeepCopy()
 		status.deleteRelatedObjectsNotRendered(co)
 		if status.relatedObjects != nil {
@@ -250,6 +331,7 @@ func (adaptor *StatusOc) set(status *StatusManager, reachedAvailableLevel bool,
 			if err := status.client.Create(context.TODO(), co); err != nil {
 				return err
 			}
+			status.cacheClusterOperatorReference(co)
 			log.Info(fmt.Sprintf("Created ClusterOperator with conditions:\n%s", string(buf)))
 			return nil
 		}
@@ -266,18 +348,28 @@ func (adaptor *StatusOc) set(status *StatusManager, reachedAvailableLevel bool,
 }
 
 func (status *StatusManager) setAntreaInstallStatus(conditions *[]configv1.ClusterOperatorStatusCondition) error {
+	return status.patchAntreaInstallStatus(func(antreaInstallStatus *operatorv1.AntreaInstallStatus) {
+		antreaInstallStatus.Conditions = *conditions
+	})
+}
+
+// patchAntreaInstallStatus applies mutate to the status of the antrea-install CR and patches
+// the result. Fields which are not touched by mutate are left as they are.
+func (status *StatusManager) patchAntreaInstallStatus(mutate func(antreaInstallStatus *operatorv1.AntreaInstallStatus)) error {
 	antreaInstall := &operatorv1.AntreaInstall{}
 	err := status.client.Get(context.TODO(), types.NamespacedName{Namespace: operatortypes.OperatorNameSpace, Name: operatortypes.OperatorConfigName}, antreaInstall)
 	if err != nil {
 		if k8serrors.IsNotFound(err) {
 			log.Info("AntreaInstall not found, skipping set AntreaInstall status")
+			status.cacheAntreaInstallReference(nil)
 			return nil
 		}
 		log.Error(err, "failed to get AntreaInstall")
 		return err
 	}
+	status.cacheAntreaInstallReference(antreaInstall)
 	antreaInstallPatch := client.MergeFrom(antreaInstall.DeepCopy())
-	antreaInstall.Status.Conditions = *conditions
+	mutate(&antreaInstall.Status)
 	if err := status.client.Status().Patch(context.TODO(), antreaInstall, antreaInstallPatch); err != nil {
 		log.Error(err, "failed to set AntreaInstall")
 		return err
@@ -330,6 +422,7 @@ func (status *StatusManager) setDegraded(statusLevel StatusLevel, reason, messag
 		Reason:  reason,
 		Message: message,
 	}
+	metrics.SetDegraded(statusLevel.String(), true, reason)
 	status.syncDegraded()
 }
 
@@ -337,6 +430,7 @@ func (status *StatusManager) setNotDegraded(statusLevel StatusLevel) {
 	if status.failing[statusLevel] != nil {
 		status.failing[statusLevel] = nil
 	}
+	metrics.SetDegraded(statusLevel.String(), false, "")
 	status.syncDegraded()
 }
 
@@ -364,6 +458,105 @@ func (status *StatusManager) SetDeployments(deployments []types.NamespacedName)
 	status.deployments = deployments
 }
 
+// SetEffectiveConfig publishes the effective configurations in AntreaInstall.Status.
+func (status *StatusManager) SetEffectiveConfig(effectiveConfig *operatorv1.EffectiveConfig) error {
+	status.Lock()
+	defer status.Unlock()
+	return status.patchAntreaInstallStatus(func(antreaInstallStatus *operatorv1.AntreaInstallStatus) {
+		antreaInstallStatus.EffectiveConfig = effectiveConfig
+	})
+}
+
+// SetProfile records the distribution profile in use in AntreaInstall.Status.
+func (status *StatusManager) SetProfile(profile string) error {
+	status.Lock()
+	defer status.Unlock()
+	return status.patchAntreaInstallStatus(func(antreaInstallStatus *operatorv1.AntreaInstallStatus) {
+		if antreaInstallStatus.Platform == nil {
+			antreaInstallStatus.Platform = &operatorv1.PlatformStatus{}
+		}
+		antreaInstallStatus.Platform.Profile = profile
+	})
+}
+
+// SetClusterNetwork records the discovered cluster network in AntreaInstall.Status.
+func (status *StatusManager) SetClusterNetwork(clusterNetwork *operatorv1.ClusterNetworkStatus) error {
+	status.Lock()
+	defer status.Unlock()
+	return status.patchAntreaInstallStatus(func(antreaInstallStatus *operatorv1.AntreaInstallStatus) {
+		antreaInstallStatus.ClusterNetwork = clusterNetwork
+	})
+}
+
+// SetPreview records the changes the operator would make in dry-run mode in AntreaInstall.Status,
+// or clears them when preview is nil.
+func (status *StatusManager) SetPreview(preview *operatorv1.PreviewStatus) error {
+	status.Lock()
+	defer status.Unlock()
+	return status.patchAntreaInstallStatus(func(antreaInstallStatus *operatorv1.AntreaInstallStatus) {
+		antreaInstallStatus.Preview = preview
+	})
+}
+
+// SetScheduledChange records the disruptive change held until a maintenance window in
+// AntreaInstall.Status, or clears it when scheduledChange is nil.
+func (status *StatusManager) SetScheduledChange(scheduledChange *operatorv1.ScheduledChangeStatus) error {
+	status.Lock()
+	defer status.Unlock()
+	return status.patchAntreaInstallStatus(func(antreaInstallStatus *operatorv1.AntreaInstallStatus) {
+		antreaInstallStatus.ScheduledChange = scheduledChange
+	})
+}
+
+// SetApprovalConditions sets the given ChangePending or ChangeApproved conditions, and publishes
+// them with the current conditions.
+func (status *StatusManager) SetApprovalConditions(conditions ...configv1.ClusterOperatorStatusCondition) {
+	status.Lock()
+	defer status.Unlock()
+	for _, condition := range conditions {
+		v1helpers.SetStatusCondition(&status.approval, condition)
+	}
+	status.syncDegraded()
+}
+
+// ClearApprovalConditions removes the ChangePending and ChangeApproved conditions, when the
+// approval policy is not Manual.
+func (status *StatusManager) ClearApprovalConditions() {
+	status.Lock()
+	defer status.Unlock()
+	if len(status.approval) == 0 {
+		return
+	}
+	status.approval = nil
+	status.syncDegraded()
+}
+
+// SetUplinkMTUUnknown sets the UplinkMTUUnknown condition with the given message, and publishes it
+// with the current conditions.
+func (status *StatusManager) SetUplinkMTUUnknown(message string) {
+	status.Lock()
+	defer status.Unlock()
+	status.uplinkMTUUnknown = &configv1.ClusterOperatorStatusCondition{
+		Type:    ConditionUplinkMTUUnknown,
+		Status:  configv1.ConditionTrue,
+		Reason:  "NoUplinkMTUAnnotation",
+		Message: message,
+	}
+	status.syncDegraded()
+}
+
+// ClearUplinkMTUUnknown removes the UplinkMTUUnknown condition, when the MTU does not fall back to
+// the default uplink MTU.
+func (status *StatusManager) ClearUplinkMTUUnknown() {
+	status.Lock()
+	defer status.Unlock()
+	if status.uplinkMTUUnknown == nil {
+		return
+	}
+	status.uplinkMTUUnknown = nil
+	status.syncDegraded()
+}
+
 func (status *StatusManager) SetRelatedObjects(relatedObjects []configv1.ObjectReference) {
 	status.Lock()
 	defer status.Unlock()
@@ -0,0 +1,42 @@
+/* Copyright © 2026 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package statusmanager
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	configv1 "github.com/openshift/api/config/v1"
+	"github.com/openshift/library-go/pkg/config/clusteroperator/v1helpers"
+	"k8s.io/apimachinery/pkg/types"
+
+	operatorv1 "github.com/vmware/antrea-operator-for-kubernetes/api/v1"
+	operatortypes "github.com/vmware/antrea-operator-for-kubernetes/controllers/types"
+)
+
+func TestUplinkMTUUnknown(t *testing.T) {
+	g := NewGomegaWithT(t)
+	status, c, _ := newTestStatusManager(g, antreaInstall.DeepCopy())
+	status.Adaptor = &StatusK8s{}
+	conditions := func() []operatorv1.InstallCondition {
+		cr := &operatorv1.AntreaInstall{}
+		g.Expect(c.Get(context.TODO(), types.NamespacedName{Namespace: operatortypes.OperatorNameSpace, Name: operatortypes.OperatorConfigName}, cr)).Should(Succeed())
+		return cr.Status.Conditions
+	}
+
+	status.SetUplinkMTUUnknown("No Node is annotated")
+	condition := v1helpers.FindStatusCondition(conditions(), ConditionUplinkMTUUnknown)
+	g.Expect(condition).ShouldNot(BeNil())
+	g.Expect(condition.Status).Should(Equal(configv1.ConditionTrue))
+	g.Expect(condition.Message).Should(Equal("No Node is annotated"))
+
+	// The condition is kept with the other conditions.
+	status.SetDegraded(OperatorConfig, "InternalError", "failed")
+	g.Expect(v1helpers.FindStatusCondition(conditions(), ConditionUplinkMTUUnknown)).ShouldNot(BeNil())
+	g.Expect(v1helpers.FindStatusCondition(conditions(), configv1.OperatorDegraded).Status).Should(Equal(configv1.ConditionTrue))
+
+	status.ClearUplinkMTUUnknown()
+	g.Expect(v1helpers.FindStatusCondition(conditions(), ConditionUplinkMTUUnknown)).Should(BeNil())
+}
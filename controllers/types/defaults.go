@@ -3,8 +3,15 @@
 
 package types
 
+import (
+	configv1alpha1 "github.com/vmware/antrea-operator-for-kubernetes/api/config/v1alpha1"
+)
+
 const (
 	DefaultAntreaImage     = "antrea/antrea-ubi:latest"
-	DefaultManifestDir     = "antrea-manifest"
+	DefaultManifestDir     = configv1alpha1.DefaultManifestDir
 	DefaultMTU         int = 1450
+
+	DefaultOperatorNameSpace  = configv1alpha1.DefaultWatchNamespace
+	DefaultOperatorConfigName = configv1alpha1.DefaultAntreaInstallName
 )
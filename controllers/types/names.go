@@ -17,12 +17,11 @@ const (
 	AntreaControllerConfigOption    = "antrea-controller.conf"
 	AntreaControllerConfigRenderKey = "AntreaControllerConfig"
 
-	ServiceCIDROption = "serviceCIDR"
-	DefaultMTUOption  = "defaultMTU"
+	ServiceCIDROption   = "serviceCIDR"
+	ServiceCIDRv6Option = "serviceCIDRv6"
+	DefaultMTUOption    = "defaultMTU"
 
-	OperatorNameSpace          = "antrea-operator"
 	ClusterConfigName          = "cluster"
-	OperatorConfigName         = "antrea-install"
 	ClusterOperatorNetworkName = "cluster"
 
 	AntreaNamespace                = "kube-system"
@@ -30,6 +29,43 @@ const (
 	AntreaControllerDeploymentName = "antrea-controller"
 	AntreaConfigMapName            = "antrea-config"
 
-	CNIConfDirRenderKey = "CNIConfDir"
-	CNIBinDirRenderKey  = "CNIBinDir"
+	EffectiveConfigMapName = "antrea-install-effective-config"
+	AntreaImageOption      = "antrea-image"
+	ManifestSourceOption   = "manifest-source"
+	AntreaVersionOption    = "antrea-version"
+	TrafficOption          = "traffic"
+
+	CNIConfDirRenderKey         = "CNIConfDir"
+	CNIBinDirRenderKey          = "CNIBinDir"
+	CNIChainingRenderKey        = "CNIChaining"
+	CNIPrimaryConfListRenderKey = "CNIPrimaryConfList"
+	InstallCNIConfDirRenderKey  = "InstallCNIConfDir"
+
+	// ChainedInstallCNIConfDir is where install-cni writes the antrea conflist when Antrea is
+	// chained to the primary CNI, out of the CNI configuration directory of the container runtime.
+	ChainedInstallCNIConfDir = "/var/run/antrea/cni/net.d"
+
+	// UplinkMTUAnnotation is set on the Nodes, by the administrator or a preflight probe, to the
+	// MTU of their uplink interface. It is used when spec.defaultMTU is auto.
+	UplinkMTUAnnotation = "operator.antrea.vmware.com/uplink-mtu"
+
+	// ApprovedRevisionAnnotation is set on the antrea-install CR to the revision of the pending
+	// change to approve it, when spec.approvalPolicy is Manual.
+	ApprovedRevisionAnnotation = "operator.antrea.vmware.com/approved-revision"
+
+	// ApprovedAtAnnotation is set by the operator on the antrea-install CR to the approved revision
+	// and the time it observed its approval, as <revision>@<RFC 3339 time>. The approval expires
+	// after spec.approvalTTL.
+	ApprovedAtAnnotation = "operator.antrea.vmware.com/approved-at"
+
+	// ApplyNowAnnotation is set on the antrea-install CR to apply a change deferred to the next
+	// maintenance window at once. It is removed once the change is applied.
+	ApplyNowAnnotation = "operator.antrea.vmware.com/apply-now"
+)
+
+// The following names can be overridden by the operator configuration file.
+var (
+	OperatorNameSpace  = DefaultOperatorNameSpace
+	OperatorConfigName = DefaultOperatorConfigName
+	ManifestDir        = DefaultManifestDir
 )
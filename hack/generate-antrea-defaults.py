@@ -0,0 +1,45 @@
+#!/usr/bin/env python3
+
+import argparse
+import os
+import re
+import yaml
+
+parser = argparse.ArgumentParser(description='Extract the default configurations of Antrea, which describe the options of an Antrea version')
+parser.add_argument('yaml_files', metavar='file', type=argparse.FileType('r'), nargs='+',
+                    help='List of yaml files for processing')
+parser.add_argument('--output-dir', required=True, help='Directory of the manifests of the Antrea version')
+
+args = parser.parse_args()
+
+# The feature gates are all commented out in the default configurations, uncomment them so that
+# they describe the type of the gates.
+feature_gate = re.compile(r'^#\s+(\w+):\s*(true|false)\s*$')
+
+
+def uncomment_feature_gates(conf):
+    lines = []
+    in_feature_gates = False
+    for line in conf.splitlines():
+        if line.startswith('featureGates:'):
+            in_feature_gates = True
+        elif line and not line.startswith('#') and not line.startswith(' '):
+            in_feature_gates = False
+        elif in_feature_gates:
+            match = feature_gate.match(line)
+            if match:
+                line = '  %s: %s' % (match.group(1), match.group(2))
+        lines.append(line)
+    return '\n'.join(lines) + '\n'
+
+
+for f in args.yaml_files:
+    docs = yaml.load_all(f, Loader=yaml.Loader)
+
+    for doc in docs:
+        if doc and doc.get('kind') == 'ConfigMap' and doc.get('metadata', {}).get('name') == 'antrea-config':
+            for name in ['antrea-agent.conf', 'antrea-controller.conf']:
+                with open(os.path.join(args.output_dir, name), 'w') as out:
+                    out.write(uncomment_feature_gates(doc.get('data', {}).get(name, "")))
+
+exit(0)
@@ -7,7 +7,9 @@ function echoerr {
 }
 
 _usage="Usage: $0 [--version <antrea version>] [--platform[openshift|kubernetes]] [--help|-h]
-Generate a YAML manifest for Antrea using Kustomize and print it to stdout.
+Generate the YAML manifest and the default configurations of an Antrea release using Kustomize, in
+antrea-manifest/v<version>.
+The manifests of the other bundled versions are kept.
         --version                     Antrea version for use for manifest generation
         --platform                    Target platform for operator yamls
         --help, -h                    Print this message and exit
@@ -24,7 +26,7 @@ function print_help {
     echoerr "Try '$0 --help' for more information."
 }
 
-ANTREA_VERSION=${ANTREA_VERSION:-"main"}
+ANTREA_VERSION=${ANTREA_VERSION:-""}
 ANTREA_PLATFORM=${ANTREA_PLATFORM:-"kubernetes"}
 
 while [[ $# -gt 0 ]]
@@ -55,6 +57,13 @@ if [ "$ANTREA_PLATFORM" != "openshift" ] && [ "$ANTREA_PLATFORM" != "kubernetes"
     print_usage
 fi
 
+# The manifests are bundled per Antrea release, selected by spec.antreaVersion.
+if [ -z "$ANTREA_VERSION" ]; then
+    echoerr "An Antrea release version is required, such as --version 1.14.1"
+    print_help
+    exit 1
+fi
+
 THIS_DIR="$( cd "$( dirname "${BASH_SOURCE[0]}" )" >/dev/null 2>&1 && pwd )"
 
 if [ -z "$KUSTOMIZE" ]; then
@@ -66,16 +75,11 @@ fi
 ### Get Antrea repository
 ANTREA_DIR=$(mktemp -d /tmp/antrea.XXXXXXX)
 
-if [ "$ANTREA_VERSION" == "main" ]; then
-    ANTREA_URL="https://github.com/antrea-io/antrea/archive/refs/heads/main.tar.gz"
-    ANTREA_ROOT=$ANTREA_DIR/antrea-main
-else
-    ANTREA_URL="https://github.com/antrea-io/antrea/archive/refs/tags/v${ANTREA_VERSION}.tar.gz"
-    ANTREA_ROOT=$ANTREA_DIR/antrea-$ANTREA_VERSION
-fi
+ANTREA_URL="https://github.com/antrea-io/antrea/archive/refs/tags/v${ANTREA_VERSION}.tar.gz"
+ANTREA_ROOT=$ANTREA_DIR/antrea-$ANTREA_VERSION
 curl -sL $ANTREA_URL | tar xz -C $ANTREA_DIR
 
-### Generate antrea-manifest/antrea.yml
+### Generate antrea-manifest/v$ANTREA_VERSION/antrea.yml
 
 KUSTOMIZATION_DIR=$ANTREA_ROOT/build/yamls
 
@@ -112,15 +116,23 @@ $KUSTOMIZE edit add patch --path controllerOsRelease.yml
 $KUSTOMIZE edit add patch --path controllerImage.yml
 $KUSTOMIZE edit add patch --path agentImagePullPolicy.yml
 $KUSTOMIZE edit add patch --path controllerImagePullPolicy.yml
+$KUSTOMIZE edit add patch --path cniChaining.yml
 
-$KUSTOMIZE build | sed 's/\\"\({{.*}}\)\\"/"\1"/; '"s/'\({{.*}}\)'/\1/" > $THIS_DIR/../antrea-manifest/antrea.yml
+MANIFEST_DIR=$THIS_DIR/../antrea-manifest/v$ANTREA_VERSION
+mkdir -p $MANIFEST_DIR
+$KUSTOMIZE build | sed 's/\\"\({{.*}}\)\\"/"\1"/; '"s/'\({{.*}}\)'/\1/" > $MANIFEST_DIR/antrea.yml
 
 popd > /dev/null
 
-### Generate config/rbac/role.yaml
-# generate-role-yaml.py requires PyYAML, install it just in case that it's missing
+### Generate the default configurations of antrea-manifest/v$ANTREA_VERSION, which the operator
+### validates the configurations of this version against.
+# The generation scripts require PyYAML, install it just in case that it's missing
 pip3 -q install PyYAML
+$THIS_DIR/generate-antrea-defaults.py --output-dir $MANIFEST_DIR $ANTREA_ROOT/build/yamls/antrea.yml
 
+### Generate config/rbac/role.yaml
+# The role is generated from the Antrea release being bundled, which should be the latest one, as
+# the permissions of Antrea are not expected to shrink across releases.
 ROLE_FILES="$THIS_DIR/../config/rbac/role_base.yaml $ANTREA_ROOT/build/yamls/antrea.yml"
 
 if [ "$ANTREA_PLATFORM" == "openshift" ]; then
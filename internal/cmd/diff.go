@@ -0,0 +1,220 @@
+/* Copyright © 2026 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package cmd
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	uns "k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+
+	operatorv1 "github.com/vmware/antrea-operator-for-kubernetes/api/v1"
+	"github.com/vmware/antrea-operator-for-kubernetes/controllers"
+	configutil "github.com/vmware/antrea-operator-for-kubernetes/controllers/config"
+	"github.com/vmware/antrea-operator-for-kubernetes/controllers/manifestsource"
+	"github.com/vmware/antrea-operator-for-kubernetes/controllers/platform"
+	"github.com/vmware/antrea-operator-for-kubernetes/controllers/preview"
+	"github.com/vmware/antrea-operator-for-kubernetes/controllers/types"
+)
+
+// DiffOptions are the inputs of Diff.
+type DiffOptions struct {
+	// AntreaInstallFile is the YAML file of the proposed AntreaInstall.
+	AntreaInstallFile string
+	// Platform is openshift or kubernetes. When empty, spec.antreaPlatform is used, and the
+	// platform is detected when it is auto.
+	Platform string
+	// Distribution overrides the detected distribution, whose profile is used when spec.profile
+	// is auto.
+	Distribution string
+	// ManifestDir is the directory of the Antrea manifest templates, with one subdirectory per
+	// bundled Antrea version. The templates of spec.manifestSource are used when it is set.
+	ManifestDir string
+}
+
+// DiffResult is the outcome of Diff.
+type DiffResult struct {
+	Objects []*preview.ObjectDiff
+	// Deleted are the names of the objects the controller would delete, as they are not rendered
+	// anymore.
+	Deleted  []string
+	Restarts preview.Restarts
+	// TrafficModeChangeErr is set when the controller would refuse the traffic mode change.
+	TrafficModeChangeErr error
+	// VersionChangeErr is set when the controller would refuse the Antrea version change.
+	VersionChangeErr error
+}
+
+// Diff renders the proposed AntreaInstall of opts the way the controller does, against the
+// cluster network, the Nodes and the applied configurations read with c, and compares the
+// rendered objects with the live ones.
+func Diff(ctx context.Context, c client.Client, discoveryClient discovery.DiscoveryInterface, opts *DiffOptions, warnings io.Writer) (*DiffResult, error) {
+	operConfig := &operatorv1.AntreaInstall{}
+	if err := decodeFile(opts.AntreaInstallFile, "AntreaInstall", operConfig); err != nil {
+		return nil, err
+	}
+	if operConfig.Namespace == "" {
+		operConfig.Namespace = types.DefaultOperatorNameSpace
+	}
+	// Own the effective configuration ConfigMap by the live AntreaInstall, as the controller does.
+	liveOperConfig := &operatorv1.AntreaInstall{}
+	if err := c.Get(ctx, client.ObjectKeyFromObject(operConfig), liveOperConfig); err == nil {
+		operConfig.UID = liveOperConfig.UID
+	} else if !apierrors.IsNotFound(err) {
+		return nil, fmt.Errorf("failed to get AntreaInstall %s/%s: %v", operConfig.Namespace, operConfig.Name, err)
+	}
+
+	platformName := opts.Platform
+	if platformName == "" {
+		platformName = operConfig.Spec.AntreaPlatform
+	}
+	selected, _, err := controllers.ResolvePlatform(ctx, platform.NewDetector(discoveryClient, c), platformName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to detect platform: %v", err)
+	}
+	distribution := opts.Distribution
+	if distribution == "" {
+		distribution = selected.Distribution
+	}
+	network, err := controllers.LoadPlatformNetwork(ctx, selected.Name, c)
+	if err != nil {
+		return nil, err
+	}
+	config, err := controllers.NewPlatformConfig(selected.Name, operConfig.Spec.Profile, distribution, network, opts.ManifestDir)
+	if err != nil {
+		return nil, err
+	}
+	manifestDir := opts.ManifestDir
+	if source := operConfig.Spec.ManifestSource; source != nil {
+		if manifestDir, err = manifestsource.Resolve(ctx, c, operConfig.Namespace, source); err != nil {
+			return nil, fmt.Errorf("failed to load manifest source: %v", err)
+		}
+	}
+	in := &renderInput{
+		operConfig:            operConfig,
+		clusterConfig:         network.ClusterConfig,
+		operatorNetwork:       network.OperatorNetwork,
+		config:                config,
+		ownedByClusterNetwork: controllers.OwnedByClusterNetwork(selected.Name),
+		manifestDir:           manifestDir,
+	}
+	state, err := controllers.GetClusterState(ctx, c)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get cluster state: %v", err)
+	}
+	appliedConfig, err := controllers.ReadAppliedOperConfig(c)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get applied configurations: %v", err)
+	}
+	state.AppliedConfig = appliedConfig
+	in.state = state
+
+	objs, err := renderObjects(in, warnings)
+	if err != nil {
+		return nil, err
+	}
+	result := &DiffResult{}
+	for _, obj := range objs {
+		live := &uns.Unstructured{}
+		live.SetGroupVersionKind(obj.GroupVersionKind())
+		if err := c.Get(ctx, client.ObjectKeyFromObject(obj), live); err != nil {
+			if !apierrors.IsNotFound(err) && !meta.IsNoMatchError(err) {
+				return nil, fmt.Errorf("failed to get %s %s: %v", obj.GetKind(), obj.GetName(), err)
+			}
+			live = nil
+		}
+		result.Objects = append(result.Objects, preview.DiffObject(obj, live))
+	}
+	// As the controller, delete the related objects of the ClusterOperator which are not rendered
+	// anymore on OpenShift.
+	if controllers.OwnedByClusterNetwork(selected.Name) {
+		if result.Deleted, err = controllers.DeletedObjects(ctx, c, c.RESTMapper(), objs); err != nil {
+			return nil, err
+		}
+	}
+
+	result.TrafficModeChangeErr = configutil.ValidateTrafficModeChange(appliedConfig, operConfig)
+	result.VersionChangeErr = configutil.ValidateVersionChange(appliedConfig, operConfig)
+	result.Restarts = preview.PredictRestarts(appliedConfig, operConfig)
+	return result, nil
+}
+
+// restConfig loads the kubeconfig file at path, or the default configuration of controller-runtime.
+func restConfig(path string) (*rest.Config, error) {
+	if path != "" {
+		return clientcmd.BuildConfigFromFlags("", path)
+	}
+	return ctrl.GetConfig()
+}
+
+// RunDiff runs the diff subcommand with args, and returns the exit code.
+func RunDiff(args []string, stdout, stderr io.Writer) int {
+	opts := &DiffOptions{}
+	var kubeconfig string
+	flags := flag.NewFlagSet("diff", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	flags.Usage = func() {
+		fmt.Fprintf(stderr, "Usage: %s diff --antrea-install FILE [flags]\n\n", os.Args[0])
+		fmt.Fprintln(stderr, "Prints the changes applying an AntreaInstall would make to the cluster, and the Antrea Pods it would restart.")
+		flags.PrintDefaults()
+	}
+	flags.StringVar(&opts.AntreaInstallFile, "antrea-install", "", "The proposed AntreaInstall YAML file.")
+	flags.StringVar(&opts.Platform, "platform", "",
+		"The platform: openshift or kubernetes. Defaults to spec.antreaPlatform, and is detected when it is auto.")
+	flags.StringVar(&opts.Distribution, "distribution", "",
+		"The distribution whose profile is used when spec.profile is auto. Detected when empty.")
+	flags.StringVar(&opts.ManifestDir, "manifest-dir", types.DefaultManifestDir, "The directory of the Antrea manifest templates, with one subdirectory per Antrea version.")
+	flags.StringVar(&kubeconfig, "kubeconfig", "", "The kubeconfig file of the cluster. Defaults to $KUBECONFIG, or the in-cluster configuration.")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if opts.AntreaInstallFile == "" || flags.NArg() > 0 {
+		flags.Usage()
+		return 2
+	}
+	ctrl.SetLogger(zap.New(zap.WriteTo(stderr)))
+
+	cfg, err := restConfig(kubeconfig)
+	if err != nil {
+		fmt.Fprintf(stderr, "error: failed to load kubeconfig: %v\n", err)
+		return 1
+	}
+	c, err := client.New(cfg, client.Options{Scheme: scheme})
+	if err != nil {
+		fmt.Fprintf(stderr, "error: failed to create client: %v\n", err)
+		return 1
+	}
+	discoveryClient, err := discovery.NewDiscoveryClientForConfig(cfg)
+	if err != nil {
+		fmt.Fprintf(stderr, "error: failed to create discovery client: %v\n", err)
+		return 1
+	}
+
+	result, err := Diff(context.TODO(), c, discoveryClient, opts, stderr)
+	if err != nil {
+		fmt.Fprintf(stderr, "error: %s\n", strings.TrimSpace(err.Error()))
+		return 1
+	}
+	preview.WriteDiffs(stdout, result.Objects, result.Deleted)
+	fmt.Fprintln(stdout, result.Restarts)
+	if result.TrafficModeChangeErr != nil {
+		fmt.Fprintf(stdout, "The operator would refuse the change: %v\n", result.TrafficModeChangeErr)
+	}
+	if result.VersionChangeErr != nil {
+		fmt.Fprintf(stdout, "The operator would refuse the change: %v\n", result.VersionChangeErr)
+	}
+	return 0
+}
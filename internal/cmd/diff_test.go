@@ -0,0 +1,225 @@
+/* Copyright © 2026 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	configv1 "github.com/openshift/api/config/v1"
+	ocoperv1 "github.com/openshift/api/operator/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	discoveryfake "k8s.io/client-go/discovery/fake"
+	clienttesting "k8s.io/client-go/testing"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/vmware/antrea-operator-for-kubernetes/controllers/manifestsource"
+	"github.com/vmware/antrea-operator-for-kubernetes/controllers/platform"
+	"github.com/vmware/antrea-operator-for-kubernetes/controllers/preview"
+	"github.com/vmware/antrea-operator-for-kubernetes/controllers/types"
+)
+
+var node = &corev1.Node{
+	ObjectMeta: metav1.ObjectMeta{Name: "node1"},
+	Spec:       corev1.NodeSpec{PodCIDR: "10.10.0.0/24"},
+	Status: corev1.NodeStatus{
+		Addresses: []corev1.NodeAddress{{Type: corev1.NodeInternalIP, Address: "192.168.1.10"}},
+	},
+}
+
+// writeAntreaInstall writes the sample AntreaInstall on Kubernetes, with the old and new string
+// pairs of replacements replaced, and returns its path.
+func writeAntreaInstall(g *WithT, dir string, replacements ...string) string {
+	data, err := os.ReadFile(sampleFile)
+	g.Expect(err).ShouldNot(HaveOccurred())
+	replacements = append(replacements, "antreaPlatform: openshift", "antreaPlatform: kubernetes")
+	sample := strings.NewReplacer(replacements...).Replace(string(data))
+	path := filepath.Join(dir, "antrea-install.yaml")
+	g.Expect(os.WriteFile(path, []byte(sample), 0644)).Should(Succeed())
+	return path
+}
+
+func TestDiff(t *testing.T) {
+	g := NewGomegaWithT(t)
+	ctx := context.TODO()
+	discoveryClient := &discoveryfake.FakeDiscovery{Fake: &clienttesting.Fake{}}
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(node).Build()
+	dir := t.TempDir()
+
+	// Antrea is not installed.
+	opts := &DiffOptions{AntreaInstallFile: writeAntreaInstall(g, dir), ManifestDir: manifestDir}
+	result, err := Diff(ctx, c, discoveryClient, opts, &bytes.Buffer{})
+	g.Expect(err).ShouldNot(HaveOccurred())
+	g.Expect(result.Restarts).Should(Equal(preview.Restarts{Install: true}))
+	g.Expect(result.TrafficModeChangeErr).ShouldNot(HaveOccurred())
+	g.Expect(result.VersionChangeErr).ShouldNot(HaveOccurred())
+	g.Expect(result.Objects).ShouldNot(BeEmpty())
+	for _, diff := range result.Objects {
+		g.Expect(diff.Created).Should(BeTrue(), diff.String())
+	}
+
+	// Apply the objects rendered against the cluster, as the controller does.
+	objs, err := Render(&RenderOptions{AntreaInstallFile: opts.AntreaInstallFile, ManifestDir: manifestDir, Distribution: platform.DistributionGeneric}, &bytes.Buffer{})
+	g.Expect(err).ShouldNot(HaveOccurred())
+	for _, obj := range objs {
+		g.Expect(c.Create(ctx, obj)).Should(Succeed(), obj.GetName())
+	}
+	result, err = Diff(ctx, c, discoveryClient, opts, &bytes.Buffer{})
+	g.Expect(err).ShouldNot(HaveOccurred())
+	g.Expect(result.Restarts).Should(Equal(preview.Restarts{}))
+	for _, diff := range result.Objects {
+		g.Expect(diff.Changed()).Should(BeFalse(), "%s: %v", diff, diff.Changes)
+	}
+
+	// Change the agent configuration.
+	opts.AntreaInstallFile = writeAntreaInstall(g, dir, "defaultMTU: 0", "defaultMTU: 1400")
+	result, err = Diff(ctx, c, discoveryClient, opts, &bytes.Buffer{})
+	g.Expect(err).ShouldNot(HaveOccurred())
+	g.Expect(result.Restarts).Should(Equal(preview.Restarts{AgentRestart: true}))
+	var changed []string
+	for _, diff := range result.Objects {
+		if diff.Changed() {
+			changed = append(changed, diff.String())
+		}
+	}
+	g.Expect(changed).Should(ContainElement(preview.ObjectName("ConfigMap", types.AntreaNamespace, types.AntreaConfigMapName)))
+
+	// Change the traffic mode of the running installation.
+	opts.AntreaInstallFile = writeAntreaInstall(g, dir, `trafficEncapMode: "encap"`, `trafficEncapMode: "noEncap"`)
+	result, err = Diff(ctx, c, discoveryClient, opts, &bytes.Buffer{})
+	g.Expect(err).ShouldNot(HaveOccurred())
+	g.Expect(result.TrafficModeChangeErr).Should(HaveOccurred())
+}
+
+func TestDiffManifestSource(t *testing.T) {
+	g := NewGomegaWithT(t)
+	ctx := context.TODO()
+	manifestsource.CacheDir = t.TempDir()
+	data, err := os.ReadFile(filepath.Join(manifestDir, "v1.14.1", "antrea.yml"))
+	g.Expect(err).ShouldNot(HaveOccurred())
+	configMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Namespace: types.DefaultOperatorNameSpace, Name: "antrea-manifests"},
+		Data:       map[string]string{"antrea.yml": string(data)},
+	}
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(node, configMap).Build()
+	discoveryClient := &discoveryfake.FakeDiscovery{Fake: &clienttesting.Fake{}}
+	digest := manifestsource.Files{"antrea.yml": data}.Digest()
+
+	// The templates of the ConfigMap are rendered instead of the bundled ones.
+	source := fmt.Sprintf("spec:\n  manifestSource:\n    configMap: %s\n    digest: %s\n", configMap.Name, digest)
+	opts := &DiffOptions{AntreaInstallFile: writeAntreaInstall(g, t.TempDir(), "spec:\n", source), ManifestDir: filepath.Join(t.TempDir(), "missing")}
+	result, err := Diff(ctx, c, discoveryClient, opts, &bytes.Buffer{})
+	g.Expect(err).ShouldNot(HaveOccurred())
+	g.Expect(result.Objects).ShouldNot(BeEmpty())
+
+	source = fmt.Sprintf("spec:\n  manifestSource:\n    configMap: %s\n    digest: sha256:%s\n", configMap.Name, strings.Repeat("0", 64))
+	opts.AntreaInstallFile = writeAntreaInstall(g, t.TempDir(), "spec:\n", source)
+	_, err = Diff(ctx, c, discoveryClient, opts, &bytes.Buffer{})
+	g.Expect(err).Should(MatchError(ContainSubstring("the digest of the templates is " + digest)))
+}
+
+// TestDiffDeleted reports the related objects of the ClusterOperator which are not rendered
+// anymore, as the controller deletes them on OpenShift.
+func TestDiffDeleted(t *testing.T) {
+	g := NewGomegaWithT(t)
+	ctx := context.TODO()
+	clusterConfig := &configv1.Network{}
+	g.Expect(decodeFile(networkFile, "Network", clusterConfig)).Should(Succeed())
+	operatorNetwork := &ocoperv1.Network{ObjectMeta: metav1.ObjectMeta{Name: types.ClusterOperatorNetworkName}}
+	co := &configv1.ClusterOperator{
+		ObjectMeta: metav1.ObjectMeta{Name: types.AntreaClusterOperatorName},
+		Status: configv1.ClusterOperatorStatus{RelatedObjects: []configv1.ObjectReference{
+			{Resource: "serviceaccounts", Namespace: types.AntreaNamespace, Name: "antrea-agent"},
+			{Resource: "serviceaccounts", Namespace: types.AntreaNamespace, Name: "antrea-stale"},
+			{Resource: "namespaces", Name: "antrea-stale"},
+		}},
+	}
+	discoveryClient := &discoveryfake.FakeDiscovery{Fake: &clienttesting.Fake{}}
+	opts := &DiffOptions{AntreaInstallFile: writeAntreaInstall(g, t.TempDir()), Platform: platform.OpenShift, ManifestDir: manifestDir}
+	// The related objects are matched by resource, which the default mapper of the fake client
+	// does not know.
+	mapper := meta.NewDefaultRESTMapper(scheme.PrioritizedVersionsAllGroups())
+	for gvk := range scheme.AllKnownTypes() {
+		mapper.Add(gvk, meta.RESTScopeNamespace)
+	}
+	newClient := func(objs ...client.Object) client.Client {
+		return fake.NewClientBuilder().WithScheme(scheme).WithRESTMapper(mapper).WithObjects(objs...).Build()
+	}
+
+	c := newClient(node, clusterConfig, operatorNetwork, co)
+	result, err := Diff(ctx, c, discoveryClient, opts, &bytes.Buffer{})
+	g.Expect(err).ShouldNot(HaveOccurred())
+	g.Expect(result.Deleted).Should(Equal([]string{preview.ObjectName("ServiceAccount", types.AntreaNamespace, "antrea-stale")}))
+
+	// Nothing is deleted without ClusterOperator.
+	c = newClient(node, clusterConfig, operatorNetwork)
+	result, err = Diff(ctx, c, discoveryClient, opts, &bytes.Buffer{})
+	g.Expect(err).ShouldNot(HaveOccurred())
+	g.Expect(result.Deleted).Should(BeEmpty())
+
+	// The related objects are only deleted on OpenShift.
+	opts.Platform = platform.Kubernetes
+	c = newClient(node, co)
+	result, err = Diff(ctx, c, discoveryClient, opts, &bytes.Buffer{})
+	g.Expect(err).ShouldNot(HaveOccurred())
+	g.Expect(result.Deleted).Should(BeEmpty())
+}
+
+func TestDiffPlatform(t *testing.T) {
+	g := NewGomegaWithT(t)
+	ctx := context.TODO()
+	dir := t.TempDir()
+	opts := &DiffOptions{AntreaInstallFile: writeAntreaInstall(g, dir), ManifestDir: manifestDir}
+
+	// The platform is detected when it is auto, the cluster Network is required on OpenShift.
+	opts.Platform = platform.Auto
+	openShiftDiscovery := &discoveryfake.FakeDiscovery{Fake: &clienttesting.Fake{Resources: []*metav1.APIResourceList{{
+		GroupVersion: "config.openshift.io/v1",
+		APIResources: []metav1.APIResource{{Kind: "Network"}, {Kind: "ClusterOperator"}},
+	}}}}
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(node).Build()
+	_, err := Diff(ctx, c, openShiftDiscovery, opts, &bytes.Buffer{})
+	g.Expect(err).Should(MatchError(ContainSubstring("failed to get cluster Network")))
+
+	opts.Platform = "windows"
+	_, err = Diff(ctx, c, openShiftDiscovery, opts, &bytes.Buffer{})
+	g.Expect(err).Should(MatchError(ContainSubstring(`invalid platform "windows"`)))
+}
+
+func TestRunDiff(t *testing.T) {
+	g := NewGomegaWithT(t)
+	kubeconfig := filepath.Join(t.TempDir(), "kubeconfig")
+	g.Expect(os.WriteFile(kubeconfig, []byte("apiVersion: v1\nkind: Config\n"), 0644)).Should(Succeed())
+
+	for _, tc := range []struct {
+		name         string
+		args         []string
+		expectedCode int
+		expectedErr  string
+	}{
+		{name: "missing AntreaInstall", expectedCode: 2, expectedErr: "Usage:"},
+		{name: "extra argument", args: []string{"--antrea-install", sampleFile, "extra"}, expectedCode: 2, expectedErr: "Usage:"},
+		{name: "unknown flag", args: []string{"--antrea-install", sampleFile, "--foo"}, expectedCode: 2, expectedErr: "flag provided but not defined"},
+		{
+			name:         "invalid kubeconfig",
+			args:         []string{"--antrea-install", sampleFile, "--kubeconfig", kubeconfig},
+			expectedCode: 1,
+			expectedErr:  "failed to load kubeconfig",
+		},
+	} {
+		var stdout, stderr bytes.Buffer
+		code := RunDiff(tc.args, &stdout, &stderr)
+		g.Expect(code).Should(Equal(tc.expectedCode), tc.name)
+		g.Expect(stderr.String()).Should(ContainSubstring(tc.expectedErr), tc.name)
+	}
+}
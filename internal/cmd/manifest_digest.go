@@ -0,0 +1,42 @@
+/* Copyright © 2026 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package cmd
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/vmware/antrea-operator-for-kubernetes/controllers/manifestsource"
+)
+
+// RunManifestDigest runs the manifest-digest subcommand with args, and returns the exit code.
+func RunManifestDigest(args []string, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("manifest-digest", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	flags.Usage = func() {
+		fmt.Fprintf(stderr, "Usage: %s manifest-digest DIR\n\n", os.Args[0])
+		fmt.Fprintln(stderr, "Prints the digest of the Antrea manifest templates of DIR, for spec.manifestSource.digest.")
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if flags.NArg() != 1 {
+		flags.Usage()
+		return 2
+	}
+	files, err := manifestsource.ReadDir(flags.Arg(0))
+	if err != nil {
+		fmt.Fprintf(stderr, "error: failed to read templates: %v\n", err)
+		return 1
+	}
+	if len(files) == 0 {
+		fmt.Fprintf(stderr, "error: no template found in %s\n", flags.Arg(0))
+		return 1
+	}
+	fmt.Fprintln(stdout, files.Digest())
+	return 0
+}
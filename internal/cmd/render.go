@@ -0,0 +1,318 @@
+/* Copyright © 2026 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+// Package cmd implements the subcommands of the operator binary: render runs without a cluster
+// connection, diff compares the rendered objects with the live ones, and manifest-digest prints
+// the digest of manifest templates.
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/ghodss/yaml"
+	configv1 "github.com/openshift/api/config/v1"
+	ocoperv1 "github.com/openshift/api/operator/v1"
+	"github.com/openshift/cluster-network-operator/pkg/render"
+	uns "k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+
+	operatorv1 "github.com/vmware/antrea-operator-for-kubernetes/api/v1"
+	"github.com/vmware/antrea-operator-for-kubernetes/controllers"
+	"github.com/vmware/antrea-operator-for-kubernetes/controllers/antreaversion"
+	configutil "github.com/vmware/antrea-operator-for-kubernetes/controllers/config"
+	"github.com/vmware/antrea-operator-for-kubernetes/controllers/manifestsource"
+	"github.com/vmware/antrea-operator-for-kubernetes/controllers/platform"
+	"github.com/vmware/antrea-operator-for-kubernetes/controllers/types"
+)
+
+const (
+	OutputYAML = "yaml"
+	OutputJSON = "json"
+)
+
+var scheme = runtime.NewScheme()
+
+func init() {
+	utilruntime.Must(clientgoscheme.AddToScheme(scheme))
+	utilruntime.Must(configv1.Install(scheme))
+	utilruntime.Must(ocoperv1.Install(scheme))
+	utilruntime.Must(operatorv1.AddToScheme(scheme))
+}
+
+// RenderOptions are the inputs of Render, which stand for the objects the controller reads from
+// the cluster.
+type RenderOptions struct {
+	// AntreaInstallFile is the YAML file of the AntreaInstall.
+	AntreaInstallFile string
+	// NetworkFile is the YAML file of the config.openshift.io Network, which is required on
+	// OpenShift and stands for the discovered cluster network on Kubernetes.
+	NetworkFile string
+	// OperatorNetworkFile is the YAML file of the operator.openshift.io Network, on OpenShift.
+	OperatorNetworkFile string
+	// Platform is openshift or kubernetes. When empty, spec.antreaPlatform is used, and
+	// kubernetes when it is auto.
+	Platform string
+	// Distribution is the distribution whose profile is used when spec.profile is auto.
+	Distribution string
+	// NodeIPAM tells whether antrea-controller allocates the Pod CIDRs of the Nodes on Kubernetes.
+	NodeIPAM bool
+	// ManifestDir is the directory of the Antrea manifest templates, with one subdirectory per
+	// bundled Antrea version.
+	ManifestDir string
+	// ManifestSourceDir is the directory of the templates of spec.manifestSource, which are
+	// verified against its digest. It is required for a ConfigMap source, which cannot be read
+	// without a cluster connection. The directory and OCI sources are loaded as the controller
+	// does when it is empty.
+	ManifestSourceDir string
+}
+
+// Render runs the configuration pipeline of the controller on the AntreaInstall of opts, and
+// returns the objects the controller would apply, in order. The warnings of the schema
+// validation are written to warnings.
+func Render(opts *RenderOptions, warnings io.Writer) ([]*uns.Unstructured, error) {
+	operConfig := &operatorv1.AntreaInstall{}
+	if err := decodeFile(opts.AntreaInstallFile, "AntreaInstall", operConfig); err != nil {
+		return nil, err
+	}
+	if operConfig.Namespace == "" {
+		operConfig.Namespace = types.DefaultOperatorNameSpace
+	}
+	network := &controllers.PlatformNetwork{NodeIPAM: opts.NodeIPAM}
+	if opts.NetworkFile != "" {
+		network.ClusterConfig = &configv1.Network{}
+		if err := decodeFile(opts.NetworkFile, "Network", network.ClusterConfig); err != nil {
+			return nil, err
+		}
+	}
+	if opts.OperatorNetworkFile != "" {
+		network.OperatorNetwork = &ocoperv1.Network{}
+		if err := decodeFile(opts.OperatorNetworkFile, "Network", network.OperatorNetwork); err != nil {
+			return nil, err
+		}
+	}
+
+	platformName := opts.Platform
+	if platformName == "" {
+		platformName = operConfig.Spec.AntreaPlatform
+	}
+	if platformName == "" || platformName == platform.Auto {
+		platformName = platform.Kubernetes
+	}
+	config, err := controllers.NewPlatformConfig(platformName, operConfig.Spec.Profile, opts.Distribution, network, opts.ManifestDir)
+	if err != nil {
+		return nil, err
+	}
+	manifestDir, err := resolveManifestSource(operConfig.Spec.ManifestSource, opts)
+	if err != nil {
+		return nil, err
+	}
+	return renderObjects(&renderInput{
+		operConfig:            operConfig,
+		clusterConfig:         network.ClusterConfig,
+		operatorNetwork:       network.OperatorNetwork,
+		config:                config,
+		ownedByClusterNetwork: controllers.OwnedByClusterNetwork(platformName),
+		manifestDir:           manifestDir,
+	}, warnings)
+}
+
+// resolveManifestSource returns the directory of the templates to render: the bundled manifests of
+// opts.ManifestDir without source, and the verified templates of source otherwise.
+func resolveManifestSource(source *operatorv1.ManifestSource, opts *RenderOptions) (string, error) {
+	if source == nil {
+		return opts.ManifestDir, nil
+	}
+	if err := manifestsource.Validate(source); err != nil {
+		return "", err
+	}
+	if opts.ManifestSourceDir != "" {
+		files, err := manifestsource.ReadDir(opts.ManifestSourceDir)
+		if err != nil {
+			return "", fmt.Errorf("failed to read the manifest source directory: %v", err)
+		}
+		if digest := files.Digest(); digest != source.Digest {
+			return "", fmt.Errorf("the digest of the templates of %s is %s, expected %s", opts.ManifestSourceDir, digest, source.Digest)
+		}
+		return opts.ManifestSourceDir, nil
+	}
+	if source.ConfigMap != "" {
+		return "", fmt.Errorf("the templates of ConfigMap %s cannot be read without a cluster connection, set --manifest-source-dir to a directory holding them", source.ConfigMap)
+	}
+	manifestDir, err := manifestsource.Resolve(context.TODO(), nil, "", source)
+	if err != nil {
+		return "", fmt.Errorf("failed to load manifest source: %v", err)
+	}
+	return manifestDir, nil
+}
+
+// renderInput holds the objects the configuration pipeline reads, from files or from the cluster.
+type renderInput struct {
+	operConfig      *operatorv1.AntreaInstall
+	clusterConfig   *configv1.Network
+	operatorNetwork *ocoperv1.Network
+	config          configutil.Config
+	// ownedByClusterNetwork tells whether the Antrea objects are owned by the cluster Network.
+	ownedByClusterNetwork bool
+	// state is nil when the cluster is not available, which skips the validations against the
+	// addresses in use.
+	state       *configutil.ClusterState
+	manifestDir string
+}
+
+// renderObjects runs the configuration pipeline of applyConfig, filling in.operConfig in place,
+// and returns the objects to apply followed by the effective configuration ConfigMap.
+func renderObjects(in *renderInput, warnings io.Writer) ([]*uns.Unstructured, error) {
+	schemaErrs, schemaWarnings := configutil.ValidateSchema(in.operConfig, in.manifestDir)
+	for _, warning := range schemaWarnings {
+		fmt.Fprintf(warnings, "warning: %s\n", warning)
+	}
+	if len(schemaErrs) > 0 {
+		return nil, fmt.Errorf("the operator configuration is invalid:\n%v", configutil.ToValidationErrors(schemaErrs))
+	}
+	if err := in.config.FillConfigs(in.clusterConfig, in.operConfig, in.state); err != nil {
+		return nil, fmt.Errorf("failed to fill configurations: %v", err)
+	}
+	if err := in.config.ValidateConfig(in.clusterConfig, in.operConfig, in.state); err != nil {
+		return nil, fmt.Errorf("the operator configuration is invalid:\n%v", err)
+	}
+	renderData, err := in.config.GenerateRenderData(in.operatorNetwork, in.operConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate render data: %v", err)
+	}
+	// The manifest directory stands for spec.manifestSource when it is set, as the controller only
+	// selects the bundled manifests of spec.antreaVersion otherwise.
+	manifestDir := in.manifestDir
+	if in.operConfig.Spec.ManifestSource == nil {
+		manifestDir, err = antreaversion.ManifestDir(in.manifestDir, in.operConfig.Spec.AntreaVersion)
+		if err != nil {
+			return nil, err
+		}
+	}
+	objs, err := render.RenderDir(manifestDir, renderData)
+	if err != nil {
+		return nil, fmt.Errorf("failed to render manifests: %v", err)
+	}
+
+	// As the controller, set the cluster Network as the owner of the Antrea objects when the
+	// platform requires it, and publish the effective configurations.
+	if in.ownedByClusterNetwork {
+		for _, obj := range objs {
+			if err := controllerutil.SetControllerReference(in.clusterConfig, obj, scheme); err != nil {
+				return nil, fmt.Errorf("failed to set owner reference of %s %s: %v", obj.GetKind(), obj.GetName(), err)
+			}
+		}
+	}
+	configMap := configutil.BuildEffectiveConfigMap(in.operConfig)
+	if err := controllerutil.SetControllerReference(in.operConfig, configMap, scheme); err != nil {
+		return nil, fmt.Errorf("failed to set owner reference of ConfigMap %s: %v", configMap.Name, err)
+	}
+	configMapObj, err := runtime.DefaultUnstructuredConverter.ToUnstructured(configMap)
+	if err != nil {
+		return nil, err
+	}
+	return append(objs, &uns.Unstructured{Object: configMapObj}), nil
+}
+
+// decodeFile decodes a YAML or JSON file into obj, which must be of the given kind.
+func decodeFile(path, kind string, obj runtime.Object) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	if err := yaml.Unmarshal(data, obj); err != nil {
+		return fmt.Errorf("failed to decode %s: %v", path, err)
+	}
+	if gvk := obj.GetObjectKind().GroupVersionKind(); gvk.Kind != kind {
+		return fmt.Errorf("%s holds a %q object, expected %s", path, gvk.Kind, kind)
+	}
+	return nil
+}
+
+// WriteObjects writes objs as a multi-document YAML stream, or as a JSON List.
+func WriteObjects(w io.Writer, objs []*uns.Unstructured, output string) error {
+	switch output {
+	case OutputYAML:
+		for _, obj := range objs {
+			data, err := yaml.Marshal(obj.Object)
+			if err != nil {
+				return err
+			}
+			if _, err := fmt.Fprintf(w, "---\n%s", data); err != nil {
+				return err
+			}
+		}
+		return nil
+	case OutputJSON:
+		items := make([]interface{}, 0, len(objs))
+		for _, obj := range objs {
+			items = append(items, obj.Object)
+		}
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(map[string]interface{}{"apiVersion": "v1", "kind": "List", "items": items})
+	default:
+		return fmt.Errorf("invalid output format %q, available formats are: %s, %s", output, OutputYAML, OutputJSON)
+	}
+}
+
+// addRenderFlags registers the flags of RenderOptions.
+func addRenderFlags(flags *flag.FlagSet, opts *RenderOptions) {
+	flags.StringVar(&opts.AntreaInstallFile, "antrea-install", "", "The AntreaInstall YAML file.")
+	flags.StringVar(&opts.NetworkFile, "network", "",
+		"The config.openshift.io Network YAML file of the cluster network, required on openshift.")
+	flags.StringVar(&opts.OperatorNetworkFile, "operator-network", "", "The operator.openshift.io Network YAML file, on openshift.")
+	flags.StringVar(&opts.Platform, "platform", "",
+		"The platform: openshift or kubernetes. Defaults to spec.antreaPlatform, and to kubernetes when it is auto.")
+	flags.StringVar(&opts.Distribution, "distribution", platform.DistributionGeneric,
+		"The distribution whose profile is used when spec.profile is auto.")
+	flags.BoolVar(&opts.NodeIPAM, "node-ipam", false,
+		"Whether antrea-controller allocates the Node Pod CIDRs from the cluster network, on kubernetes.")
+	flags.StringVar(&opts.ManifestDir, "manifest-dir", types.DefaultManifestDir, "The directory of the Antrea manifest templates, with one subdirectory per Antrea version.")
+	flags.StringVar(&opts.ManifestSourceDir, "manifest-source-dir", "",
+		"The directory of the templates of spec.manifestSource, verified against its digest. Required for a configMap source.")
+}
+
+// RunRender runs the render subcommand with args, and returns the exit code.
+func RunRender(args []string, stdout, stderr io.Writer) int {
+	opts := &RenderOptions{}
+	var output string
+	flags := flag.NewFlagSet("render", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	flags.Usage = func() {
+		fmt.Fprintf(stderr, "Usage: %s render --antrea-install FILE [flags]\n\n", os.Args[0])
+		fmt.Fprintln(stderr, "Prints the objects the operator would apply for an AntreaInstall, without a cluster connection.")
+		flags.PrintDefaults()
+	}
+	addRenderFlags(flags, opts)
+	flags.StringVar(&output, "output", OutputYAML, "The output format: yaml or json.")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if opts.AntreaInstallFile == "" || flags.NArg() > 0 {
+		flags.Usage()
+		return 2
+	}
+	ctrl.SetLogger(zap.New(zap.WriteTo(stderr)))
+
+	objs, err := Render(opts, stderr)
+	if err != nil {
+		fmt.Fprintf(stderr, "error: %s\n", strings.TrimSpace(err.Error()))
+		return 1
+	}
+	if err := WriteObjects(stdout, objs, output); err != nil {
+		fmt.Fprintf(stderr, "error: %v\n", err)
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,193 @@
+/* Copyright © 2026 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package cmd
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ghodss/yaml"
+	. "github.com/onsi/gomega"
+	uns "k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	operatorv1 "github.com/vmware/antrea-operator-for-kubernetes/api/v1"
+	"github.com/vmware/antrea-operator-for-kubernetes/controllers/manifestsource"
+	"github.com/vmware/antrea-operator-for-kubernetes/controllers/platform"
+	"github.com/vmware/antrea-operator-for-kubernetes/controllers/types"
+)
+
+var update = flag.Bool("update", false, "Update the golden files of the tests.")
+
+const (
+	sampleFile  = "../../config/samples/operator_v1_antreainstall.yaml"
+	manifestDir = "../../antrea-manifest"
+	networkFile = "testdata/network.yaml"
+	goldenFile  = "testdata/render-kubernetes.golden.yaml"
+)
+
+func findObject(objs []*uns.Unstructured, kind, name string) *uns.Unstructured {
+	for _, obj := range objs {
+		if obj.GetKind() == kind && obj.GetName() == name {
+			return obj
+		}
+	}
+	return nil
+}
+
+// TestRenderGolden renders the sample AntreaInstall on Kubernetes, and compares the output with
+// the golden file. Run the test with -update to regenerate it after a manifest change.
+func TestRenderGolden(t *testing.T) {
+	g := NewGomegaWithT(t)
+	var stdout, stderr bytes.Buffer
+	code := RunRender([]string{"--antrea-install", sampleFile, "--platform", platform.Kubernetes, "--manifest-dir", manifestDir}, &stdout, &stderr)
+	g.Expect(code).Should(Equal(0), stderr.String())
+	if *update {
+		g.Expect(os.WriteFile(goldenFile, stdout.Bytes(), 0644)).Should(Succeed())
+	}
+	expected, err := os.ReadFile(goldenFile)
+	g.Expect(err).ShouldNot(HaveOccurred())
+	g.Expect(stdout.String()).Should(Equal(string(expected)))
+}
+
+func TestRender(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	objs, err := Render(&RenderOptions{AntreaInstallFile: sampleFile, NetworkFile: networkFile, ManifestDir: manifestDir}, &bytes.Buffer{})
+	g.Expect(err).ShouldNot(HaveOccurred())
+	daemonSet := findObject(objs, "DaemonSet", types.AntreaAgentDaemonSetName)
+	g.Expect(daemonSet).ShouldNot(BeNil())
+	owners := daemonSet.GetOwnerReferences()
+	g.Expect(owners).Should(HaveLen(1))
+	g.Expect(owners[0].Kind).Should(Equal("Network"))
+	g.Expect(owners[0].Name).Should(Equal(types.ClusterConfigName))
+	configMap := objs[len(objs)-1]
+	g.Expect(configMap.GetKind()).Should(Equal("ConfigMap"))
+	g.Expect(configMap.GetOwnerReferences()[0].Kind).Should(Equal("AntreaInstall"))
+
+	objs, err = Render(&RenderOptions{AntreaInstallFile: sampleFile, Platform: platform.Kubernetes, ManifestDir: manifestDir}, &bytes.Buffer{})
+	g.Expect(err).ShouldNot(HaveOccurred())
+	g.Expect(findObject(objs, "DaemonSet", types.AntreaAgentDaemonSetName).GetOwnerReferences()).Should(BeEmpty())
+
+	_, err = Render(&RenderOptions{AntreaInstallFile: sampleFile, ManifestDir: manifestDir}, &bytes.Buffer{})
+	g.Expect(err).Should(MatchError(ContainSubstring("the cluster Network is required on openshift")))
+	_, err = Render(&RenderOptions{AntreaInstallFile: sampleFile, Platform: "windows", ManifestDir: manifestDir}, &bytes.Buffer{})
+	g.Expect(err).Should(MatchError(ContainSubstring(`invalid platform "windows"`)))
+	_, err = Render(&RenderOptions{AntreaInstallFile: networkFile, ManifestDir: manifestDir}, &bytes.Buffer{})
+	g.Expect(err).Should(MatchError(ContainSubstring("expected AntreaInstall")))
+}
+
+func TestRunRender(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	for _, tc := range []struct {
+		name         string
+		args         []string
+		expectedCode int
+		expectedErr  string
+	}{
+		{name: "missing AntreaInstall", expectedCode: 2, expectedErr: "Usage:"},
+		{name: "extra argument", args: []string{"--antrea-install", sampleFile, "extra"}, expectedCode: 2, expectedErr: "Usage:"},
+		{name: "unknown flag", args: []string{"--antrea-install", sampleFile, "--foo"}, expectedCode: 2, expectedErr: "flag provided but not defined"},
+		{
+			name:         "missing file",
+			args:         []string{"--antrea-install", filepath.Join(t.TempDir(), "antrea-install.yaml")},
+			expectedCode: 1,
+			expectedErr:  "no such file or directory",
+		},
+		{
+			name:         "invalid output",
+			args:         []string{"--antrea-install", sampleFile, "--network", networkFile, "--manifest-dir", manifestDir, "--output", "toml"},
+			expectedCode: 1,
+			expectedErr:  `invalid output format "toml"`,
+		},
+		{name: "json output", args: []string{"--antrea-install", sampleFile, "--network", networkFile, "--manifest-dir", manifestDir, "--output", "json"}},
+	} {
+		var stdout, stderr bytes.Buffer
+		code := RunRender(tc.args, &stdout, &stderr)
+		g.Expect(code).Should(Equal(tc.expectedCode), tc.name)
+		if tc.expectedErr != "" {
+			g.Expect(stderr.String()).Should(ContainSubstring(tc.expectedErr), tc.name)
+			continue
+		}
+		g.Expect(stdout.String()).Should(HavePrefix("{\n  \"apiVersion\": \"v1\",\n  \"items\": ["), tc.name)
+	}
+}
+
+// writeManifestSource writes an AntreaInstall loading its templates from source, and the directory
+// holding its one template, and returns their paths.
+func writeManifestSource(g *WithT, dir string, source *operatorv1.ManifestSource) (string, string) {
+	templateDir := filepath.Join(dir, "templates")
+	g.Expect(os.MkdirAll(templateDir, 0755)).Should(Succeed())
+	template := "apiVersion: v1\nkind: ServiceAccount\nmetadata:\n  name: antrea-custom\n  namespace: kube-system\n"
+	g.Expect(os.WriteFile(filepath.Join(templateDir, "antrea.yml"), []byte(template), 0644)).Should(Succeed())
+	files, err := manifestsource.ReadDir(templateDir)
+	g.Expect(err).ShouldNot(HaveOccurred())
+
+	data, err := os.ReadFile(sampleFile)
+	g.Expect(err).ShouldNot(HaveOccurred())
+	operConfig := &operatorv1.AntreaInstall{}
+	g.Expect(yaml.Unmarshal(data, operConfig)).Should(Succeed())
+	if source.Directory != "" {
+		source.Directory = templateDir
+	}
+	if source.Digest == "" {
+		source.Digest = files.Digest()
+	}
+	operConfig.Spec.ManifestSource = source
+	data, err = yaml.Marshal(operConfig)
+	g.Expect(err).ShouldNot(HaveOccurred())
+	antreaInstallFile := filepath.Join(dir, "antrea-install.yaml")
+	g.Expect(os.WriteFile(antreaInstallFile, data, 0644)).Should(Succeed())
+	return antreaInstallFile, templateDir
+}
+
+// TestRenderManifestSource renders the templates of spec.manifestSource instead of the bundled
+// ones.
+func TestRenderManifestSource(t *testing.T) {
+	g := NewGomegaWithT(t)
+	cacheDir := manifestsource.CacheDir
+	manifestsource.CacheDir = t.TempDir()
+	defer func() { manifestsource.CacheDir = cacheDir }()
+	otherDigest := "sha256:" + strings.Repeat("0", 64)
+
+	for _, tc := range []struct {
+		name string
+		// source is completed with the template directory, and its digest when it is empty.
+		source      *operatorv1.ManifestSource
+		sourceDir   bool
+		expectedErr string
+	}{
+		{name: "directory", source: &operatorv1.ManifestSource{Directory: "templates"}},
+		{name: "directory with the templates", source: &operatorv1.ManifestSource{Directory: "templates"}, sourceDir: true},
+		{name: "directory digest mismatch", source: &operatorv1.ManifestSource{Directory: "templates", Digest: otherDigest}, expectedErr: "the digest of the templates is"},
+		{name: "configMap", source: &operatorv1.ManifestSource{ConfigMap: "antrea-manifests"}, sourceDir: true},
+		{name: "configMap without templates", source: &operatorv1.ManifestSource{ConfigMap: "antrea-manifests"}, expectedErr: "set --manifest-source-dir"},
+		{
+			name:        "configMap digest mismatch",
+			source:      &operatorv1.ManifestSource{ConfigMap: "antrea-manifests", Digest: otherDigest},
+			sourceDir:   true,
+			expectedErr: "expected " + otherDigest,
+		},
+		{name: "invalid source", source: &operatorv1.ManifestSource{ConfigMap: "antrea-manifests", Directory: "templates"}, expectedErr: "exactly one of"},
+	} {
+		antreaInstallFile, templateDir := writeManifestSource(g, t.TempDir(), tc.source)
+		opts := &RenderOptions{AntreaInstallFile: antreaInstallFile, Platform: platform.Kubernetes, ManifestDir: manifestDir}
+		if tc.sourceDir {
+			opts.ManifestSourceDir = templateDir
+		}
+		objs, err := Render(opts, &bytes.Buffer{})
+		if tc.expectedErr != "" {
+			g.Expect(err).Should(MatchError(ContainSubstring(tc.expectedErr)), tc.name)
+			continue
+		}
+		g.Expect(err).ShouldNot(HaveOccurred(), tc.name)
+		g.Expect(objs).Should(HaveLen(2), tc.name)
+		g.Expect(findObject(objs, "ServiceAccount", "antrea-custom")).ShouldNot(BeNil(), tc.name)
+		g.Expect(findObject(objs, "DaemonSet", types.AntreaAgentDaemonSetName)).Should(BeNil(), tc.name)
+	}
+}
@@ -7,22 +7,30 @@ import (
 	"flag"
 	"fmt"
 	"os"
+	"strconv"
+	// Embed the time zone database for the maintenance windows.
+	_ "time/tzdata"
 
 	configv1 "github.com/openshift/api/config/v1"
 	ocoperv1 "github.com/openshift/api/operator/v1"
 	cnoclient "github.com/openshift/cluster-network-operator/pkg/client"
 	"github.com/openshift/cluster-network-operator/pkg/names"
+	"go.uber.org/zap/zapcore"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	_ "k8s.io/client-go/plugin/pkg/client/auth/gcp"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
 
+	configv1alpha1 "github.com/vmware/antrea-operator-for-kubernetes/api/config/v1alpha1"
 	operatorv1 "github.com/vmware/antrea-operator-for-kubernetes/api/v1"
 	"github.com/vmware/antrea-operator-for-kubernetes/controllers"
+	"github.com/vmware/antrea-operator-for-kubernetes/controllers/health"
+	"github.com/vmware/antrea-operator-for-kubernetes/controllers/manifestsource"
 	"github.com/vmware/antrea-operator-for-kubernetes/controllers/sharedinfo"
 	"github.com/vmware/antrea-operator-for-kubernetes/controllers/statusmanager"
 	"github.com/vmware/antrea-operator-for-kubernetes/controllers/types"
+	"github.com/vmware/antrea-operator-for-kubernetes/internal/cmd"
 	"github.com/vmware/antrea-operator-for-kubernetes/internal/version"
 	// +kubebuilder:scaffold:imports
 )
@@ -38,18 +46,51 @@ func init() {
 	utilruntime.Must(ocoperv1.Install(scheme))
 
 	utilruntime.Must(operatorv1.AddToScheme(scheme))
+	utilruntime.Must(configv1alpha1.AddToScheme(scheme))
 	// +kubebuilder:scaffold:scheme
 }
 
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == "render" {
+		os.Exit(cmd.RunRender(os.Args[2:], os.Stdout, os.Stderr))
+	}
+	if len(os.Args) > 1 && os.Args[1] == "diff" {
+		os.Exit(cmd.RunDiff(os.Args[2:], os.Stdout, os.Stderr))
+	}
+	if len(os.Args) > 1 && os.Args[1] == "manifest-digest" {
+		os.Exit(cmd.RunManifestDigest(os.Args[2:], os.Stdout, os.Stderr))
+	}
+
 	var printVersion bool
+	var configFile string
 	var metricsAddr string
+	var probeAddr string
 	var enableLeaderElection bool
+	var logLevel string
+	var logFormat string
+	var enableWebhooks bool
+	var watchNamespace string
+	var antreaInstallName string
+	var manifestDir string
+	var manifestCacheDir string
 	flag.BoolVar(&printVersion, "version", false, "Show version and exit")
-	flag.StringVar(&metricsAddr, "metrics-addr", "0", "The address the metric endpoint binds to.")
+	flag.StringVar(&configFile, "config", "",
+		"The operator configuration file. "+
+			"The flags which are set override the values of the file.")
+	flag.StringVar(&metricsAddr, "metrics-addr", configv1alpha1.DefaultMetricsBindAddress, "The address the metric endpoint binds to.")
+	flag.StringVar(&probeAddr, "health-probe-bind-address", configv1alpha1.DefaultHealthProbeBindAddress, "The address the health probe endpoint binds to.")
 	flag.BoolVar(&enableLeaderElection, "enable-leader-election", false,
 		"Enable leader election for controller manager. "+
 			"Enabling this will ensure there is only one active controller manager.")
+	flag.StringVar(&logLevel, "log-level", configv1alpha1.LogLevelDebug, "The log level: debug, info, error or an integer verbosity greater than 0.")
+	flag.StringVar(&logFormat, "log-format", configv1alpha1.LogFormatConsole, "The log format: json or console.")
+	flag.BoolVar(&enableWebhooks, "enable-webhooks", false,
+		"Serve the admission webhooks of the AntreaInstall CR. "+
+			"The serving certificate must be mounted in the certificate directory of the operator configuration.")
+	flag.StringVar(&watchNamespace, "watch-namespace", configv1alpha1.DefaultWatchNamespace, "The namespace of the AntreaInstall CR.")
+	flag.StringVar(&antreaInstallName, "antrea-install-name", configv1alpha1.DefaultAntreaInstallName, "The name of the AntreaInstall CR.")
+	flag.StringVar(&manifestDir, "manifest-dir", configv1alpha1.DefaultManifestDir, "The directory of the Antrea manifest templates, with one subdirectory per Antrea version.")
+	flag.StringVar(&manifestCacheDir, "manifest-cache-dir", configv1alpha1.DefaultManifestCacheDir(), "The writable directory in which the templates of spec.manifestSource are cached.")
 	flag.Parse()
 
 	if printVersion {
@@ -57,36 +98,88 @@ func main() {
 		os.Exit(0)
 	}
 
-	ctrl.SetLogger(zap.New(zap.UseDevMode(true)))
+	operatorConfig, err := loadOperatorConfig(configFile)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "unable to load operator configuration: %v\n", err)
+		os.Exit(1)
+	}
+	operatorConfig.Default()
+	flag.Visit(func(f *flag.Flag) {
+		switch f.Name {
+		case "metrics-addr":
+			operatorConfig.Metrics.BindAddress = metricsAddr
+		case "health-probe-bind-address":
+			operatorConfig.Health.HealthProbeBindAddress = probeAddr
+		case "enable-leader-election":
+			operatorConfig.LeaderElection.LeaderElect = &enableLeaderElection
+		case "log-level":
+			operatorConfig.Logging.Level = logLevel
+		case "log-format":
+			operatorConfig.Logging.Format = logFormat
+		case "watch-namespace":
+			operatorConfig.WatchNamespace = watchNamespace
+		case "antrea-install-name":
+			operatorConfig.AntreaInstallName = antreaInstallName
+		case "manifest-dir":
+			operatorConfig.ManifestDir = manifestDir
+		case "manifest-cache-dir":
+			operatorConfig.ManifestCacheDir = manifestCacheDir
+		}
+	})
+	if err := operatorConfig.Validate(); err != nil {
+		fmt.Fprintf(os.Stderr, "invalid operator configuration: %v\n", err)
+		os.Exit(1)
+	}
+	if info, err := os.Stat(operatorConfig.ManifestDir); err != nil || !info.IsDir() {
+		fmt.Fprintf(os.Stderr, "invalid operator configuration: manifest directory %s is not accessible\n", operatorConfig.ManifestDir)
+		os.Exit(1)
+	}
+	if err := os.MkdirAll(operatorConfig.ManifestCacheDir, 0755); err != nil {
+		fmt.Fprintf(os.Stderr, "invalid operator configuration: manifest cache directory %s cannot be created: %v\n", operatorConfig.ManifestCacheDir, err)
+		os.Exit(1)
+	}
+
+	ctrl.SetLogger(zap.New(loggerOptions(operatorConfig.Logging)...))
 
+	types.OperatorNameSpace = operatorConfig.WatchNamespace
+	types.OperatorConfigName = operatorConfig.AntreaInstallName
+	types.ManifestDir = operatorConfig.ManifestDir
+	manifestsource.CacheDir = operatorConfig.ManifestCacheDir
+	controllers.ResyncPeriod = operatorConfig.ResyncPeriod.Duration
+	statusmanager.ProgressTimeout = operatorConfig.ProgressTimeout.Duration
+
+	options, err := ctrl.Options{Scheme: scheme}.AndFrom(operatorConfig)
+	if err != nil {
+		setupLog.Error(err, "unable to apply operator configuration")
+		os.Exit(1)
+	}
 	cfg := ctrl.GetConfigOrDie()
-	mgr, err := ctrl.NewManager(cfg, ctrl.Options{
-		Scheme:             scheme,
-		MetricsBindAddress: metricsAddr,
-		Port:               9443,
-		LeaderElection:     enableLeaderElection,
-		LeaderElectionID:   "antrea-operator.antrea.vmware.com",
-	})
+	mgr, err := ctrl.NewManager(cfg, options)
 	if err != nil {
 		setupLog.Error(err, "unable to start manager")
 		os.Exit(1)
 	}
-	sharedInfo, err := sharedinfo.New(mgr)
+	checker, err := health.NewChecker(mgr, operatorConfig.ReconcileDeadline.Duration, enableWebhooks)
 	if err != nil {
-		setupLog.Error(err, "unable to get shareinfo")
+		setupLog.Error(err, "unable to create health checker")
 		os.Exit(1)
 	}
-	statusManager, err := statusmanager.New(mgr.GetClient(), mgr.GetRESTMapper(), types.AntreaClusterOperatorName, types.OperatorNameSpace, version.GetVersion(), sharedInfo)
-	if err != nil {
-		setupLog.Error(err, "unable to get status manager")
+	if err := mgr.AddHealthzCheck("reconcile", checker.Healthz); err != nil {
+		setupLog.Error(err, "unable to set up health check")
 		os.Exit(1)
 	}
+	if err := mgr.AddReadyzCheck("operator", checker.Readyz); err != nil {
+		setupLog.Error(err, "unable to set up ready check")
+		os.Exit(1)
+	}
+	sharedInfo := sharedinfo.New()
+	statusManager := statusmanager.New(mgr.GetClient(), mgr.GetRESTMapper(), mgr.GetEventRecorderFor("antrea-operator"), types.AntreaClusterOperatorName, types.OperatorNameSpace, version.GetVersion())
 	cnoClient, err := cnoclient.NewClient(cfg, cfg, names.DefaultClusterName, nil)
 	if err != nil {
 		setupLog.Error(err, "fail to create client")
 		os.Exit(1)
 	}
-	controller, err := controllers.New(mgr, statusManager, sharedInfo, cnoClient)
+	controller, err := controllers.New(mgr, statusManager, sharedInfo, cnoClient, checker)
 	if err != nil {
 		setupLog.Error(err, "unable to get controller")
 		os.Exit(1)
@@ -95,6 +188,17 @@ func main() {
 		setupLog.Error(err, "unable to create controller", "controller", "AntreaInstall")
 		os.Exit(1)
 	}
+	if enableWebhooks {
+		validator, err := controllers.NewAntreaInstallValidator(mgr)
+		if err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "AntreaInstall")
+			os.Exit(1)
+		}
+		if err = validator.SetupWebhookWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "AntreaInstall")
+			os.Exit(1)
+		}
+	}
 	// +kubebuilder:scaffold:builder
 	if err = (&controllers.PodReconciler{
 		Client:     cnoClient,
@@ -102,6 +206,7 @@ func main() {
 		Scheme:     mgr.GetScheme(),
 		Status:     statusManager,
 		SharedInfo: sharedInfo,
+		Health:     checker,
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "AntreaInstall")
 		os.Exit(1)
@@ -113,3 +218,41 @@ func main() {
 		os.Exit(1)
 	}
 }
+
+// loadOperatorConfig reads the operator configuration file at path, if any.
+func loadOperatorConfig(path string) (*configv1alpha1.OperatorConfiguration, error) {
+	operatorConfig := &configv1alpha1.OperatorConfiguration{}
+	if path == "" {
+		return operatorConfig, nil
+	}
+	loader := ctrl.ConfigFile().AtPath(path).OfKind(operatorConfig)
+	if err := loader.InjectScheme(scheme); err != nil {
+		return nil, err
+	}
+	if _, err := loader.Complete(); err != nil {
+		return nil, err
+	}
+	return operatorConfig, nil
+}
+
+// loggerOptions converts a validated logging configuration to zap options.
+func loggerOptions(logging configv1alpha1.LoggingConfiguration) []zap.Opts {
+	opts := []zap.Opts{zap.UseDevMode(*logging.Development)}
+	switch logging.Level {
+	case configv1alpha1.LogLevelDebug:
+		opts = append(opts, zap.Level(zapcore.DebugLevel))
+	case configv1alpha1.LogLevelInfo:
+		opts = append(opts, zap.Level(zapcore.InfoLevel))
+	case configv1alpha1.LogLevelError:
+		opts = append(opts, zap.Level(zapcore.ErrorLevel))
+	default:
+		verbosity, _ := strconv.Atoi(logging.Level)
+		opts = append(opts, zap.Level(zapcore.Level(-verbosity)))
+	}
+	if logging.Format == configv1alpha1.LogFormatJSON {
+		opts = append(opts, zap.JSONEncoder())
+	} else {
+		opts = append(opts, zap.ConsoleEncoder())
+	}
+	return opts
+}
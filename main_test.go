@@ -0,0 +1,69 @@
+/* Copyright © 2026 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"go.uber.org/zap/zapcore"
+	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+
+	configv1alpha1 "github.com/vmware/antrea-operator-for-kubernetes/api/config/v1alpha1"
+)
+
+func TestLoadOperatorConfig(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	// Without file, the defaults apply.
+	operatorConfig, err := loadOperatorConfig("")
+	g.Expect(err).ShouldNot(HaveOccurred())
+	g.Expect(*operatorConfig).Should(Equal(configv1alpha1.OperatorConfiguration{}))
+
+	// The shipped configuration is valid.
+	operatorConfig, err = loadOperatorConfig("config/manager/operator_config.yaml")
+	g.Expect(err).ShouldNot(HaveOccurred())
+	operatorConfig.Default()
+	g.Expect(operatorConfig.Validate()).Should(Succeed())
+	g.Expect(*operatorConfig.LeaderElection.LeaderElect).Should(BeTrue())
+	g.Expect(operatorConfig.Logging.Format).Should(Equal(configv1alpha1.LogFormatJSON))
+	g.Expect(operatorConfig.WatchNamespace).Should(Equal(configv1alpha1.DefaultWatchNamespace))
+
+	path := filepath.Join(t.TempDir(), "operator_config.yaml")
+	g.Expect(os.WriteFile(path, []byte("apiVersion: config.operator.antrea.vmware.com/v1alpha1\nkind: Unknown\n"), 0644)).Should(Succeed())
+	_, err = loadOperatorConfig(path)
+	g.Expect(err).Should(HaveOccurred())
+	_, err = loadOperatorConfig(filepath.Join(t.TempDir(), "missing.yaml"))
+	g.Expect(err).Should(HaveOccurred())
+}
+
+func TestLoggerOptions(t *testing.T) {
+	g := NewGomegaWithT(t)
+	development := false
+	for _, tc := range []struct {
+		level         string
+		format        string
+		expectedLevel zapcore.Level
+		expectedJSON  bool
+	}{
+		{level: configv1alpha1.LogLevelDebug, format: configv1alpha1.LogFormatConsole, expectedLevel: zapcore.DebugLevel},
+		{level: configv1alpha1.LogLevelInfo, format: configv1alpha1.LogFormatJSON, expectedLevel: zapcore.InfoLevel, expectedJSON: true},
+		{level: configv1alpha1.LogLevelError, format: configv1alpha1.LogFormatConsole, expectedLevel: zapcore.ErrorLevel},
+		{level: "3", format: configv1alpha1.LogFormatJSON, expectedLevel: zapcore.Level(-3), expectedJSON: true},
+	} {
+		opts := &zap.Options{}
+		for _, opt := range loggerOptions(configv1alpha1.LoggingConfiguration{Level: tc.level, Format: tc.format, Development: &development}) {
+			opt(opts)
+		}
+		g.Expect(opts.Development).Should(BeFalse(), tc.level)
+		g.Expect(opts.Level.Enabled(tc.expectedLevel)).Should(BeTrue(), tc.level)
+		g.Expect(opts.Level.Enabled(tc.expectedLevel-1)).Should(BeFalse(), tc.level)
+		buf, err := opts.Encoder.EncodeEntry(zapcore.Entry{Message: "test"}, nil)
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(strings.HasPrefix(buf.String(), "{")).Should(Equal(tc.expectedJSON), tc.format)
+	}
+}